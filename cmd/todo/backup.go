@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/service"
+	"task-recommender/internal/view"
+	"task-recommender/pkg/db"
+	"task-recommender/pkg/storage"
+)
+
+// withBlobController 添付ファイルの保存先を設定したコントローラーを渡すヘルパー関数
+func withBlobController(c *cli.Context, fn func(*controller.TaskController) error) error {
+	database, err := db.Connect()
+	if err != nil {
+		return fmt.Errorf("データベース接続エラー: %w", err)
+	}
+	defer database.Close()
+
+	blobStore, err := storage.FromEnv()
+	if err != nil {
+		return fmt.Errorf("添付ファイルの保存先の初期化エラー: %w", err)
+	}
+
+	taskService := service.NewTaskService(database).
+		WithActor(c.String("user")).
+		WithBlobStore(blobStore, service.DefaultAttachmentMaxBytes)
+	return fn(controller.NewTaskController(taskService))
+}
+
+func backupCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "backup",
+		Usage: "すべてのタスクと関連データを圧縮したJSONファイルに書き出す",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "出力先のファイル（省略時は backup-日時.json.gz、\"-\" は標準出力）",
+			},
+			&cli.BoolFlag{Name: "no-attachments", Usage: "添付ファイルの内容を含めない"},
+		},
+		Action: func(c *cli.Context) error {
+			path := c.String("output")
+			if path == "" {
+				path = "backup-" + time.Now().Format("20060102-150405") + ".json.gz"
+			}
+
+			return withBlobController(c, func(ctrl *controller.TaskController) error {
+				out, err := openOutput(path)
+				if err != nil {
+					return err
+				}
+				defer out.Close()
+
+				summary, err := ctrl.Backup(out, !c.Bool("no-attachments"))
+				if err != nil {
+					return err
+				}
+				if err := out.Close(); err != nil {
+					return err
+				}
+				if path != "-" {
+					view.PrintBackupSummary("バックアップ: "+path, summary)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func restoreBackupCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "restore-backup",
+		Usage:     "backup で書き出したファイルからデータを復元する",
+		ArgsUsage: "<ファイル | ->",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "force", Usage: "既存のデータをすべて消して復元する"},
+			&cli.BoolFlag{Name: "init", Usage: "復元の前にテーブルを作り直す（既存のデータは消える）"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("ファイルを指定してください")
+			}
+			in, err := openInput(c.Args().First())
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			if c.Bool("init") {
+				database, err := db.Connect()
+				if err != nil {
+					return fmt.Errorf("データベース接続エラー: %w", err)
+				}
+				err = db.ResetDatabase(database)
+				database.Close()
+				if err != nil {
+					return fmt.Errorf("データベース初期化エラー: %w", err)
+				}
+			}
+
+			return withBlobController(c, func(ctrl *controller.TaskController) error {
+				summary, err := ctrl.Restore(in, c.Bool("force"))
+				if err != nil {
+					return err
+				}
+				view.PrintBackupSummary("復元", summary)
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,651 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/model"
+	"task-recommender/internal/quickadd"
+	"task-recommender/internal/service"
+	"task-recommender/internal/view"
+	"task-recommender/pkg/db"
+)
+
+// withController データベースに接続してコントローラーを渡すヘルパー関数
+func withController(c *cli.Context, fn func(*controller.TaskController) error) error {
+	database, err := db.Connect()
+	if err != nil {
+		return fmt.Errorf("データベース接続エラー: %w", err)
+	}
+	defer database.Close()
+
+	taskService := service.NewTaskService(database).WithActor(c.String("user"))
+	return fn(controller.NewTaskController(taskService))
+}
+
+// ifVersionFlag 楽観的排他制御のためのフラグ
+func ifVersionFlag() cli.Flag {
+	return &cli.IntFlag{Name: "if-version", Usage: "タスクのバージョンがこの値の場合のみ更新する（show で確認できる）"}
+}
+
+// withVersionedController --if-version を反映したコントローラーを渡すヘルパー関数
+//
+// 他のユーザーが先に更新していた場合は、上書きせずに現在のタスクの内容を表示する
+func withVersionedController(c *cli.Context, id int, fn func(*controller.TaskController) error) error {
+	return withController(c, func(ctrl *controller.TaskController) error {
+		err := fn(ctrl.IfVersion(c.Int("if-version")))
+		if errors.Is(err, service.ErrVersionConflict) {
+			if current, gerr := ctrl.GetTask(id); gerr == nil {
+				view.PrintVersionConflict(current)
+			}
+		}
+		return err
+	})
+}
+
+// taskIDArg 位置引数からタスクIDを取得するヘルパー関数
+func taskIDArg(c *cli.Context, n int) (int, error) {
+	if c.NArg() <= n {
+		return 0, fmt.Errorf("タスクIDを指定してください")
+	}
+	id, err := strconv.Atoi(c.Args().Get(n))
+	if err != nil {
+		return 0, fmt.Errorf("タスクIDが不正です: %s", c.Args().Get(n))
+	}
+	return id, nil
+}
+
+// intArg 位置引数から整数を取得するヘルパー関数
+func intArg(c *cli.Context, n int, name string) (int, error) {
+	if c.NArg() <= n {
+		return 0, fmt.Errorf("%sを指定してください", name)
+	}
+	v, err := strconv.Atoi(c.Args().Get(n))
+	if err != nil {
+		return 0, fmt.Errorf("%sが不正です: %s", name, c.Args().Get(n))
+	}
+	return v, nil
+}
+
+// parseDate YYYY-MM-DD形式の日付を解析するヘルパー関数
+func parseDate(s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日付の形式が不正です。YYYY-MM-DD形式で指定してください")
+	}
+	return t, nil
+}
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "タスク一覧を表示する",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Value:   view.DefaultFormat,
+				Usage:   "出力形式 (" + strings.Join(view.Formats(), ", ") + ")",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return withController(c, func(ctrl *controller.TaskController) error {
+				tasks, err := ctrl.ListTasks()
+				if err != nil {
+					return err
+				}
+				return view.RenderTaskList(c.String("format"), tasks.([]model.Task))
+			})
+		},
+	}
+}
+
+func showCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "show",
+		Usage:     "タスクの詳細とバージョンを表示する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				task, err := ctrl.GetTask(id)
+				if err != nil {
+					return err
+				}
+				view.PrintTask(task)
+				return nil
+			})
+		},
+	}
+}
+
+func addCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "add",
+		Usage: "タスクを追加する",
+		Description: "タイトルに書いた優先度や期限日、見積時間も読み取る（--raw で無効）。例:\n" +
+			"  todo add 明日までにレポート提出 !高 90分\n" +
+			"  todo add submit report next friday p3 1h30m +work #office\n" +
+			"フラグで指定した項目はタイトルから読み取った値より優先する",
+		ArgsUsage: "<タイトル>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "description", Aliases: []string{"d"}, Usage: "タスクの説明"},
+			&cli.IntFlag{Name: "priority", Aliases: []string{"p"}, Value: quickadd.DefaultPriority, Usage: "優先度 (1=低, 2=中, 3=高)"},
+			&cli.StringFlag{Name: "due", Usage: "期限日 (YYYY-MM-DD)"},
+			&cli.IntFlag{Name: "duration", Usage: "見積所要時間（分）"},
+			&cli.BoolFlag{Name: "raw", Usage: "タイトルを解釈せずにそのまま使う"},
+		},
+		Action: func(c *cli.Context) error {
+			text := strings.Join(c.Args().Slice(), " ")
+			if text == "" {
+				return fmt.Errorf("タイトルを指定してください")
+			}
+
+			task := model.Task{Title: text, Priority: c.Int("priority")}
+			if !c.Bool("raw") {
+				task = quickadd.Parse(text, time.Now()).Task()
+			}
+			if task.Title == "" {
+				return fmt.Errorf("タイトルを指定してください")
+			}
+			task.Description = c.String("description")
+			if c.IsSet("priority") {
+				task.Priority = c.Int("priority")
+			}
+			if c.IsSet("duration") {
+				task.EstimatedDuration = c.Int("duration")
+			}
+			if c.String("due") != "" {
+				dueDate, err := parseDate(c.String("due"))
+				if err != nil {
+					return err
+				}
+				task.DueDate = dueDate
+			}
+
+			return withController(c, func(ctrl *controller.TaskController) error {
+				id, err := ctrl.CreateTask(task)
+				if err != nil {
+					return err
+				}
+				view.PrintTaskQuickAdded(id, task)
+				return nil
+			})
+		},
+	}
+}
+
+func doneCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "done",
+		Usage:     "タスクを完了にする",
+		ArgsUsage: "<タスクID>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.CompleteTask(id); err != nil {
+					return err
+				}
+				view.PrintTaskCompleted(id)
+				return nil
+			})
+		},
+	}
+}
+
+func undoCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "undo",
+		Usage:     "完了したタスクを未着手に戻す",
+		ArgsUsage: "<タスクID>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.ReopenTask(id); err != nil {
+					return err
+				}
+				view.PrintTaskReopened(id)
+				return nil
+			})
+		},
+	}
+}
+
+func statusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "タスクの状態を遷移させる (todo, in_progress, blocked, done, cancelled)",
+		ArgsUsage: "<タスクID> <状態>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			status, err := model.ParseStatus(c.Args().Get(1))
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.TransitionStatus(id, status); err != nil {
+					return err
+				}
+				view.PrintStatusUpdated(id, status)
+				return nil
+			})
+		},
+	}
+}
+
+func transitionsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "transitions",
+		Usage:     "タスクの状態遷移の履歴を表示する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				transitions, err := ctrl.ListStatusTransitions(id)
+				if err != nil {
+					return err
+				}
+				view.PrintStatusTransitions(transitions)
+				return nil
+			})
+		},
+	}
+}
+
+func historyCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "history",
+		Usage:     "タスクの変更履歴を表示する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				entries, err := ctrl.ListHistory(id)
+				if err != nil {
+					return err
+				}
+				view.PrintHistory(entries)
+				return nil
+			})
+		},
+	}
+}
+
+func commentsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "comments",
+		Usage:     "タスクのコメントを表示する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				comments, err := ctrl.ListComments(id)
+				if err != nil {
+					return err
+				}
+				view.PrintComments(comments)
+				return nil
+			})
+		},
+	}
+}
+
+func commentCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "comment",
+		Usage:     "タスクにコメントを投稿する",
+		ArgsUsage: "<タスクID> <本文>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			body := strings.Join(c.Args().Tail(), " ")
+			return withController(c, func(ctrl *controller.TaskController) error {
+				comment, err := ctrl.AddComment(id, body)
+				if err != nil {
+					return err
+				}
+				view.PrintCommentAdded(comment)
+				return nil
+			})
+		},
+	}
+}
+
+func checklistCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "checklist",
+		Usage: "タスクのチェックリストを操作する",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "list",
+				Usage:     "チェックリストを表示する",
+				ArgsUsage: "<タスクID>",
+				Action: func(c *cli.Context) error {
+					id, err := taskIDArg(c, 0)
+					if err != nil {
+						return err
+					}
+					return withController(c, func(ctrl *controller.TaskController) error {
+						items, err := ctrl.ListChecklist(id)
+						if err != nil {
+							return err
+						}
+						view.PrintChecklist(items)
+						return nil
+					})
+				},
+			},
+			{
+				Name:      "add",
+				Usage:     "チェックリストに項目を追加する",
+				ArgsUsage: "<タスクID> <内容>",
+				Action: func(c *cli.Context) error {
+					id, err := taskIDArg(c, 0)
+					if err != nil {
+						return err
+					}
+					title := strings.Join(c.Args().Tail(), " ")
+					return withController(c, func(ctrl *controller.TaskController) error {
+						item, err := ctrl.AddChecklistItem(id, title)
+						if err != nil {
+							return err
+						}
+						view.PrintChecklistItemAdded(item)
+						return nil
+					})
+				},
+			},
+			{
+				Name:      "toggle",
+				Usage:     "チェックリストの項目の完了状態を切り替える",
+				ArgsUsage: "<タスクID> <項目ID>",
+				Action: func(c *cli.Context) error {
+					id, err := taskIDArg(c, 0)
+					if err != nil {
+						return err
+					}
+					itemID, err := intArg(c, 1, "項目ID")
+					if err != nil {
+						return err
+					}
+					return withController(c, func(ctrl *controller.TaskController) error {
+						item, err := ctrl.ToggleChecklistItem(id, itemID)
+						if err != nil {
+							return err
+						}
+						view.PrintChecklistItemToggled(item)
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
+
+func nextCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "next",
+		Usage: "次に取り組むべきタスクを表示する",
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Value: service.DefaultRecommendationLimit, Usage: "表示件数"},
+		},
+		Action: func(c *cli.Context) error {
+			return withController(c, func(ctrl *controller.TaskController) error {
+				recommendations, err := ctrl.RecommendTasks(c.Int("limit"))
+				if err != nil {
+					return err
+				}
+				view.PrintRecommendations(recommendations)
+				return nil
+			})
+		},
+	}
+}
+
+func deleteCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "delete",
+		Usage:     "タスクを削除する",
+		ArgsUsage: "<タスクID>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.DeleteTask(id); err != nil {
+					return err
+				}
+				view.PrintTaskDeleted(id)
+				return nil
+			})
+		},
+	}
+}
+
+func trashCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "trash",
+		Usage: "ゴミ箱のタスク一覧を表示する",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{Name: "purge", Usage: "指定した期間より前に削除したタスクを完全に削除する (例: 720h)"},
+		},
+		Action: func(c *cli.Context) error {
+			return withController(c, func(ctrl *controller.TaskController) error {
+				if c.IsSet("purge") {
+					n, err := ctrl.PurgeTrash(time.Now().Add(-c.Duration("purge")))
+					if err != nil {
+						return err
+					}
+					view.PrintTrashPurged(n)
+					return nil
+				}
+
+				tasks, err := ctrl.ListTrash()
+				if err != nil {
+					return err
+				}
+				view.PrintTrashList(tasks)
+				return nil
+			})
+		},
+	}
+}
+
+func restoreCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "restore",
+		Usage:     "ゴミ箱のタスクを元に戻す",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				if err := ctrl.RestoreTask(id); err != nil {
+					return err
+				}
+				view.PrintTaskRestored(id)
+				return nil
+			})
+		},
+	}
+}
+
+func priorityCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "priority",
+		Usage:     "タスクの優先度を更新する",
+		ArgsUsage: "<タスクID> <優先度>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			priority, err := intArg(c, 1, "優先度")
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.UpdatePriority(id, priority); err != nil {
+					return err
+				}
+				view.PrintPriorityUpdated(id, priority)
+				return nil
+			})
+		},
+	}
+}
+
+func dueCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "due",
+		Usage:     "タスクの期限日を更新する",
+		ArgsUsage: "<タスクID> <YYYY-MM-DD>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			dueDate, err := parseDate(c.Args().Get(1))
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.UpdateDueDate(id, dueDate); err != nil {
+					return err
+				}
+				view.PrintDueDateUpdated(id, dueDate)
+				return nil
+			})
+		},
+	}
+}
+
+func durationCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "duration",
+		Usage:     "タスクの見積所要時間を更新する",
+		ArgsUsage: "<タスクID> <分>",
+		Flags:     []cli.Flag{ifVersionFlag()},
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			duration, err := intArg(c, 1, "見積時間")
+			if err != nil {
+				return err
+			}
+			return withVersionedController(c, id, func(ctrl *controller.TaskController) error {
+				if err := ctrl.UpdateEstimatedDuration(id, duration); err != nil {
+					return err
+				}
+				view.PrintDurationUpdated(id, duration)
+				return nil
+			})
+		},
+	}
+}
+
+func startCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "start",
+		Usage:     "タスクの時間計測を開始する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				entry, err := ctrl.StartTimer(id)
+				if err != nil {
+					return err
+				}
+				view.PrintTimerStarted(entry)
+				return nil
+			})
+		},
+	}
+}
+
+func stopCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "stop",
+		Usage:     "タスクの時間計測を停止する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				entry, err := ctrl.StopTimer(id)
+				if err != nil {
+					return err
+				}
+				view.PrintTimerStopped(entry)
+				return nil
+			})
+		},
+	}
+}
+
+func timeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "time",
+		Usage:     "タスクの時間記録を表示する",
+		ArgsUsage: "<タスクID>",
+		Action: func(c *cli.Context) error {
+			id, err := taskIDArg(c, 0)
+			if err != nil {
+				return err
+			}
+			return withController(c, func(ctrl *controller.TaskController) error {
+				entries, err := ctrl.ListTimeEntries(id)
+				if err != nil {
+					return err
+				}
+				view.PrintTimeEntries(entries)
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/digest"
+	"task-recommender/internal/model"
+	"task-recommender/internal/notify"
+	"task-recommender/internal/view"
+)
+
+// digestFlags 毎朝のまとめの設定
+func digestFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "digest-recipient",
+			Usage:   "毎朝のまとめを送るユーザーと宛先（例: yamada=yamada@example.com）",
+			EnvVars: []string{"DIGEST_RECIPIENTS"},
+		},
+		&cli.StringFlag{
+			Name:    "digest-time",
+			Usage:   "毎朝のまとめを送る時刻 (HH:MM)",
+			Value:   "07:00",
+			EnvVars: []string{"DIGEST_TIME"},
+		},
+		timezoneFlag(),
+	}
+}
+
+// timezoneFlag まとめのタイムゾーン。serve と digest で共通
+func timezoneFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "digest-timezone",
+		Usage:   "まとめの時刻と日付の区切りに使うタイムゾーン（例: Asia/Tokyo。省略時はサーバーのタイムゾーン）",
+		EnvVars: []string{"DIGEST_TIMEZONE"},
+	}
+}
+
+// digestLocation まとめに使うタイムゾーン
+func digestLocation(c *cli.Context) (*time.Location, error) {
+	name := c.String("digest-timezone")
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("タイムゾーンが不正です: %w", err)
+	}
+	return loc, nil
+}
+
+// digestRecipientsFromFlags user=address 形式の受信者を読み込む
+func digestRecipientsFromFlags(c *cli.Context) ([]model.DigestRecipient, error) {
+	var recipients []model.DigestRecipient
+	for _, s := range c.StringSlice("digest-recipient") {
+		user, email, ok := strings.Cut(s, "=")
+		user, email = strings.TrimSpace(user), strings.TrimSpace(email)
+		if !ok || user == "" || email == "" {
+			return nil, fmt.Errorf("まとめの受信者が不正です。ユーザー=宛先 の形式で指定してください: %s", s)
+		}
+		recipients = append(recipients, model.DigestRecipient{User: user, Email: email})
+	}
+	return recipients, nil
+}
+
+// sendDigestsDaily 毎日、指定した時刻を過ぎたらその日のまとめを送る
+//
+// 送信済みかどうかはデータベースに記録するため、時刻を過ぎてから起動した場合もその日のうちは送る
+func sendDigestsDaily(taskController *controller.TaskController, mailer notify.Mailer, recipients []model.DigestRecipient, at time.Duration, loc *time.Location) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().In(loc)
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		if !now.Before(day.Add(at)) {
+			n, err := taskController.SendDigests(mailer, recipients, day)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "まとめの送信エラー: %v\n", err)
+			}
+			if n > 0 {
+				fmt.Printf("まとめを%d件送りました\n", n)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// parseClock HH:MM 形式の時刻を0時からの経過時間に変換する
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("時刻の形式が不正です。HH:MM形式で指定してください: %s", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func digestCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "digest",
+		Usage: "毎朝のまとめを表示する、またはメールで送る",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "date", Usage: "まとめの対象日 (YYYY-MM-DD、省略時は今日)"},
+			&cli.BoolFlag{Name: "html", Usage: "HTMLで表示する"},
+			&cli.StringFlag{Name: "to", Usage: "表示する代わりにこの宛先へメールで送る（--smtp-addr が必要）"},
+			timezoneFlag(),
+		}, smtpFlags()...),
+		Action: func(c *cli.Context) error {
+			loc, err := digestLocation(c)
+			if err != nil {
+				return err
+			}
+			now := time.Now().In(loc)
+			day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+			if s := c.String("date"); s != "" {
+				day, err = time.ParseInLocation("2006-01-02", s, loc)
+				if err != nil {
+					return fmt.Errorf("日付の形式が不正です。YYYY-MM-DD形式で指定してください")
+				}
+			}
+
+			user := c.String("user")
+			return withController(c, func(ctrl *controller.TaskController) error {
+				d, err := ctrl.BuildDigest(user, day)
+				if err != nil {
+					return err
+				}
+
+				to := c.String("to")
+				if to == "" {
+					return view.PrintDigest(d, c.Bool("html"))
+				}
+				mailer, err := smtpFromFlags(c)
+				if err != nil {
+					return err
+				}
+				if mailer == nil {
+					return fmt.Errorf("--to を使うには --smtp-addr を指定してください")
+				}
+				text, html, err := digest.Render(d)
+				if err != nil {
+					return err
+				}
+				if err := mailer.Send([]string{to}, digest.Subject(d), text, html); err != nil {
+					return err
+				}
+				view.PrintDigestSent(user, to)
+				return nil
+			})
+		},
+	}
+}
@@ -2,49 +2,235 @@
 // @version 1.0
 // @description タスクの追加、一覧表示、完了マーク、削除などの機能を提供するAPI
 // @host task-recommender.onrender.com
-// @BasePath /
+// @BasePath /v1
 package main
 
 import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
 
 	_ "task-recommender/docs"
 	"task-recommender/internal/api"
 	"task-recommender/internal/controller"
+	"task-recommender/internal/rpc"
 	"task-recommender/internal/service"
+	"task-recommender/internal/view"
+	"task-recommender/internal/webhook"
 	"task-recommender/pkg/db"
+	"task-recommender/pkg/storage"
 )
 
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "10000"
+	app := &cli.App{
+		Name:  "task-recommender",
+		Usage: "タスク管理アプリケーション",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "user",
+				Usage:   "操作するユーザー名",
+				EnvVars: []string{"TODO_USER", "USER"},
+			},
+		},
+		DefaultCommand: "serve",
+		Commands: []*cli.Command{
+			serveCommand(),
+			listCommand(),
+			showCommand(),
+			addCommand(),
+			doneCommand(),
+			undoCommand(),
+			statusCommand(),
+			transitionsCommand(),
+			historyCommand(),
+			commentsCommand(),
+			commentCommand(),
+			checklistCommand(),
+			nextCommand(),
+			exportCommand(),
+			importCommand(),
+			syncCommand(),
+			backupCommand(),
+			restoreBackupCommand(),
+			deleteCommand(),
+			trashCommand(),
+			restoreCommand(),
+			priorityCommand(),
+			dueCommand(),
+			durationCommand(),
+			startCommand(),
+			stopCommand(),
+			timeCommand(),
+			watchCommand(),
+			digestCommand(),
+		},
 	}
 
-	// データベース接続
-	database, err := db.Connect()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "データベース接続エラー: %v\n", err)
+	if err := app.Run(os.Args); err != nil {
+		view.PrintError(err)
 		os.Exit(1)
 	}
-	defer database.Close()
+}
 
-	// データベース初期化
-	err = db.InitializeDatabase(database)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "データベース初期化エラー: %v\n", err)
-		os.Exit(1)
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "APIサーバーを起動する",
+		Flags: append([]cli.Flag{
+			&cli.DurationFlag{
+				Name:    "trash-retention",
+				Usage:   "ゴミ箱のタスクを完全に削除するまでの保持期間",
+				Value:   30 * 24 * time.Hour,
+				EnvVars: []string{"TRASH_RETENTION"},
+			},
+			&cli.Int64Flag{
+				Name:    "attachment-max-bytes",
+				Usage:   "添付ファイルの最大サイズ（バイト）",
+				Value:   service.DefaultAttachmentMaxBytes,
+				EnvVars: []string{"ATTACHMENT_MAX_BYTES"},
+			},
+			&cli.StringFlag{
+				Name:    "calendar-token",
+				Usage:   "カレンダー (/calendar.ics) 配信用のトークン。未指定の場合は配信しない",
+				EnvVars: []string{"CALENDAR_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:    "slack-signing-secret",
+				Usage:   "スラッシュコマンド (/slack/commands) の署名用の秘密鍵。未指定の場合は受け付けない",
+				EnvVars: []string{"SLACK_SIGNING_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:    "grpc-port",
+				Usage:   "gRPC (平文の HTTP/2) で待ち受けるポート。未指定の場合は起動しない",
+				EnvVars: []string{"GRPC_PORT"},
+			},
+			&cli.DurationFlag{
+				Name:    "webhook-interval",
+				Usage:   "Webhookの未送信イベントを送信する間隔",
+				Value:   10 * time.Second,
+				EnvVars: []string{"WEBHOOK_INTERVAL"},
+			},
+		}, append(notifyFlags(), digestFlags()...)...),
+		Action: func(c *cli.Context) error {
+			port := os.Getenv("PORT")
+			if port == "" {
+				port = "10000"
+			}
+
+			// データベース接続
+			database, err := db.Connect()
+			if err != nil {
+				return fmt.Errorf("データベース接続エラー: %w", err)
+			}
+			defer database.Close()
+
+			// 未適用のマイグレーションを実行（既存のデータは残す）
+			err = db.InitializeDatabase(database)
+			if err != nil {
+				return fmt.Errorf("データベース初期化エラー: %w", err)
+			}
+
+			// 添付ファイルの保存先
+			blobStore, err := storage.FromEnv()
+			if err != nil {
+				return fmt.Errorf("添付ファイルの保存先の初期化エラー: %w", err)
+			}
+
+			// サービスとコントローラーの初期化
+			taskService := service.NewTaskService(database).WithBlobStore(blobStore, c.Int64("attachment-max-bytes"))
+			taskController := controller.NewTaskController(taskService)
+
+			// ゴミ箱の定期削除
+			go purgeTrashPeriodically(taskController, c.Duration("trash-retention"), time.Hour)
+
+			// 期限のリマインダー
+			notifiers, leads, err := remindersFromFlags(c)
+			if err != nil {
+				return err
+			}
+			if len(notifiers) > 0 {
+				go sendRemindersPeriodically(taskController, notifiers, leads, c.Duration("reminder-interval"))
+			}
+
+			// 毎朝のまとめ
+			recipients, err := digestRecipientsFromFlags(c)
+			if err != nil {
+				return err
+			}
+			if len(recipients) > 0 {
+				mailer, err := smtpFromFlags(c)
+				if err != nil {
+					return err
+				}
+				if mailer == nil {
+					return fmt.Errorf("--digest-recipient を使うには --smtp-addr を指定してください")
+				}
+				at, err := parseClock(c.String("digest-time"))
+				if err != nil {
+					return err
+				}
+				loc, err := digestLocation(c)
+				if err != nil {
+					return err
+				}
+				go sendDigestsDaily(taskController, mailer, recipients, at, loc)
+			}
+
+			// Webhookの送信
+			go deliverWebhooksPeriodically(taskController, webhook.NewClient(10*time.Second), c.Duration("webhook-interval"))
+
+			// ルーターの設定
+			router := api.SetupRouter(taskController, api.Config{
+				CalendarToken:      c.String("calendar-token"),
+				SlackSigningSecret: c.String("slack-signing-secret"),
+			})
+
+			// どちらかのサーバーが停止したら終了する
+			errc := make(chan error, 2)
+			if grpcPort := c.String("grpc-port"); grpcPort != "" {
+				fmt.Printf("gRPCサーバーを起動しています: 0.0.0.0:%s\n", grpcPort)
+				go func() {
+					errc <- rpc.ListenAndServe("0.0.0.0:"+grpcPort, rpc.NewServer(taskController))
+				}()
+			}
+
+			fmt.Printf("サーバーを起動しています: 0.0.0.0:%s\n", port)
+			go func() {
+				errc <- http.ListenAndServe("0.0.0.0:"+port, router)
+			}()
+			return <-errc
+		},
 	}
+}
 
-	// サービスとコントローラーの初期化
-	taskService := service.NewTaskService(database)
-	taskController := controller.NewTaskController(taskService)
+// purgeTrashPeriodically 保持期間を過ぎたゴミ箱のタスクをintervalごとに完全に削除する
+func purgeTrashPeriodically(taskController *controller.TaskController, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	// ルーターの設定
-	router := api.SetupRouter(taskController)
+	for {
+		n, err := taskController.PurgeTrash(time.Now().Add(-retention))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ゴミ箱の削除エラー: %v\n", err)
+		} else if n > 0 {
+			fmt.Printf("ゴミ箱から%d件のタスクを完全に削除しました\n", n)
+		}
+		<-ticker.C
+	}
+}
 
-	fmt.Printf("サーバーを起動しています: 0.0.0.0:%s\n", port)
-	http.ListenAndServe("0.0.0.0:"+port, router)
+// deliverWebhooksPeriodically 送信待ちのWebhookをintervalごとに送信する
+func deliverWebhooksPeriodically(taskController *controller.TaskController, sender webhook.Sender, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := taskController.DeliverWebhooks(sender, 100); err != nil {
+			fmt.Fprintf(os.Stderr, "Webhookの送信エラー: %v\n", err)
+		}
+		<-ticker.C
+	}
 }
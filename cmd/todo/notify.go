@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/notify"
+)
+
+// notifyTimeout 通知先へのHTTPリクエストのタイムアウト
+const notifyTimeout = 10 * time.Second
+
+// smtpFlags メールを送るSMTPサーバーの設定
+func smtpFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "smtp-addr", Usage: "メールを送るSMTPサーバー (host:port)", EnvVars: []string{"SMTP_ADDR"}},
+		&cli.StringFlag{Name: "smtp-username", Usage: "SMTPの認証ユーザー名（省略時は認証しない）", EnvVars: []string{"SMTP_USERNAME"}},
+		&cli.StringFlag{Name: "smtp-password", Usage: "SMTPの認証パスワード", EnvVars: []string{"SMTP_PASSWORD"}},
+		&cli.StringFlag{Name: "smtp-from", Usage: "メールの送信元アドレス", EnvVars: []string{"SMTP_FROM"}},
+	}
+}
+
+// notifyFlags 通知とリマインダーの設定
+func notifyFlags() []cli.Flag {
+	return append(smtpFlags(),
+		&cli.StringSliceFlag{Name: "reminder-email-to", Usage: "リマインダーをメールで送る宛先", EnvVars: []string{"REMINDER_EMAIL_TO"}},
+		&cli.StringFlag{Name: "reminder-webhook-url", Usage: "リマインダーをJSONでPOSTするURL", EnvVars: []string{"REMINDER_WEBHOOK_URL"}},
+		&cli.StringFlag{Name: "reminder-slack-url", Usage: "リマインダーを送るSlackの Incoming Webhook のURL", EnvVars: []string{"REMINDER_SLACK_URL"}},
+		&cli.StringSliceFlag{
+			Name:    "reminder-lead",
+			Usage:   "期限のどれだけ前に知らせるか（例: 24h, 1h）",
+			Value:   cli.NewStringSlice("24h", "1h"),
+			EnvVars: []string{"REMINDER_LEADS"},
+		},
+		&cli.DurationFlag{
+			Name:    "reminder-interval",
+			Usage:   "期限の近いタスクを確認する間隔",
+			Value:   time.Minute,
+			EnvVars: []string{"REMINDER_INTERVAL"},
+		},
+	)
+}
+
+// smtpFromFlags SMTPの設定。SMTPサーバーが指定されていない場合はnilを返す
+func smtpFromFlags(c *cli.Context) (*notify.SMTP, error) {
+	if c.String("smtp-addr") == "" {
+		return nil, nil
+	}
+	if c.String("smtp-from") == "" {
+		return nil, fmt.Errorf("--smtp-from を指定してください")
+	}
+	return &notify.SMTP{
+		Addr:     c.String("smtp-addr"),
+		Username: c.String("smtp-username"),
+		Password: c.String("smtp-password"),
+		From:     c.String("smtp-from"),
+	}, nil
+}
+
+// remindersFromFlags リマインダーの通知先と知らせる時期を読み込む
+func remindersFromFlags(c *cli.Context) ([]notify.Notifier, []time.Duration, error) {
+	var notifiers []notify.Notifier
+	if to := c.StringSlice("reminder-email-to"); len(to) > 0 {
+		mailer, err := smtpFromFlags(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		if mailer == nil {
+			return nil, nil, fmt.Errorf("--reminder-email-to を使うには --smtp-addr を指定してください")
+		}
+		mailer.To = to
+		notifiers = append(notifiers, mailer)
+	}
+	if url := c.String("reminder-webhook-url"); url != "" {
+		notifiers = append(notifiers, notify.NewWebhook(url, notifyTimeout))
+	}
+	if url := c.String("reminder-slack-url"); url != "" {
+		notifiers = append(notifiers, notify.NewSlack(url, notifyTimeout))
+	}
+
+	var leads []time.Duration
+	for _, s := range c.StringSlice("reminder-lead") {
+		lead, err := time.ParseDuration(s)
+		if err != nil || lead <= 0 {
+			return nil, nil, fmt.Errorf("リマインダーの時期が不正です: %s", s)
+		}
+		leads = append(leads, lead)
+	}
+	return notifiers, leads, nil
+}
+
+// sendRemindersPeriodically 期限の近いタスクと期限切れのタスクのリマインダーをintervalごとに送る
+func sendRemindersPeriodically(taskController *controller.TaskController, notifiers []notify.Notifier, leads []time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := taskController.SendReminders(notifiers, leads, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "リマインダーの送信エラー: %v\n", err)
+		}
+		if n > 0 {
+			fmt.Printf("リマインダーを%d件送りました\n", n)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/model"
+	"task-recommender/internal/todotxt"
+	"task-recommender/internal/view"
+)
+
+func syncCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "sync",
+		Usage:     "todo.txtとデータベースを双方向に同期する",
+		ArgsUsage: "<todo.txt>",
+		Description: "前回の同期時点の内容を基準に、ファイルとデータベースの変更を行単位でマージします。\n" +
+			"行は id: の値（外部ID）で突き合わせ、id: のない行やタスクには新しいIDを割り当てます。",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "prefer", Value: todotxt.PreferDB, Usage: "両方で変更されていた場合に採用する側 (db, file)"},
+			&cli.StringFlag{Name: "state", Usage: "前回の同期時点の内容を保存するファイル（省略時は .<ファイル名>.sync）"},
+			&cli.BoolFlag{Name: "dry-run", Usage: "変更内容を表示するだけで保存しない"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("ファイルを指定してください")
+			}
+			path := c.Args().First()
+			statePath := c.String("state")
+			if statePath == "" {
+				statePath = filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".sync")
+			}
+			prefer := c.String("prefer")
+			if prefer != todotxt.PreferDB && prefer != todotxt.PreferFile {
+				return fmt.Errorf("--prefer には db または file を指定してください")
+			}
+			dryRun := c.Bool("dry-run")
+
+			local, err := readTodoTxt(path)
+			if err != nil {
+				return err
+			}
+			base, err := readTodoTxt(statePath)
+			if err != nil {
+				return err
+			}
+
+			return withController(c, func(ctrl *controller.TaskController) error {
+				tasks, err := ctrl.ListTasks()
+				if err != nil {
+					return err
+				}
+				remote := tasks.([]model.Task)
+				for i := range remote {
+					if remote[i].ExternalID != "" {
+						continue
+					}
+					remote[i].ExternalID = todotxt.NewID()
+					if dryRun {
+						continue
+					}
+					if err := ctrl.SetExternalID(remote[i].ID, remote[i].ExternalID); err != nil {
+						return err
+					}
+				}
+				for i := range local {
+					if local[i].ExternalID == "" {
+						local[i].ExternalID = todotxt.NewID()
+					}
+				}
+
+				m := todotxt.ThreeWayMerge(base, local, remote, prefer, time.Now())
+				m.Report.DryRun = dryRun
+				if dryRun {
+					view.PrintSyncReport(m.Report)
+					return nil
+				}
+
+				records := make([]model.ImportRecord, len(m.Push))
+				for i, t := range m.Push {
+					records[i] = model.ImportRecord{Line: i + 1, Task: t}
+				}
+				if report, err := ctrl.ImportTasks(records, false); err != nil {
+					view.PrintImportReport(report)
+					return err
+				}
+				for _, t := range m.Trash {
+					if err := ctrl.DeleteTask(t.ID); err != nil {
+						return err
+					}
+				}
+
+				if err := writeTodoTxt(path, m.Lines); err != nil {
+					return err
+				}
+				if err := writeTodoTxt(statePath, m.Lines); err != nil {
+					return err
+				}
+				view.PrintSyncReport(m.Report)
+				return nil
+			})
+		},
+	}
+}
+
+// readTodoTxt todo.txtを読み込む。ファイルがなければ空として扱い、解析できない行があればエラーにする
+func readTodoTxt(path string) ([]model.Task, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := todotxt.Read(f)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]model.Task, 0, len(records))
+	for _, r := range records {
+		if len(r.Errors) > 0 {
+			return nil, fmt.Errorf("%s:%d: %s", path, r.Line, strings.Join(r.Errors, ", "))
+		}
+		tasks = append(tasks, r.Task)
+	}
+	return tasks, nil
+}
+
+// writeTodoTxt 一時ファイルに書き出してから置き換える
+func writeTodoTxt(path string, tasks []model.Task) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := todotxt.Write(tmp, tasks); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
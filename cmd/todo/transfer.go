@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/csvio"
+	"task-recommender/internal/ical"
+	"task-recommender/internal/model"
+	"task-recommender/internal/todotxt"
+	"task-recommender/internal/view"
+)
+
+// openOutput 出力先のファイルを開く。"-" または空の場合は標準出力
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+// openInput 入力元のファイルを開く。"-" の場合は標準入力
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "タスクをファイルに書き出す",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Value: "csv", Usage: "出力形式 (csv, ics, todotxt)"},
+			&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "出力先のファイル（省略時は標準出力）"},
+		},
+		Action: func(c *cli.Context) error {
+			return withController(c, func(ctrl *controller.TaskController) error {
+				tasks, err := ctrl.ListTasks()
+				if err != nil {
+					return err
+				}
+
+				out, err := openOutput(c.String("output"))
+				if err != nil {
+					return err
+				}
+				defer out.Close()
+
+				switch c.String("format") {
+				case "csv":
+					return csvio.Write(out, tasks.([]model.Task))
+				case "ics":
+					return ical.Write(out, ical.Calendar{Tasks: tasks.([]model.Task)}, time.Now())
+				case "todotxt":
+					return todotxt.Write(out, tasks.([]model.Task))
+				default:
+					return fmt.Errorf("未対応の形式です: %s", c.String("format"))
+				}
+			})
+		},
+	}
+}
+
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "ファイルからタスクを取り込む（外部IDが一致するタスクは更新する）",
+		ArgsUsage: "<ファイル | ->",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Value: "csv", Usage: "入力形式 (csv, ics, todotxt)"},
+			&cli.BoolFlag{Name: "dry-run", Usage: "検証のみ行い保存しない"},
+			&cli.StringSliceFlag{Name: "map", Usage: "列名の対応 (例: --map 件名:title)"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("ファイルを指定してください")
+			}
+			in, err := openInput(c.Args().First())
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			var records []model.ImportRecord
+			switch c.String("format") {
+			case "csv":
+				mapping := map[string]string{}
+				for _, pair := range c.StringSlice("map") {
+					from, to, ok := strings.Cut(pair, ":")
+					if !ok {
+						return fmt.Errorf("列の対応の形式が不正です: %q", pair)
+					}
+					mapping[from] = to
+				}
+				records, err = csvio.Read(in, csvio.Options{Mapping: mapping})
+			case "ics":
+				records, err = ical.Read(in)
+			case "todotxt":
+				records, err = todotxt.Read(in)
+			default:
+				return fmt.Errorf("未対応の形式です: %s", c.String("format"))
+			}
+			if err != nil {
+				return err
+			}
+
+			return withController(c, func(ctrl *controller.TaskController) error {
+				report, err := ctrl.ImportTasks(records, c.Bool("dry-run"))
+				view.PrintImportReport(report)
+				return err
+			})
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"task-recommender/internal/api"
+	"task-recommender/internal/model"
+	"task-recommender/internal/sse"
+	"task-recommender/internal/view"
+)
+
+func watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "APIサーバーからタスクの変更を受け取って表示し続ける",
+		Description: "APIサーバーの /v1/events にServer-Sent Eventsで接続します。\n" +
+			"切断された場合は最後に受け取ったイベントから再開します。",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "server",
+				Value:   "http://localhost:10000",
+				Usage:   "APIサーバーのURL",
+				EnvVars: []string{"TODO_SERVER"},
+			},
+			&cli.StringFlag{Name: "since", Usage: "このイベントIDより後のイベントから表示する（省略時は接続以降のみ）"},
+			&cli.StringSliceFlag{Name: "type", Usage: "表示するイベントの種類 (task.created, task.updated, task.completed, task.deleted)"},
+			&cli.BoolFlag{Name: "json", Usage: "イベントをJSONのまま1行ずつ出力する"},
+		},
+		Action: func(c *cli.Context) error {
+			endpoint, err := url.Parse(strings.TrimSuffix(c.String("server"), "/") + api.APIVersionPrefix + "/events")
+			if err != nil {
+				return fmt.Errorf("サーバーのURLが不正です: %w", err)
+			}
+			if types := c.StringSlice("type"); len(types) > 0 {
+				endpoint.RawQuery = url.Values{"types": {strings.Join(types, ",")}}.Encode()
+			}
+
+			lastID := c.String("since")
+			retry := 3 * time.Second
+			for {
+				err := watchEvents(endpoint.String(), &lastID, &retry, func(e model.TaskEvent, raw string) {
+					if c.Bool("json") {
+						fmt.Println(raw)
+						return
+					}
+					view.PrintTaskEvent(e)
+				})
+				var status *watchStatusError
+				if errors.As(err, &status) && status.code < 500 {
+					return err
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "接続が切れました: %v（%sに再接続します）\n", err, retry)
+				}
+				time.Sleep(retry)
+			}
+		},
+	}
+}
+
+// watchStatusError サーバーが200以外を返した
+type watchStatusError struct {
+	code int
+	body string
+}
+
+func (e *watchStatusError) Error() string {
+	return fmt.Sprintf("サーバーエラー (%d): %s", e.code, e.body)
+}
+
+// watchEvents イベントストリームに接続し、切断されるまでイベントをfnに渡す
+//
+// lastIDとretryは受け取ったイベントIDとサーバーが指定した再接続の間隔で更新する
+func watchEvents(endpoint string, lastID *string, retry *time.Duration, fn func(model.TaskEvent, string)) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", sse.ContentType)
+	if *lastID != "" {
+		req.Header.Set("Last-Event-ID", *lastID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &watchStatusError{code: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		ev, err := reader.Next()
+		if reader.Retry() > 0 {
+			*retry = reader.Retry()
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		*lastID = ev.ID
+
+		var e model.TaskEvent
+		if err := json.Unmarshal([]byte(ev.Data), &e); err != nil {
+			return fmt.Errorf("イベントを読み込めません: %w", err)
+		}
+		fn(e, ev.Data)
+	}
+}
@@ -15,33 +15,167 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/tasks": {
+        "/board": {
             "get": {
-                "description": "すべてのタスクの一覧を取得します",
-                "consumes": [
-                    "application/json"
+                "description": "プロジェクトのボードにWebSocketで接続し、タスクの変更と閲覧状況をリアルタイムに受け取ります。\n接続するとプロジェクトのタスク一覧 (snapshot) が届き、以降はタスクの変更 (event) と閲覧状況 (presence) が届きます。\nクライアントからは閲覧状況 ({\"type\":\"presence\",\"state\":\"editing\",\"task_id\":1}) と、\nPOST /tasks/bulk の1件分と同じ形式の操作 ({\"type\":\"mutation\",\"request_id\":\"1\",\"mutation\":{\"op\":\"set_status\",\"id\":1,\"status\":\"done\",\"version\":3}}) を送れます。\n操作の結果は同じ request_id の result として返ります。project を省略するとすべてのプロジェクトの変更を受け取ります",
+                "tags": [
+                    "board"
+                ],
+                "summary": "ボードにWebSocketで接続",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "プロジェクト",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名（X-User ヘッダーを指定できない場合）",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
                 ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "400": {
+                        "description": "WebSocketのリクエストではありません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar.ics": {
+            "get": {
+                "description": "ゴミ箱にないタスクをiCalendar形式のVTODOとして配信します。planned=true の場合は、おすすめ度の高い順に平日9時〜18時へ割り当てた作業予定をVEVENTとして含めます。カレンダーアプリから購読できるよう、トークンはクエリパラメーターまたはAuthorizationヘッダー (Bearer) で指定します",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "tasks"
+                    "calendar"
+                ],
+                "summary": "カレンダーを配信",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "配信用のトークン",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "作業予定をVEVENTとして含める",
+                        "name": "planned",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 90,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "作業予定を立てる日数（既定: 7、最大: 90）",
+                        "name": "days",
+                        "in": "query"
+                    }
                 ],
-                "summary": "タスク一覧を取得",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Task"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "日数が不正です",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "トークンが不正です",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "カレンダーの配信が無効です",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "タイトル、説明、優先度、期限日、見積時間を指定して新しいタスクを作成します",
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "タスクの作成・更新・完了・削除をServer-Sent Events (text/event-stream) で配信します。\nイベント名はイベントの種類、dataはTaskEventのJSON、idはイベントIDです。\nLast-Event-ID ヘッダー（またはクエリパラメーター last_event_id）を指定すると、そのIDより後のイベントから再開します。\n指定しない場合は接続以降のイベントのみを配信します",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "タスクの変更をストリーミング",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "最後に受け取ったイベントID",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "最後に受け取ったイベントID（ヘッダーを指定できない場合）",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "配信するイベントの種類（カンマ区切り、例: task.created,task.completed）",
+                        "name": "types",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TaskEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "get": {
+                "description": "タスク、プロジェクト、タグ、おすすめをGraphQLで取得・更新します。\nPOST は {\"query\": \"...\", \"operationName\": \"...\", \"variables\": {...}} のJSONを、GET はクエリパラメーターを受け付けます（GET は query のみ）。\nAccept: text/event-stream を指定すると応答をServer-Sent Events (next, complete) で返すため、subscription { taskEvents { ... } } を購読できます。\nWebSocket (サブプロトコル graphql-transport-ws) で接続した場合も subscription を購読できます。\n例: { project(name: \"買い物\") { tasks { id title tags history { action actor createdAt } } } tags { name } }",
                 "consumes": [
                     "application/json"
                 ],
@@ -49,28 +183,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "graphql"
                 ],
-                "summary": "新しいタスクを作成",
+                "summary": "GraphQL",
                 "parameters": [
                     {
-                        "description": "タスク情報",
-                        "name": "task",
+                        "description": "GraphQLのリクエスト",
+                        "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/graphql.Request"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "クエリ (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "実行する操作の名前 (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "変数のJSON (GET)",
+                        "name": "variables",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     },
                     "400": {
@@ -79,18 +227,16 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "サーバーエラー",
+                    "405": {
+                        "description": "GET では mutation を実行できません",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/tasks/{id}": {
-            "delete": {
-                "description": "指定されたIDのタスクを削除します",
+            },
+            "post": {
+                "description": "タスク、プロジェクト、タグ、おすすめをGraphQLで取得・更新します。\nPOST は {\"query\": \"...\", \"operationName\": \"...\", \"variables\": {...}} のJSONを、GET はクエリパラメーターを受け付けます（GET は query のみ）。\nAccept: text/event-stream を指定すると応答をServer-Sent Events (next, complete) で返すため、subscription { taskEvents { ... } } を購読できます。\nWebSocket (サブプロトコル graphql-transport-ws) で接続した場合も subscription を購読できます。\n例: { project(name: \"買い物\") { tasks { id title tags history { action actor createdAt } } } tags { name } }",
                 "consumes": [
                     "application/json"
                 ],
@@ -98,26 +244,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "graphql"
                 ],
-                "summary": "タスクを削除",
+                "summary": "GraphQL",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "タスクID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "GraphQLのリクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "クエリ (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "実行する操作の名前 (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "変数のJSON (GET)",
+                        "name": "variables",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     },
                     "400": {
@@ -126,8 +288,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "サーバーエラー",
+                    "405": {
+                        "description": "GET では mutation を実行できません",
                         "schema": {
                             "type": "string"
                         }
@@ -135,9 +297,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/complete": {
-            "put": {
-                "description": "指定されたIDのタスクを完了状態に更新します",
+        "/recommendations": {
+            "get": {
+                "description": "優先度・期限・着手状況・残りの作業量（チェックリストの未完了の割合を反映）から、次に取り組むべきタスクを返します",
                 "consumes": [
                     "application/json"
                 ],
@@ -145,25 +307,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "recommendations"
                 ],
-                "summary": "タスクを完了としてマーク",
+                "summary": "おすすめのタスクを取得",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "タスクID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "取得件数 (既定値: 5)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Recommendation"
                             }
                         }
                     },
@@ -182,55 +343,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/due": {
-            "put": {
-                "description": "指定されたIDのタスクの期限日を更新します",
+        "/slack/commands": {
+            "post": {
+                "description": "Slack互換のスラッシュコマンドを受け付けます。X-Slack-Signature の署名を検証したうえで、\ntext の内容に応じてタスクの追加 (add 牛乳を買う p3 due:2026-10-20)、おすすめの表示 (next)、完了 (done 12) を行います。\n操作ユーザーは user_name です。コマンドの誤りは200で使い方とともに返します",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "chat"
                 ],
-                "summary": "タスクの期限日を更新",
+                "summary": "チャットのスラッシュコマンド",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "タスクID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "送信日時のUNIX時刻",
+                        "name": "X-Slack-Request-Timestamp",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "期限日情報",
-                        "name": "dueDate",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "type": "string",
+                        "description": "署名 (v0=...)",
+                        "name": "X-Slack-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "コマンド名 (例: /task)",
+                        "name": "command",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "コマンドの引数",
+                        "name": "text",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "実行したユーザー名",
+                        "name": "user_name",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/slash.Response"
                         }
                     },
-                    "400": {
-                        "description": "不正なリクエスト",
+                    "401": {
+                        "description": "署名が不正です",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "サーバーエラー",
+                    "404": {
+                        "description": "スラッシュコマンドが無効です",
                         "schema": {
                             "type": "string"
                         }
@@ -238,45 +412,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/duration": {
-            "put": {
-                "description": "指定されたIDのタスクの見積時間を更新します",
+        "/tasks": {
+            "get": {
+                "description": "すべてのタスクの一覧を取得します。Acceptヘッダーで出力形式 (JSON, YAML, Markdown, HTML, テキストの表) を選べます",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml",
+                    "text/markdown",
+                    "text/html",
+                    "text/plain"
                 ],
                 "tags": [
                     "tasks"
                 ],
-                "summary": "タスクの見積時間を更新",
+                "summary": "タスク一覧を取得",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "タスクID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "出力形式 (application/json, application/yaml, text/markdown, text/html, text/plain)",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Task"
+                            }
+                        }
                     },
+                    "406": {
+                        "description": "対応していない形式です",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "タイトル、説明、優先度、期限日、見積時間を指定して新しいタスクを作成します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "新しいタスクを作成",
+                "parameters": [
                     {
-                        "description": "見積時間情報",
-                        "name": "duration",
+                        "description": "タスク情報",
+                        "name": "task",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/api.createTaskRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.createdResponse"
                         }
                     },
                     "400": {
@@ -294,9 +500,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/priority": {
-            "put": {
-                "description": "指定されたIDのタスクの優先度を更新します",
+        "/tasks/bulk": {
+            "post": {
+                "description": "作成 (create)、完了 (complete)、削除 (delete)、優先度・期限日・見積時間・状態・プロジェクトの変更 (set_priority, set_due_date, set_duration, set_status, set_project) を1つのトランザクションで順に実行し、操作ごとの結果を返します。version を指定した操作は、タスクのバージョンが一致する場合のみ実行します。mode が atomic（既定）の場合は1件でも失敗するとすべて取り消して422を返し、best_effort の場合は失敗した操作だけを取り消します",
                 "consumes": [
                     "application/json"
                 ],
@@ -306,33 +512,29 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "タスクの優先度を更新",
+                "summary": "タスクを一括操作",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "タスクID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "優先度情報",
-                        "name": "priority",
+                        "description": "一括操作",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/api.bulkRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.BulkReport"
                         }
                     },
                     "400": {
@@ -341,6 +543,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "失敗した操作があるため取り消しました",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkReport"
+                        }
+                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -349,26 +557,2843 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
+        },
+        "/tasks/export": {
+            "get": {
+                "description": "ゴミ箱にないすべてのタスクを指定した形式で出力します",
+                "produces": [
+                    "text/csv",
+                    "text/calendar",
+                    "text/plain"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "タスクをエクスポート",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "出力形式 (csv, ics, todotxt)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/import": {
+            "post": {
+                "description": "CSV、iCalendar (VTODO)、todo.txtのタスクを外部ID (external_id) で突き合わせて作成または更新します。CSVの列名は英語・日本語の別名を自動で判定し、map で明示的に対応付けることもできます。日付の書式は列ごとに自動判定します。iCalendarではUID、todo.txtでは id: の値を外部IDとして使います。dry_run=true の場合は保存せずに行ごとの検証結果を返します",
+                "consumes": [
+                    "text/csv",
+                    "text/calendar",
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "タスクをインポート",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "入力形式 (csv, ics, todotxt)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "検証のみ行い保存しない",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "列名の対応 (例: 件名:title,締切:due_date)",
+                        "name": "map",
+                        "in": "query"
+                    },
+                    {
+                        "description": "インポートするファイルの内容",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "検証エラーのある行があります",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/quick": {
+            "post": {
+                "description": "「明日までにレポート提出 !高 90分」や「submit report tomorrow p3 1h30m #work」のような文から\nタイトル、優先度、期限日、見積時間、プロジェクト (+name)、状況 (#tag, @tag) を読み取ってタスクを作成します。\n今日、明日、来週金曜、next Friday などの相対的な日付はサーバーの日付を基準にします。\ndry_run を true にすると作成せずに解釈した結果だけを返します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "自然文からタスクを追加",
+                "parameters": [
+                    {
+                        "description": "自然文",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.quickAddRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run の場合",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}": {
+            "get": {
+                "description": "指定されたIDのタスクを取得します。ETagヘッダーにタスクのバージョンを返すので、更新時にIf-Matchヘッダーで指定すると他のユーザーの更新を上書きせずに済みます",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "前回取得したETag。一致する場合は304を返す",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "304": {
+                        "description": "変更されていません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "指定されたIDのタスクをゴミ箱に移動します。保持期間を過ぎると完全に削除されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクを削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/attachments": {
+            "get": {
+                "description": "指定されたIDのタスクの添付ファイルのメタデータを取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "タスクの添付ファイル一覧を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Attachment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "multipart/form-data の file フィールドで送信したファイルをタスクに添付します。Content-Typeはファイルの内容から判定します",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "タスクにファイルを添付",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "添付するファイル",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "ファイルサイズが上限を超えています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/attachments/{attachmentID}": {
+            "get": {
+                "description": "指定された添付ファイルの内容を取得します",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "添付ファイルをダウンロード",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "添付ファイルID",
+                        "name": "attachmentID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "添付ファイルが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "指定された添付ファイルを削除します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "添付ファイルを削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "添付ファイルID",
+                        "name": "attachmentID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "添付ファイルが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist": {
+            "get": {
+                "description": "指定されたIDのタスクのチェックリストを表示順に取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checklist"
+                ],
+                "summary": "タスクのチェックリストを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ChecklistItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "指定されたIDのタスクのチェックリストの末尾に項目を追加します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checklist"
+                ],
+                "summary": "チェックリストに項目を追加",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "項目の内容",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.checklistItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChecklistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist/order": {
+            "put": {
+                "description": "指定されたIDのタスクのチェックリストを item_ids の順に並べ替えます。すべての項目のIDを指定してください",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checklist"
+                ],
+                "summary": "チェックリストを並べ替え",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "項目IDの並び",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.checklistOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist/{itemID}": {
+            "delete": {
+                "description": "指定された項目をチェックリストから削除します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checklist"
+                ],
+                "summary": "チェックリストの項目を削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "項目ID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "チェックリストの項目が見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist/{itemID}/toggle": {
+            "put": {
+                "description": "指定された項目が未完了なら完了に、完了なら未完了にします",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checklist"
+                ],
+                "summary": "チェックリストの項目の完了状態を切り替え",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "項目ID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChecklistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "チェックリストの項目が見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/comments": {
+            "get": {
+                "description": "指定されたIDのタスクのコメントを投稿順に取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "タスクのコメント一覧を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Comment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "指定されたIDのタスクにMarkdown形式のコメントを投稿します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "タスクにコメントを投稿",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "コメント本文",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.commentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/comments/{commentID}": {
+            "put": {
+                "description": "指定されたコメントの本文を編集します。編集できるのは投稿者のみです",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "コメントを編集",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "コメントID",
+                        "name": "commentID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "コメント本文",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.commentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "投稿者ではありません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "コメントが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "指定されたコメントを削除します。削除できるのは投稿者のみです",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "コメントを削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "コメントID",
+                        "name": "commentID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "投稿者ではありません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "コメントが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/complete": {
+            "put": {
+                "description": "指定されたIDのタスクを完了状態に更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクを完了としてマーク",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "この状態には遷移できません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/due": {
+            "put": {
+                "description": "指定されたIDのタスクの期限日を更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクの期限日を更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "期限日情報",
+                        "name": "dueDate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.dueDateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/duration": {
+            "put": {
+                "description": "指定されたIDのタスクの見積時間を更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクの見積時間を更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "見積時間情報",
+                        "name": "duration",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.durationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/history": {
+            "get": {
+                "description": "指定されたIDのタスクに対する作成・更新・削除などの操作履歴を、操作ユーザー・日時・変更前後の値とともに取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "タスクの変更履歴を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.HistoryEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/priority": {
+            "put": {
+                "description": "指定されたIDのタスクの優先度を更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクの優先度を更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "優先度情報",
+                        "name": "priority",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.priorityRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/reopen": {
+            "put": {
+                "description": "指定されたIDの完了または中止したタスクを未着手に戻し、完了日時をクリアします",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "完了したタスクを再開",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "完了または中止していないタスクは再開できません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/restore": {
+            "put": {
+                "description": "指定されたIDの削除されたタスクを元に戻します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "ゴミ箱のタスクを復元",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "ゴミ箱にタスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/start": {
+            "post": {
+                "description": "指定されたIDのタスクの時間計測を開始します。計測中のタイマーはユーザーごとに1つまでです",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timers"
+                ],
+                "summary": "タスクの時間計測を開始",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "既に計測中のタイマーがあります",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/status": {
+            "put": {
+                "description": "指定されたIDのタスクの状態を遷移させます (todo → in_progress → done, blocked, cancelled)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクの状態を遷移",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "状態情報",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.statusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "タスクが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "この状態には遷移できません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "タスクが他のユーザーによって更新されています",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/stop": {
+            "post": {
+                "description": "指定されたIDのタスクで計測中のタイマーを停止します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timers"
+                ],
+                "summary": "タスクの時間計測を停止",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "計測中のタイマーがありません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/time": {
+            "get": {
+                "description": "指定されたIDのタスクの時間記録を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timers"
+                ],
+                "summary": "タスクの時間記録一覧を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TimeEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/transitions": {
+            "get": {
+                "description": "指定されたIDのタスクの状態遷移と各遷移の日時を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "タスクの状態遷移の履歴を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "タスクID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StatusTransition"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "description": "削除されたタスクを削除日時の新しい順に取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "ゴミ箱のタスク一覧を取得",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Task"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "登録されているWebhookを登録順に取得します。秘密鍵は含みません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhookの一覧を取得",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "タスクのイベント（task.created, task.updated, task.completed, task.deleted）を送信するWebhookを登録します。\nsecretを省略した場合は生成し、登録時のレスポンスでのみ返します。\n送信時は X-Webhook-Signature ヘッダーに \"タイムスタンプ.本文\" のHMAC-SHA256署名を付けます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhookを登録",
+                "parameters": [
+                    {
+                        "description": "送信先のURL、秘密鍵、イベントの種類",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.webhookRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作ユーザー名",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "description": "指定されたIDのWebhookと送信ログを削除します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhookを削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WebhookID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhookが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "指定されたIDのWebhookの送信ログを新しい順に取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhookの送信ログを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WebhookID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "取得する件数 (デフォルト: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhookが見つかりません",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
     "definitions": {
+        "api.bulkOperationRequest": {
+            "type": "object",
+            "required": [
+                "op"
+            ],
+            "properties": {
+                "description": {
+                    "description": "作成するタスクの説明",
+                    "type": "string"
+                },
+                "due_date": {
+                    "description": "期限日 (YYYY-MM-DD)",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2023-12-31"
+                },
+                "estimated_duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "id": {
+                    "description": "対象タスクのID (create 以外)",
+                    "type": "integer",
+                    "example": 1
+                },
+                "op": {
+                    "$ref": "#/definitions/model.BulkOp"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "project": {
+                    "type": "string",
+                    "example": "買い物"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.Status"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "牛乳を買う"
+                },
+                "version": {
+                    "description": "指定した場合、タスクのバージョンが一致する場合のみ実行する",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "api.bulkRequest": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "mode": {
+                    "description": "省略時は atomic",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ]
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.bulkOperationRequest"
+                    }
+                }
+            }
+        },
+        "api.checklistItemRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "title": {
+                    "description": "項目の内容",
+                    "type": "string",
+                    "example": "見積書を送る"
+                }
+            }
+        },
+        "api.checklistOrderRequest": {
+            "type": "object",
+            "required": [
+                "item_ids"
+            ],
+            "properties": {
+                "item_ids": {
+                    "description": "タスクのすべての項目のIDを新しい並び順で指定する",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "api.commentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "description": "コメント本文",
+                    "type": "string",
+                    "example": "明日までに確認します"
+                }
+            }
+        },
+        "api.createTaskRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "description": "タスクの説明",
+                    "type": "string",
+                    "example": "スーパーで低脂肪牛乳を購入する"
+                },
+                "due_date": {
+                    "description": "期限日 (YYYY-MM-DD)",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2023-12-31"
+                },
+                "estimated_duration": {
+                    "description": "見積所要時間（分）",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 30
+                },
+                "priority": {
+                    "description": "優先度 (1=低, 2=中, 3=高)",
+                    "type": "integer",
+                    "maximum": 3,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "title": {
+                    "description": "タスクのタイトル",
+                    "type": "string",
+                    "example": "牛乳を買う"
+                }
+            }
+        },
+        "api.createdResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.dueDateRequest": {
+            "type": "object",
+            "required": [
+                "due_date"
+            ],
+            "properties": {
+                "due_date": {
+                    "description": "期限日 (YYYY-MM-DD)",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2023-12-31"
+                }
+            }
+        },
+        "api.durationRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "見積所要時間（分）",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 45
+                }
+            }
+        },
+        "api.priorityRequest": {
+            "type": "object",
+            "required": [
+                "priority"
+            ],
+            "properties": {
+                "priority": {
+                    "description": "優先度 (1=低, 2=中, 3=高)",
+                    "type": "integer",
+                    "maximum": 3,
+                    "minimum": 1,
+                    "example": 3
+                }
+            }
+        },
+        "api.quickAddRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "dry_run": {
+                    "description": "作成せずに解釈結果だけを返す",
+                    "type": "boolean",
+                    "example": false
+                },
+                "text": {
+                    "description": "自然文",
+                    "type": "string",
+                    "example": "明日までにレポート提出 !高 90分 +仕事"
+                }
+            }
+        },
+        "api.statusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "遷移先の状態 (todo, in_progress, blocked, done, cancelled)",
+                    "type": "string",
+                    "example": "in_progress"
+                }
+            }
+        },
+        "api.statusResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                }
+            }
+        },
+        "api.webhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "送信するイベントの種類。空の場合はすべて",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EventType"
+                    }
+                },
+                "secret": {
+                    "description": "署名用の秘密鍵。省略した場合は生成する",
+                    "type": "string",
+                    "example": "s3cr3t"
+                },
+                "url": {
+                    "description": "送信先のURL",
+                    "type": "string",
+                    "example": "https://example.com/hooks/tasks"
+                }
+            }
+        },
+        "graphql.Error": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "graphql.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Error"
+                    }
+                }
+            }
+        },
+        "model.Attachment": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "@内容から判定したContent-Type\n@example: application/pdf",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@アップロード日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                },
+                "filename": {
+                    "description": "@元のファイル名\n@example: receipt.pdf",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@添付ファイルのID\n@example: 1",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "@ファイルサイズ（バイト）\n@example: 20480",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                },
+                "uploaded_by": {
+                    "description": "@アップロードしたユーザー名\n@example: yamada",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkItemStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "error",
+                "rolled_back"
+            ],
+            "x-enum-varnames": [
+                "BulkItemOK",
+                "BulkItemError",
+                "BulkItemRolledBack"
+            ]
+        },
+        "model.BulkMode": {
+            "type": "string",
+            "enum": [
+                "atomic",
+                "best_effort"
+            ],
+            "x-enum-varnames": [
+                "BulkAtomic",
+                "BulkBestEffort"
+            ]
+        },
+        "model.BulkOp": {
+            "type": "string",
+            "enum": [
+                "create",
+                "complete",
+                "delete",
+                "set_priority",
+                "set_due_date",
+                "set_duration",
+                "set_status",
+                "set_project"
+            ],
+            "x-enum-varnames": [
+                "BulkCreate",
+                "BulkComplete",
+                "BulkDelete",
+                "BulkSetPriority",
+                "BulkSetDueDate",
+                "BulkSetDuration",
+                "BulkSetStatus",
+                "BulkSetProject"
+            ]
+        },
+        "model.BulkReport": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "@変更を保存したかどうか\n@example: true",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "@失敗した件数\n@example: 0",
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "@失敗時の扱い (atomic, best_effort)\n@example: atomic",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ]
+                },
+                "results": {
+                    "description": "@操作ごとの結果",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkResult"
+                    }
+                },
+                "succeeded": {
+                    "description": "@成功した件数\n@example: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "@エラーの内容\n@example: タスクが見つかりません",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "@操作の順番（0始まり）\n@example: 0",
+                    "type": "integer"
+                },
+                "op": {
+                    "description": "@操作の種類\n@example: complete",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkOp"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "@結果 (ok, error, rolled_back)\n@example: ok",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkItemStatus"
+                        }
+                    ]
+                },
+                "task_id": {
+                    "description": "@操作したタスクのID（createでは作成したタスクのID）\n@example: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ChecklistItem": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "@項目の完了日時\n@example: 2023-01-02T15:30:00Z",
+                    "type": "string"
+                },
+                "done": {
+                    "description": "@項目の完了状態\n@example: false",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "@チェックリスト項目のID\n@example: 1",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "@表示順（0始まり）\n@example: 0",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "@項目の内容\n@example: 牛乳売り場を確認する\n@required: true",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChecklistProgress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "description": "@完了した項目数\n@example: 3",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "@項目の総数\n@example: 5",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Comment": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "@コメントの投稿者\n@example: yamada",
+                    "type": "string"
+                },
+                "body": {
+                    "description": "@コメント本文（Markdown）\n@example: 低脂肪牛乳が**売り切れ**なら普通の牛乳で\n@required: true",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@投稿日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@コメントのID\n@example: 1",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "@最終編集日時\n@example: 2023-01-02T09:30:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "DeliveryPending",
+                "DeliverySucceeded",
+                "DeliveryFailed"
+            ]
+        },
+        "model.EventType": {
+            "type": "string",
+            "enum": [
+                "task.created",
+                "task.updated",
+                "task.completed",
+                "task.deleted"
+            ],
+            "x-enum-varnames": [
+                "EventTaskCreated",
+                "EventTaskUpdated",
+                "EventTaskCompleted",
+                "EventTaskDeleted"
+            ]
+        },
+        "model.HistoryAction": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "status_changed",
+                "deleted",
+                "restored",
+                "purged"
+            ],
+            "x-enum-varnames": [
+                "HistoryCreated",
+                "HistoryUpdated",
+                "HistoryStatusChanged",
+                "HistoryDeleted",
+                "HistoryRestored",
+                "HistoryPurged"
+            ]
+        },
+        "model.HistoryEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "@操作種別 (created, updated, status_changed, deleted, restored, purged)\n@example: updated",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.HistoryAction"
+                        }
+                    ]
+                },
+                "actor": {
+                    "description": "@操作したユーザー名\n@example: yamada",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@操作日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "@変更した項目（作成・削除などの場合は空）\n@example: priority",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@変更履歴のID\n@example: 1",
+                    "type": "integer"
+                },
+                "new_value": {
+                    "description": "@変更後の値\n@example: 3",
+                    "type": "string"
+                },
+                "old_value": {
+                    "description": "@変更前の値\n@example: 1",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ImportAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "error",
+                "skip"
+            ],
+            "x-enum-varnames": [
+                "ImportCreate",
+                "ImportUpdate",
+                "ImportError",
+                "ImportSkip"
+            ]
+        },
+        "model.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "@作成した（する）件数\n@example: 3",
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "description": "@ドライランかどうか\n@example: true",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "@エラーの件数\n@example: 0",
+                    "type": "integer"
+                },
+                "results": {
+                    "description": "@行ごとの結果",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportResult"
+                    }
+                },
+                "updated": {
+                    "description": "@更新した（する）件数\n@example: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ImportResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "@処理内容 (create, update, skip, error)\n@example: create",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ImportAction"
+                        }
+                    ]
+                },
+                "errors": {
+                    "description": "@検証エラー\n@example: [\"優先度は1〜3で指定してください\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "external_id": {
+                    "description": "@外部ID\n@example: sheet-row-12",
+                    "type": "string"
+                },
+                "line": {
+                    "description": "@ファイル上の行番号\n@example: 2",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "@作成・更新したタスクのID（ドライランでは更新対象のIDのみ）\n@example: 1",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "@タスクのタイトル\n@example: 牛乳を買う",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Recommendation": {
+            "type": "object",
+            "properties": {
+                "reasons": {
+                    "description": "@おすすめの理由\n@example: [\"優先度が高い\", \"期限が明日\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remaining_effort": {
+                    "description": "@残りの見積所要時間（分）。チェックリストの未完了の割合を反映する\n@example: 18",
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "@おすすめ度（大きいほど先に取り組むべき）\n@example: 42.5",
+                    "type": "number"
+                },
+                "task": {
+                    "description": "@おすすめのタスク",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.Status": {
+            "type": "string",
+            "enum": [
+                "todo",
+                "in_progress",
+                "blocked",
+                "done",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "StatusTodo",
+                "StatusInProgress",
+                "StatusBlocked",
+                "StatusDone",
+                "StatusCancelled"
+            ]
+        },
+        "model.StatusTransition": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "@遷移させたユーザー名\n@example: yamada",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "@遷移前の状態（作成時は空）\n@example: todo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Status"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "@状態遷移のID\n@example: 1",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "@遷移後の状態\n@example: in_progress",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Status"
+                        }
+                    ]
+                },
+                "transitioned_at": {
+                    "description": "@遷移日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                }
+            }
+        },
         "model.Task": {
             "type": "object",
             "properties": {
+                "checklist": {
+                    "description": "@チェックリストの進捗",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ChecklistProgress"
+                        }
+                    ]
+                },
                 "completed_at": {
                     "description": "@タスクの完了日時\n@example: 2023-01-02T15:30:00Z",
                     "type": "string"
                 },
+                "contexts": {
+                    "description": "@タスクを行う状況（場所や道具など）\n@example: [\"外出\", \"電話\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "created_at": {
                     "description": "@タスクの作成日時\n@example: 2023-01-01T10:00:00Z",
                     "type": "string"
                 },
+                "deleted_at": {
+                    "description": "@タスクをゴミ箱に移動した日時\n@example: 2023-01-03T12:00:00Z",
+                    "type": "string"
+                },
                 "description": {
                     "description": "@タスクの説明\n@example: スーパーで低脂肪牛乳を購入する",
                     "type": "string"
                 },
                 "done": {
-                    "description": "@タスクの完了状態\n@example: false",
+                    "description": "@タスクの完了状態（後方互換のため status が done の場合に true）\n@example: false",
                     "type": "boolean"
                 },
                 "due_date": {
@@ -379,6 +3404,10 @@ const docTemplate = `{
                     "description": "@タスクの見積所要時間（分）\n@example: 30\n@min: 0",
                     "type": "integer"
                 },
+                "external_id": {
+                    "description": "@外部システムでのID。インポート時の突き合わせに使う\n@example: sheet-row-12",
+                    "type": "string"
+                },
                 "id": {
                     "description": "@タスクのID\n@example: 1",
                     "type": "integer"
@@ -387,9 +3416,196 @@ const docTemplate = `{
                     "description": "@タスクの優先度 (1=低, 2=中, 3=高)\n@example: 2\n@min: 1\n@max: 3",
                     "type": "integer"
                 },
+                "project": {
+                    "description": "@タスクが属するプロジェクト\n@example: 買い物",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "@タスクの状態 (todo, in_progress, blocked, done, cancelled)\n@example: in_progress",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Status"
+                        }
+                    ]
+                },
+                "status_changed_at": {
+                    "description": "@タスクの状態が最後に変わった日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                },
                 "title": {
                     "description": "タスクのタイトル\n@example: 牛乳を買う\n@required: true",
                     "type": "string"
+                },
+                "tracked_duration": {
+                    "description": "@タスクの実績時間（分）。時間記録の合計\n@example: 45\n@min: 0",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "@タスクのバージョン。更新のたびに1つ増える\n@example: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TaskEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "@操作したユーザー名\n@example: yamada",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "@変更した項目\n@example: status",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@イベントのID（変更履歴のID）\n@example: 42",
+                    "type": "integer"
+                },
+                "new_value": {
+                    "description": "@変更後の値\n@example: done",
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "description": "@発生日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                },
+                "old_value": {
+                    "description": "@変更前の値\n@example: in_progress",
+                    "type": "string"
+                },
+                "project": {
+                    "description": "@対象タスクのプロジェクト（現在の値）\n@example: 買い物",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "@イベントの種類 (task.created, task.updated, task.completed, task.deleted)\n@example: task.completed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EventType"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.TimeEntry": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "@時間記録のID\n@example: 1",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "@計測開始日時\n@example: 2023-01-02T09:00:00Z",
+                    "type": "string"
+                },
+                "stopped_at": {
+                    "description": "@計測終了日時（計測中は空）\n@example: 2023-01-02T09:45:00Z",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "@対象タスクのID\n@example: 1",
+                    "type": "integer"
+                },
+                "user": {
+                    "description": "@計測したユーザー名\n@example: yamada",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@登録日時\n@example: 2023-01-01T10:00:00Z",
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "@登録したユーザー名\n@example: yamada",
+                    "type": "string"
+                },
+                "events": {
+                    "description": "@送信するイベントの種類。空の場合はすべて\n@example: [\"task.created\", \"task.completed\"]",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EventType"
+                    }
+                },
+                "id": {
+                    "description": "@WebhookのID\n@example: 1",
+                    "type": "integer"
+                },
+                "secret": {
+                    "description": "@署名用の秘密鍵。登録時のレスポンスでのみ返す\n@example: 3f9a1c2b4d5e6f708192a3b4c5d6e7f8",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "@送信先のURL\n@example: https://example.com/hooks/tasks",
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "@送信を試みた回数\n@example: 1",
+                    "type": "integer"
+                },
+                "delivered_at": {
+                    "description": "@送信に成功した日時\n@example: 2023-01-02T09:00:01Z",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "@送信するイベント",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TaskEvent"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "@送信のID\n@example: 10",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "@最後の送信のエラー\n@example: connection refused",
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "description": "@最後の送信で受け取ったHTTPステータスコード\n@example: 200",
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "description": "@次に送信を試みる日時\n@example: 2023-01-02T09:00:30Z",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "@送信状態 (pending, succeeded, failed)\n@example: succeeded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DeliveryStatus"
+                        }
+                    ]
+                },
+                "webhook_id": {
+                    "description": "@WebhookのID\n@example: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "slash.Response": {
+            "type": "object",
+            "properties": {
+                "response_type": {
+                    "description": "ResponseType \"ephemeral\"（実行した人にだけ表示）または \"in_channel\"（チャンネル全体に表示）",
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
                 }
             }
         }
@@ -400,7 +3616,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "task-recommender.onrender.com",
-	BasePath:         "/",
+	BasePath:         "/v1",
 	Schemes:          []string{},
 	Title:            "タスク管理アプリケーションAPI",
 	Description:      "タスクの追加、一覧表示、完了マーク、削除などの機能を提供するAPI",
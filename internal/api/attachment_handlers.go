@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// @Summary タスクにファイルを添付
+// @Description multipart/form-data の file フィールドで送信したファイルをタスクに添付します。Content-Typeはファイルの内容から判定します
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param file formData file true "添付するファイル"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 201 {object} model.Attachment
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 413 {object} string "ファイルサイズが上限を超えています"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/attachments [post]
+func (h *TaskHandler) HandleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// ParseMultipartFormで一時ファイルを作らないよう、パートを順に読んでfileフィールドをそのまま渡す。
+	// 内容はサービス側で上限サイズまでメモリに読み込む
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			http.Error(w, "file フィールドがありません", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.controller.WithActor(actorFromRequest(r)).AddAttachment(id, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			http.Error(w, err.Error(), statusFromError(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(attachment)
+		return
+	}
+}
+
+// @Summary タスクの添付ファイル一覧を取得
+// @Description 指定されたIDのタスクの添付ファイルのメタデータを取得します
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {array} model.Attachment
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/attachments [get]
+func (h *TaskHandler) HandleListAttachments(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	attachments, err := h.controller.ListAttachments(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// @Summary 添付ファイルをダウンロード
+// @Description 指定された添付ファイルの内容を取得します
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "タスクID"
+// @Param attachmentID path int true "添付ファイルID"
+// @Success 200 {file} file
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "添付ファイルが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/attachments/{attachmentID} [get]
+func (h *TaskHandler) HandleDownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, attachmentID, err := getSubIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, content, err := h.controller.OpenAttachment(id, attachmentID)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, content)
+}
+
+// @Summary 添付ファイルを削除
+// @Description 指定された添付ファイルを削除します
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param attachmentID path int true "添付ファイルID"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "添付ファイルが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/attachments/{attachmentID} [delete]
+func (h *TaskHandler) HandleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id, attachmentID, err := getSubIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.controller.WithActor(actorFromRequest(r)).DeleteAttachment(id, attachmentID)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "deleted"})
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/model"
+	"task-recommender/internal/realtime"
+	"task-recommender/internal/service"
+)
+
+// boardMaxMessageBytes クライアントから受け取るメッセージの最大サイズ
+const boardMaxMessageBytes = 1 << 20
+
+// ボードのメッセージの種類
+const (
+	// サーバーから送る
+	boardSnapshot = "snapshot"
+	boardEvent    = "event"
+	boardResult   = "result"
+	boardError    = "error"
+	boardPong     = "pong"
+	// 双方向。クライアントからは自分の状態を、サーバーからはルーム全体の状態を送る
+	boardPresence = "presence"
+	// クライアントから送る
+	boardMutation = "mutation"
+	boardPing     = "ping"
+)
+
+// boardMessage ボードのWebSocketでやり取りするメッセージ
+type boardMessage struct {
+	Type string `json:"type"`
+	// クライアントが付けた識別子。result, error, pong で送り返す
+	RequestID string `json:"request_id,omitempty"`
+
+	// snapshot: プロジェクトのタスク一覧
+	Tasks []model.Task `json:"tasks,omitempty"`
+	// event: タスクの変更と変更後のタスク（削除された場合は含めない）
+	Event *model.TaskEvent `json:"event,omitempty"`
+	Task  *model.Task      `json:"task,omitempty"`
+	// snapshot, presence: ルームに接続しているユーザーの閲覧状況
+	Presence []model.Presence `json:"presence,omitempty"`
+	// result: mutationの結果
+	Result *model.BulkResult `json:"result,omitempty"`
+	// error: エラーの内容
+	Error string `json:"error,omitempty"`
+
+	// presence（クライアントから）: 自分の状態と対象のタスク
+	State  model.PresenceState `json:"state,omitempty"`
+	TaskID int                 `json:"task_id,omitempty"`
+	// mutation: 一括操作 (POST /tasks/bulk) の1件分と同じ形式の操作
+	Mutation *bulkOperationRequest `json:"mutation,omitempty"`
+}
+
+// board 接続しているボードにタスクの変更を配信する
+type board struct {
+	controller *controller.TaskController
+	hub        *realtime.Hub
+	once       sync.Once
+}
+
+func newBoard(controller *controller.TaskController) *board {
+	return &board{controller: controller, hub: realtime.NewHub()}
+}
+
+// start 最初の接続で変更の配信を始める
+func (b *board) start() {
+	b.once.Do(func() { go b.run() })
+}
+
+// run 変更履歴を定期的に読み、接続しているボードに配信し続ける
+func (b *board) run() {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	lastID := int64(-1)
+	for ; ; <-ticker.C {
+		// 接続がない間は読み飛ばす。最新のIDは接続の有無を見る前に取得し、
+		// その間に接続したボードが初期表示以降の変更を取りこぼさないようにする
+		latest, err := b.controller.LatestEventID()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ボードの配信エラー: %v\n", err)
+			continue
+		}
+		if lastID < 0 || b.hub.Empty() {
+			lastID = latest
+			continue
+		}
+
+		for {
+			events, err := b.controller.ListEvents(lastID, eventBatchSize)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ボードの配信エラー: %v\n", err)
+				break
+			}
+			for _, e := range events {
+				lastID = e.ID
+				b.publish(e)
+			}
+			if len(events) < eventBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// publish 変更をタスクのプロジェクトと全体のルームに配信する。
+// 別のプロジェクトへ移したタスクは、移す前のプロジェクトにも配信する
+func (b *board) publish(e model.TaskEvent) {
+	msg := boardMessage{Type: boardEvent, Event: &e}
+	if e.Type != model.EventTaskDeleted {
+		if task, err := b.controller.GetTask(e.TaskID); err == nil {
+			msg.Task = &task
+		}
+	}
+
+	projects := []string{realtime.AllProjects}
+	if e.Project != realtime.AllProjects {
+		projects = append(projects, e.Project)
+	}
+	if e.Field == "project" && e.OldValue != e.Project && e.OldValue != realtime.AllProjects {
+		projects = append(projects, e.OldValue)
+	}
+	for _, p := range projects {
+		b.hub.Broadcast(p, msg)
+	}
+}
+
+// broadcastPresence ルームの閲覧状況を配信する
+func (b *board) broadcastPresence(project string) {
+	b.hub.Broadcast(project, boardMessage{Type: boardPresence, Presence: b.hub.Presence(project)})
+}
+
+// @Summary ボードにWebSocketで接続
+// @Description プロジェクトのボードにWebSocketで接続し、タスクの変更と閲覧状況をリアルタイムに受け取ります。
+// @Description 接続するとプロジェクトのタスク一覧 (snapshot) が届き、以降はタスクの変更 (event) と閲覧状況 (presence) が届きます。
+// @Description クライアントからは閲覧状況 ({"type":"presence","state":"editing","task_id":1}) と、
+// @Description POST /tasks/bulk の1件分と同じ形式の操作 ({"type":"mutation","request_id":"1","mutation":{"op":"set_status","id":1,"status":"done","version":3}}) を送れます。
+// @Description 操作の結果は同じ request_id の result として返ります。project を省略するとすべてのプロジェクトの変更を受け取ります
+// @Tags board
+// @Param project query string false "プロジェクト"
+// @Param user query string false "操作ユーザー名（X-User ヘッダーを指定できない場合）"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 101 "Switching Protocols"
+// @Failure 400 {object} string "WebSocketのリクエストではありません"
+// @Router /board [get]
+func (h *TaskHandler) HandleBoard(w http.ResponseWriter, r *http.Request) {
+	project := strings.TrimSpace(r.URL.Query().Get("project"))
+	user := actorFromRequest(r)
+	if user == "" {
+		user = strings.TrimSpace(r.URL.Query().Get("user"))
+	}
+
+	h.board.start()
+	websocket.Server{
+		// APIは同一オリジン以外からも利用するため、Originは確認しない
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = boardMaxMessageBytes
+			h.serveBoard(ws, project, user)
+		},
+	}.ServeHTTP(w, r)
+}
+
+// serveBoard 接続が切れるまでボードのメッセージをやり取りする
+func (h *TaskHandler) serveBoard(ws *websocket.Conn, project, user string) {
+	hub := h.board.hub
+	client := hub.Join(project, user)
+	defer func() {
+		hub.Leave(client)
+		h.board.broadcastPresence(project)
+	}()
+
+	// 送信は別のゴルーチンで行い、受信が追いつかない接続は切断する
+	go func() {
+		for msg := range client.Messages() {
+			if err := websocket.Message.Send(ws, string(msg)); err != nil {
+				break
+			}
+		}
+		ws.Close()
+	}()
+
+	tasks, err := h.boardTasks(project)
+	if err != nil {
+		hub.Send(client, boardMessage{Type: boardError, Error: err.Error()})
+		return
+	}
+	hub.Send(client, boardMessage{Type: boardSnapshot, Tasks: tasks, Presence: hub.Presence(project)})
+	h.board.broadcastPresence(project)
+
+	for {
+		var data string
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			return
+		}
+		var msg boardMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			hub.Send(client, boardMessage{Type: boardError, Error: "メッセージを読み込めません: " + err.Error()})
+			continue
+		}
+
+		switch msg.Type {
+		case boardPresence:
+			if !msg.State.Valid() {
+				hub.Send(client, boardMessage{Type: boardError, RequestID: msg.RequestID, Error: "不正な状態です: " + string(msg.State)})
+				continue
+			}
+			if hub.SetPresence(client, msg.State, msg.TaskID) {
+				h.board.broadcastPresence(project)
+			}
+		case boardMutation:
+			hub.Send(client, h.applyBoardMutation(project, user, msg))
+		case boardPing:
+			hub.Send(client, boardMessage{Type: boardPong, RequestID: msg.RequestID})
+		default:
+			hub.Send(client, boardMessage{Type: boardError, RequestID: msg.RequestID, Error: "不明なメッセージです: " + msg.Type})
+		}
+	}
+}
+
+// boardTasks ボードに表示するタスクを取得する
+func (h *TaskHandler) boardTasks(project string) ([]model.Task, error) {
+	all, err := h.controller.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	if project == realtime.AllProjects {
+		return all.([]model.Task), nil
+	}
+
+	tasks := []model.Task{}
+	for _, t := range all.([]model.Task) {
+		if t.Project == project {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// applyBoardMutation ボードから送られた操作を実行し、結果のメッセージを返す。
+// 変更は他の変更と同じく event として配信される
+func (h *TaskHandler) applyBoardMutation(project, user string, msg boardMessage) boardMessage {
+	reply := boardMessage{Type: boardResult, RequestID: msg.RequestID}
+	if msg.Mutation == nil {
+		reply.Type, reply.Error = boardError, "mutation を指定してください"
+		return reply
+	}
+
+	op, err := msg.Mutation.toOperation()
+	if err != nil {
+		reply.Type, reply.Error = boardError, err.Error()
+		return reply
+	}
+	if op.Op == model.BulkCreate && op.Project == "" {
+		op.Project = project
+	}
+
+	report, err := h.controller.WithActor(user).ExecuteBulk([]model.BulkOperation{op}, model.BulkAtomic)
+	if err != nil && !errors.Is(err, service.ErrBulkFailed) {
+		reply.Type, reply.Error = boardError, err.Error()
+		return reply
+	}
+	reply.Result = &report.Results[0]
+	return reply
+}
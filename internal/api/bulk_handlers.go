@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"task-recommender/internal/model"
+	"task-recommender/internal/service"
+)
+
+// bulkOperationRequest 一括操作の1件分のリクエスト
+type bulkOperationRequest struct {
+	Op model.BulkOp `json:"op" validate:"required"`
+	// 対象タスクのID (create 以外)
+	ID int `json:"id" example:"1"`
+	// 指定した場合、タスクのバージョンが一致する場合のみ実行する
+	Version int    `json:"version" example:"3"`
+	Title   string `json:"title" example:"牛乳を買う"`
+	// 作成するタスクの説明
+	Description string `json:"description"`
+	Priority    int    `json:"priority" example:"2"`
+	// 期限日 (YYYY-MM-DD)
+	DueDate           string       `json:"due_date" example:"2023-12-31" format:"date"`
+	EstimatedDuration int          `json:"estimated_duration" example:"30"`
+	Status            model.Status `json:"status"`
+	Project           string       `json:"project" example:"買い物"`
+}
+
+// toOperation リクエストをサービスに渡す操作に変換する
+func (o bulkOperationRequest) toOperation() (model.BulkOperation, error) {
+	op := model.BulkOperation{
+		Op:                o.Op,
+		TaskID:            o.ID,
+		Version:           o.Version,
+		Title:             o.Title,
+		Description:       o.Description,
+		Priority:          o.Priority,
+		EstimatedDuration: o.EstimatedDuration,
+		Status:            o.Status,
+		Project:           o.Project,
+	}
+	if o.DueDate != "" {
+		dueDate, err := time.Parse("2006-01-02", o.DueDate)
+		if err != nil {
+			return op, fmt.Errorf("日付の形式が不正です。YYYY-MM-DD形式で指定してください")
+		}
+		op.DueDate = dueDate
+	}
+	return op, nil
+}
+
+// bulkRequest 一括操作のリクエスト
+type bulkRequest struct {
+	// 省略時は atomic
+	Mode       model.BulkMode         `json:"mode"`
+	Operations []bulkOperationRequest `json:"operations" validate:"required"`
+}
+
+// @Summary タスクを一括操作
+// @Description 作成 (create)、完了 (complete)、削除 (delete)、優先度・期限日・見積時間・状態・プロジェクトの変更 (set_priority, set_due_date, set_duration, set_status, set_project) を1つのトランザクションで順に実行し、操作ごとの結果を返します。version を指定した操作は、タスクのバージョンが一致する場合のみ実行します。mode が atomic（既定）の場合は1件でも失敗するとすべて取り消して422を返し、best_effort の場合は失敗した操作だけを取り消します
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body bulkRequest true "一括操作"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} model.BulkReport
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 422 {object} model.BulkReport "失敗した操作があるため取り消しました"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/bulk [post]
+func (h *TaskHandler) HandleBulkTasks(w http.ResponseWriter, r *http.Request) {
+	var req bulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ops := make([]model.BulkOperation, 0, len(req.Operations))
+	for i, o := range req.Operations {
+		op, err := o.toOperation()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("operations[%d]: %v", i, err), http.StatusBadRequest)
+			return
+		}
+		ops = append(ops, op)
+	}
+
+	report, err := h.controller.WithActor(actorFromRequest(r)).ExecuteBulk(ops, req.Mode)
+	switch {
+	case errors.Is(err, service.ErrInvalidBulkOperation), errors.Is(err, service.ErrTooManyBulkOperations):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil && !errors.Is(err, service.ErrBulkFailed):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(report)
+}
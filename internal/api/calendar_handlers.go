@@ -0,0 +1,81 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-recommender/internal/ical"
+	"task-recommender/internal/model"
+	"task-recommender/internal/service"
+)
+
+// @Summary カレンダーを配信
+// @Description ゴミ箱にないタスクをiCalendar形式のVTODOとして配信します。planned=true の場合は、おすすめ度の高い順に平日9時〜18時へ割り当てた作業予定をVEVENTとして含めます。カレンダーアプリから購読できるよう、トークンはクエリパラメーターまたはAuthorizationヘッダー (Bearer) で指定します
+// @Tags calendar
+// @Produce text/calendar
+// @Param token query string false "配信用のトークン"
+// @Param planned query bool false "作業予定をVEVENTとして含める"
+// @Param days query int false "作業予定を立てる日数（既定: 7、最大: 90）" minimum(1) maximum(90)
+// @Success 200 {file} file
+// @Failure 400 {object} string "日数が不正です"
+// @Failure 401 {object} string "トークンが不正です"
+// @Failure 404 {object} string "カレンダーの配信が無効です"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /calendar.ics [get]
+func (h *TaskHandler) HandleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	if h.calendarToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if !validToken(calendarTokenFromRequest(r), h.calendarToken) {
+		http.Error(w, "トークンが不正です", http.StatusUnauthorized)
+		return
+	}
+
+	planned, _ := strconv.ParseBool(r.URL.Query().Get("planned"))
+	days := 0
+	if s := r.URL.Query().Get("days"); planned && s != "" {
+		var err error
+		days, err = strconv.Atoi(s)
+		if err != nil || days < 1 || days > service.MaxPlanDays {
+			http.Error(w, fmt.Sprintf("日数は1〜%dで指定してください: %s", service.MaxPlanDays, s), http.StatusBadRequest)
+			return
+		}
+	}
+
+	tasks, err := h.controller.ListTasks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cal := ical.Calendar{Name: "タスク", Tasks: tasks.([]model.Task)}
+
+	if planned {
+		cal.Blocks, err = h.controller.PlanWorkBlocks(time.Now(), days)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+	ical.Write(w, cal, time.Now())
+}
+
+// calendarTokenFromRequest クエリパラメーター token、なければ Authorization: Bearer のトークンを返す
+func calendarTokenFromRequest(r *http.Request) string {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// validToken トークンを一定時間で比較する
+func validToken(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCalendarFeedRejectsInvalidDays(t *testing.T) {
+	h := NewTaskHandler(nil, Config{CalendarToken: "secret"})
+	for _, days := range []string{"0", "-1", "91", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret&planned=true&days="+days, nil)
+		rec := httptest.NewRecorder()
+		h.HandleCalendarFeed(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("days=%s: status = %d, want 400", days, rec.Code)
+		}
+	}
+}
+
+func TestCalendarFeedRequiresToken(t *testing.T) {
+	h := NewTaskHandler(nil, Config{CalendarToken: "secret"})
+	req := httptest.NewRequest(http.MethodGet, "/calendar.ics?token=wrong&planned=true&days=1000", nil)
+	rec := httptest.NewRecorder()
+	h.HandleCalendarFeed(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
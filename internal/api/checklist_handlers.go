@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// @Summary タスクのチェックリストを取得
+// @Description 指定されたIDのタスクのチェックリストを表示順に取得します
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {array} model.ChecklistItem
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/checklist [get]
+func (h *TaskHandler) HandleListChecklist(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.controller.ListChecklist(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// @Summary チェックリストに項目を追加
+// @Description 指定されたIDのタスクのチェックリストの末尾に項目を追加します
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param item body checklistItemRequest true "項目の内容"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 201 {object} model.ChecklistItem
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/checklist [post]
+func (h *TaskHandler) HandleAddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var data checklistItemRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.controller.WithActor(actorFromRequest(r)).AddChecklistItem(id, data.Title)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// @Summary チェックリストの項目の完了状態を切り替え
+// @Description 指定された項目が未完了なら完了に、完了なら未完了にします
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param itemID path int true "項目ID"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} model.ChecklistItem
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "チェックリストの項目が見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/checklist/{itemID}/toggle [put]
+func (h *TaskHandler) HandleToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, err := getSubIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.controller.WithActor(actorFromRequest(r)).ToggleChecklistItem(id, itemID)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// @Summary チェックリストの項目を削除
+// @Description 指定された項目をチェックリストから削除します
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param itemID path int true "項目ID"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "チェックリストの項目が見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/checklist/{itemID} [delete]
+func (h *TaskHandler) HandleDeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, err := getSubIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.controller.WithActor(actorFromRequest(r)).DeleteChecklistItem(id, itemID)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "deleted"})
+}
+
+// @Summary チェックリストを並べ替え
+// @Description 指定されたIDのタスクのチェックリストを item_ids の順に並べ替えます。すべての項目のIDを指定してください
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param order body checklistOrderRequest true "項目IDの並び"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/checklist/order [put]
+func (h *TaskHandler) HandleReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var data checklistOrderRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.controller.ReorderChecklist(id, data.ItemIDs)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "checklist reordered"})
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// @Summary タスクのコメント一覧を取得
+// @Description 指定されたIDのタスクのコメントを投稿順に取得します
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {array} model.Comment
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/comments [get]
+func (h *TaskHandler) HandleListComments(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	comments, err := h.controller.ListComments(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+// @Summary タスクにコメントを投稿
+// @Description 指定されたIDのタスクにMarkdown形式のコメントを投稿します
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param comment body commentRequest true "コメント本文"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 201 {object} model.Comment
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/comments [post]
+func (h *TaskHandler) HandleAddComment(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var data commentRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.controller.WithActor(actorFromRequest(r)).AddComment(id, data.Body)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// @Summary コメントを編集
+// @Description 指定されたコメントの本文を編集します。編集できるのは投稿者のみです
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param commentID path int true "コメントID"
+// @Param comment body commentRequest true "コメント本文"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} model.Comment
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 403 {object} string "投稿者ではありません"
+// @Failure 404 {object} string "コメントが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/comments/{commentID} [put]
+func (h *TaskHandler) HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	id, commentID, err := getSubIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var data commentRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.controller.WithActor(actorFromRequest(r)).UpdateComment(id, commentID, data.Body)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// @Summary コメントを削除
+// @Description 指定されたコメントを削除します。削除できるのは投稿者のみです
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param commentID path int true "コメントID"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 403 {object} string "投稿者ではありません"
+// @Failure 404 {object} string "コメントが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/comments/{commentID} [delete]
+func (h *TaskHandler) HandleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	id, commentID, err := getSubIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.controller.WithActor(actorFromRequest(r)).DeleteComment(id, commentID)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "deleted"})
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"task-recommender/internal/controller"
+)
+
+// etag タスクのバージョンを表すETag
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// versionsFromIfMatch If-Matchヘッダーのバージョンの一覧を取り出す。ヘッダーがないか "*" の場合は空を返す
+//
+// "3", "4" のように複数指定した場合は、いずれかと一致すれば更新する
+func versionsFromIfMatch(header string) ([]int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	var versions []int
+	for _, entry := range strings.Split(header, ",") {
+		tag := strings.Trim(strings.TrimPrefix(strings.TrimSpace(entry), "W/"), `"`)
+		version, err := strconv.Atoi(tag)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("If-Matchヘッダーが不正です: %s", header)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// setETag 更新後のタスクのバージョンをETagヘッダーに設定する。取得できなかった場合は設定しない
+func (h *TaskHandler) setETag(w http.ResponseWriter, id int) {
+	task, err := h.controller.GetTask(id)
+	if err != nil {
+		return
+	}
+	w.Header().Set("ETag", etag(task.Version))
+}
+
+// taskWriter 操作ユーザーとIf-Matchヘッダーのバージョンを反映したコントローラーを返す
+func (h *TaskHandler) taskWriter(r *http.Request) (*controller.TaskController, error) {
+	versions, err := versionsFromIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		return nil, err
+	}
+	return h.controller.WithActor(actorFromRequest(r)).IfVersion(versions...), nil
+}
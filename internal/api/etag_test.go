@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestVersionsFromIfMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []int
+	}{
+		{"", nil},
+		{"*", nil},
+		{` "3" `, []int{3}},
+		{`W/"3"`, []int{3}},
+		{`"3", "4"`, []int{3, 4}},
+		{`"3",W/"5"`, []int{3, 5}},
+	}
+	for _, tt := range tests {
+		got, err := versionsFromIfMatch(tt.header)
+		if err != nil {
+			t.Errorf("versionsFromIfMatch(%q): %v", tt.header, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("versionsFromIfMatch(%q) = %v, want %v", tt.header, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("versionsFromIfMatch(%q) = %v, want %v", tt.header, got, tt.want)
+				break
+			}
+		}
+	}
+
+	for _, header := range []string{`"abc"`, `"0"`, `"3", `, `"3", "x"`} {
+		if _, err := versionsFromIfMatch(header); err == nil {
+			t.Errorf("versionsFromIfMatch(%q) がエラーになりません", header)
+		}
+	}
+}
+
+func TestWriteResponsesReturnETag(t *testing.T) {
+	srv := httptest.NewServer(SetupRouter(newContractController(t), Config{}))
+	defer srv.Close()
+
+	do := func(method, path, ifMatch, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+APIVersionPrefix+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp, err := http.Post(srv.URL+APIVersionPrefix+"/tasks", "application/json", strings.NewReader(`{"title":"ETagの確認","priority":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created createdResponse
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/tasks/" + strconv.Itoa(created.ID)
+
+	first := do(http.MethodGet, path, "", "").Header.Get("ETag")
+	if first == "" {
+		t.Fatal("GET に ETag がありません")
+	}
+
+	// いずれかのバージョンが一致すれば更新し、更新後の ETag を返す
+	resp = do(http.MethodPut, path+"/priority", `"999", `+first, `{"priority":3}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	second := resp.Header.Get("ETag")
+	if second == "" || second == first {
+		t.Errorf("更新後の ETag = %q, 更新前 %q", second, first)
+	}
+	if got := do(http.MethodGet, path, "", "").Header.Get("ETag"); got != second {
+		t.Errorf("GET の ETag = %q, want %q", got, second)
+	}
+
+	// 古い ETag では更新できない
+	if resp := do(http.MethodPut, path+"/duration", first, `{"duration":30}`); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("古い ETag での更新の status = %d, want 412", resp.StatusCode)
+	}
+	resp = do(http.MethodPut, path+"/status", second, `{"status":"in_progress"}`)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == second {
+		t.Errorf("status = %d, ETag = %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-recommender/internal/model"
+	"task-recommender/internal/sse"
+)
+
+const (
+	// eventPollInterval 新しいイベントを確認する間隔
+	eventPollInterval = time.Second
+	// eventHeartbeatInterval プロキシに接続を切られないようコメントを送る間隔
+	eventHeartbeatInterval = 15 * time.Second
+	// eventRetry 切断時にクライアントが再接続するまでの待ち時間
+	eventRetry = 3 * time.Second
+	// eventBatchSize 一度に読み込むイベントの件数
+	eventBatchSize = 100
+)
+
+// @Summary タスクの変更をストリーミング
+// @Description タスクの作成・更新・完了・削除をServer-Sent Events (text/event-stream) で配信します。
+// @Description イベント名はイベントの種類、dataはTaskEventのJSON、idはイベントIDです。
+// @Description Last-Event-ID ヘッダー（またはクエリパラメーター last_event_id）を指定すると、そのIDより後のイベントから再開します。
+// @Description 指定しない場合は接続以降のイベントのみを配信します
+// @Tags events
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "最後に受け取ったイベントID"
+// @Param last_event_id query string false "最後に受け取ったイベントID（ヘッダーを指定できない場合）"
+// @Param types query string false "配信するイベントの種類（カンマ区切り、例: task.created,task.completed）"
+// @Success 200 {object} model.TaskEvent
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /events [get]
+func (h *TaskHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	types, err := eventTypesFromQuery(r.URL.Query().Get("types"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lastID, err := lastEventIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+		return
+	}
+	if lastID < 0 {
+		lastID, err = h.controller.LatestEventID()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", sse.ContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := sse.WriteRetry(w, eventRetry); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	poll := time.NewTicker(eventPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		// 溜まっているイベントをすべて送ってから待つ
+		for {
+			events, err := h.controller.ListEvents(lastID, eventBatchSize)
+			if err != nil {
+				sse.WriteComment(w, "error: "+err.Error())
+				flusher.Flush()
+				return
+			}
+			for _, e := range events {
+				lastID = e.ID
+				if len(types) > 0 && !types[e.Type] {
+					continue
+				}
+				data, err := json.Marshal(e)
+				if err != nil {
+					return
+				}
+				ev := sse.Event{ID: strconv.FormatInt(e.ID, 10), Type: string(e.Type), Data: string(data)}
+				if err := sse.Write(w, ev); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+			if len(events) < eventBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if err := sse.WriteComment(w, "heartbeat"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-poll.C:
+		}
+	}
+}
+
+// lastEventIDFromRequest 再開するイベントIDを取り出す。指定がない場合は-1を返す
+func lastEventIDFromRequest(r *http.Request) (int64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v == "" {
+		return -1, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return id, nil
+}
+
+// eventTypesFromQuery カンマ区切りのイベントの種類を集合に変換する。空の場合はnilを返す
+func eventTypesFromQuery(s string) (map[model.EventType]bool, error) {
+	if s == "" {
+		return nil, nil
+	}
+	types := map[model.EventType]bool{}
+	for _, name := range strings.Split(s, ",") {
+		t := model.EventType(strings.TrimSpace(name))
+		if !t.Valid() {
+			return nil, fmt.Errorf("不明なイベントの種類です: %s", t)
+		}
+		types[t] = true
+	}
+	return types, nil
+}
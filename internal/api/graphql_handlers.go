@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"task-recommender/internal/graphql"
+	"task-recommender/internal/sse"
+)
+
+const (
+	// graphqlMaxRequestBytes GraphQLのリクエストの最大サイズ
+	graphqlMaxRequestBytes = 1 << 20
+	// graphqlWSProtocol WebSocketで使うサブプロトコル (graphql-transport-ws)
+	graphqlWSProtocol = "graphql-transport-ws"
+	// graphqlInitTimeout WebSocketの接続後、connection_init を待つ時間
+	graphqlInitTimeout = 10 * time.Second
+)
+
+// graphql-transport-ws のメッセージの種類
+const (
+	graphqlConnectionInit = "connection_init"
+	graphqlConnectionAck  = "connection_ack"
+	graphqlPing           = "ping"
+	graphqlPong           = "pong"
+	graphqlSubscribe      = "subscribe"
+	graphqlNext           = "next"
+	graphqlError          = "error"
+	graphqlComplete       = "complete"
+)
+
+// graphqlWSMessage graphql-transport-ws でやり取りするメッセージ
+type graphqlWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// @Summary GraphQL
+// @Description タスク、プロジェクト、タグ、おすすめをGraphQLで取得・更新します。
+// @Description POST は {"query": "...", "operationName": "...", "variables": {...}} のJSONを、GET はクエリパラメーターを受け付けます（GET は query のみ）。
+// @Description Accept: text/event-stream を指定すると応答をServer-Sent Events (next, complete) で返すため、subscription { taskEvents { ... } } を購読できます。
+// @Description WebSocket (サブプロトコル graphql-transport-ws) で接続した場合も subscription を購読できます。
+// @Description 例: { project(name: "買い物") { tasks { id title tags history { action actor createdAt } } } tags { name } }
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request false "GraphQLのリクエスト"
+// @Param query query string false "クエリ (GET)"
+// @Param operationName query string false "実行する操作の名前 (GET)"
+// @Param variables query string false "変数のJSON (GET)"
+// @Success 200 {object} graphql.Response
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 405 {object} string "GET では mutation を実行できません"
+// @Router /graphql [post]
+// @Router /graphql [get]
+func (h *TaskHandler) HandleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		h.serveGraphQLWebSocket(w, r)
+		return
+	}
+
+	req, err := graphqlRequestFrom(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodGet && graphql.OperationKind(req) == "mutation" {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "GET では mutation を実行できません", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := withGraphQLController(r.Context(), h.controller.WithActor(actorFromRequest(r)))
+	if strings.Contains(r.Header.Get("Accept"), sse.ContentType) {
+		serveGraphQLEvents(w, graphqlSchema.Subscribe(ctx, req))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(graphqlSchema.Execute(ctx, req))
+}
+
+// graphqlRequestFrom GETのクエリパラメーターかPOSTの本文からリクエストを読み込む
+func graphqlRequestFrom(r *http.Request) (graphql.Request, error) {
+	var req graphql.Request
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return req, errors.New("variables はJSONのオブジェクトで指定してください")
+			}
+		}
+	} else if err := json.NewDecoder(io.LimitReader(r.Body, graphqlMaxRequestBytes)).Decode(&req); err != nil {
+		return req, err
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return req, errors.New("query を指定してください")
+	}
+	return req, nil
+}
+
+// serveGraphQLEvents 応答をServer-Sent Eventsで送る。すべて送ったら complete を送る
+func serveGraphQLEvents(w http.ResponseWriter, responses <-chan *graphql.Response) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", sse.ContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case resp, ok := <-responses:
+			if !ok {
+				sse.Write(w, sse.Event{Type: graphqlComplete})
+				flusher.Flush()
+				return
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				return
+			}
+			if err := sse.Write(w, sse.Event{Type: graphqlNext, Data: string(data)}); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := sse.WriteComment(w, "heartbeat"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// serveGraphQLWebSocket graphql-transport-ws で subscription を含む操作を受け付ける
+func (h *TaskHandler) serveGraphQLWebSocket(w http.ResponseWriter, r *http.Request) {
+	ctrl := h.controller.WithActor(actorFromRequest(r))
+	websocket.Server{
+		// APIは同一オリジン以外からも利用するため、Originは確認しない
+		Handshake: func(config *websocket.Config, _ *http.Request) error {
+			if len(config.Protocol) == 0 {
+				return nil
+			}
+			for _, p := range config.Protocol {
+				if p == graphqlWSProtocol {
+					config.Protocol = []string{graphqlWSProtocol}
+					return nil
+				}
+			}
+			return websocket.ErrBadWebSocketProtocol
+		},
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = graphqlMaxRequestBytes
+			serveGraphQLConn(withGraphQLController(r.Context(), ctrl), ws)
+		},
+	}.ServeHTTP(w, r)
+}
+
+// serveGraphQLConn 接続が切れるまで graphql-transport-ws のメッセージをやり取りする
+func serveGraphQLConn(ctx context.Context, ws *websocket.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	var sendMu, mu sync.Mutex
+	operations := map[string]context.CancelFunc{}
+	defer func() {
+		cancel()
+		ws.Close()
+	}()
+
+	send := func(msg graphqlWSMessage) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return websocket.Message.Send(ws, string(data))
+	}
+
+	// 最初のメッセージは connection_init でなければならない
+	ws.SetReadDeadline(time.Now().Add(graphqlInitTimeout))
+	var init graphqlWSMessage
+	if err := websocket.JSON.Receive(ws, &init); err != nil || init.Type != graphqlConnectionInit {
+		return
+	}
+	ws.SetReadDeadline(time.Time{})
+	if err := send(graphqlWSMessage{Type: graphqlConnectionAck}); err != nil {
+		return
+	}
+
+	for {
+		var msg graphqlWSMessage
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case graphqlPing:
+			send(graphqlWSMessage{Type: graphqlPong})
+		case graphqlPong:
+		case graphqlSubscribe:
+			var req graphql.Request
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+				return
+			}
+			mu.Lock()
+			_, exists := operations[msg.ID]
+			opCtx, opCancel := context.WithCancel(ctx)
+			if !exists {
+				operations[msg.ID] = opCancel
+			}
+			mu.Unlock()
+			if exists {
+				// 同じIDの操作が実行中の場合、プロトコルでは接続を閉じる
+				opCancel()
+				return
+			}
+
+			go func(id string) {
+				defer func() {
+					mu.Lock()
+					delete(operations, id)
+					mu.Unlock()
+					opCancel()
+				}()
+				for resp := range graphqlSchema.Subscribe(opCtx, req) {
+					if resp.Data == nil && len(resp.Errors) > 0 {
+						payload, _ := json.Marshal(resp.Errors)
+						send(graphqlWSMessage{ID: id, Type: graphqlError, Payload: payload})
+						return
+					}
+					payload, _ := json.Marshal(resp)
+					if send(graphqlWSMessage{ID: id, Type: graphqlNext, Payload: payload}) != nil {
+						return
+					}
+				}
+				if opCtx.Err() == nil {
+					send(graphqlWSMessage{ID: id, Type: graphqlComplete})
+				}
+			}(msg.ID)
+		case graphqlComplete:
+			mu.Lock()
+			if stop, ok := operations[msg.ID]; ok {
+				stop()
+			}
+			mu.Unlock()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/graphql"
+	"task-recommender/internal/model"
+	"task-recommender/internal/quickadd"
+)
+
+type graphqlContextKey int
+
+const (
+	graphqlControllerKey graphqlContextKey = iota
+	graphqlLoadersKey
+)
+
+// graphqlLoaders 1回の実行（mutation ではルートのフィールド1つ）の中でタスクや変更履歴をまとめて読み込むローダー
+type graphqlLoaders struct {
+	tasks        *graphql.Loader[int, model.Task]
+	history      *graphql.Loader[int, []model.HistoryEntry]
+	projectTasks *graphql.Loader[string, []model.Task]
+	tagTasks     *graphql.Loader[string, []model.Task]
+}
+
+func newGraphQLLoaders(ctrl *controller.TaskController) *graphqlLoaders {
+	return &graphqlLoaders{
+		tasks: graphql.NewLoader(func(ids []int) (map[int]model.Task, error) {
+			tasks, err := ctrl.GetTasks(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]model.Task, len(tasks))
+			for _, t := range tasks {
+				byID[t.ID] = t
+			}
+			return byID, nil
+		}),
+		history: graphql.NewLoader(func(ids []int) (map[int][]model.HistoryEntry, error) {
+			entries, err := ctrl.ListHistoryForTasks(ids)
+			if err != nil {
+				return nil, err
+			}
+			byTask := map[int][]model.HistoryEntry{}
+			for _, e := range entries {
+				byTask[e.TaskID] = append(byTask[e.TaskID], e)
+			}
+			return byTask, nil
+		}),
+		projectTasks: graphql.NewLoader(func(projects []string) (map[string][]model.Task, error) {
+			tasks, err := ctrl.ListTasksInProjects(projects)
+			if err != nil {
+				return nil, err
+			}
+			byProject := map[string][]model.Task{}
+			for _, t := range tasks {
+				byProject[t.Project] = append(byProject[t.Project], t)
+			}
+			return byProject, nil
+		}),
+		tagTasks: graphql.NewLoader(func(tags []string) (map[string][]model.Task, error) {
+			tasks, err := ctrl.ListTasksWithTags(tags)
+			if err != nil {
+				return nil, err
+			}
+			byTag := map[string][]model.Task{}
+			for _, t := range tasks {
+				for _, tag := range t.Contexts {
+					byTag[tag] = append(byTag[tag], t)
+				}
+			}
+			return byTag, nil
+		}),
+	}
+}
+
+// withGraphQLController リゾルバーが使うコントローラーをcontextに設定する
+func withGraphQLController(ctx context.Context, ctrl *controller.TaskController) context.Context {
+	return context.WithValue(ctx, graphqlControllerKey, ctrl)
+}
+
+func graphqlController(ctx context.Context) *controller.TaskController {
+	return ctx.Value(graphqlControllerKey).(*controller.TaskController)
+}
+
+func loaders(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey).(*graphqlLoaders)
+}
+
+// graphqlSchema タスク、プロジェクト、タグ、おすすめを公開するスキーマ
+//
+// 型の一覧:
+//
+//	type Task { id, externalId, title, description, status, statusChangedAt, priority, dueDate,
+//	            estimatedDuration, trackedDuration, project, tags, checklist, createdAt, completedAt, version, history }
+//	type HistoryEntry { id, action, field, oldValue, newValue, actor, createdAt, task }
+//	type Project { name, taskCount, openTaskCount, tasks(status) }
+//	type Tag { name, taskCount, tasks(status) }
+//	type Recommendation { task, score, reasons }
+//	type TaskEvent { id, type, taskId, project, field, oldValue, newValue, actor, occurredAt, task }
+//
+// タスクの参照 (HistoryEntry.task, TaskEvent.task)、変更履歴、プロジェクトとタグのタスクはローダーでまとめて読み込む
+var graphqlSchema = newGraphQLSchema()
+
+func newGraphQLSchema() *graphql.Schema {
+	task := &graphql.Object{Name: "Task"}
+	historyEntry := &graphql.Object{Name: "HistoryEntry"}
+	checklist := &graphql.Object{Name: "ChecklistProgress", Fields: graphql.Fields{
+		"done":  {Type: graphql.NonNullOf(graphql.Int)},
+		"total": {Type: graphql.NonNullOf(graphql.Int)},
+	}}
+	nonNullString := graphql.NonNullOf(graphql.String)
+	stringList := graphql.NonNullOf(graphql.ListOf(nonNullString))
+	taskList := graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(task)))
+	statusArg := graphql.Args{"status": {Type: graphql.String}}
+
+	task.Fields = graphql.Fields{
+		"id":                {Type: graphql.NonNullOf(graphql.ID)},
+		"externalId":        {Type: graphql.String, Resolve: stringOrNull(func(t model.Task) string { return t.ExternalID })},
+		"title":             {Type: nonNullString},
+		"description":       {Type: nonNullString},
+		"status":            {Type: nonNullString},
+		"statusChangedAt":   {Type: graphql.DateTime},
+		"priority":          {Type: graphql.NonNullOf(graphql.Int)},
+		"dueDate":           {Type: graphql.DateTime},
+		"estimatedDuration": {Type: graphql.NonNullOf(graphql.Int)},
+		"trackedDuration":   {Type: graphql.NonNullOf(graphql.Int)},
+		"project":           {Type: graphql.String, Resolve: stringOrNull(func(t model.Task) string { return t.Project })},
+		"tags": {Type: stringList, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(model.Task).Contexts, nil
+		}},
+		"checklist":   {Type: graphql.NonNullOf(checklist)},
+		"createdAt":   {Type: graphql.DateTime},
+		"completedAt": {Type: graphql.DateTime},
+		"version":     {Type: graphql.NonNullOf(graphql.Int)},
+		"history": {Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(historyEntry))), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loaders(p.Context).history.LoadOr(p.Source.(model.Task).ID), nil
+		}},
+	}
+
+	historyEntry.Fields = graphql.Fields{
+		"id":        {Type: graphql.NonNullOf(graphql.ID)},
+		"action":    {Type: nonNullString},
+		"field":     {Type: graphql.String},
+		"oldValue":  {Type: graphql.String},
+		"newValue":  {Type: graphql.String},
+		"actor":     {Type: nonNullString},
+		"createdAt": {Type: graphql.NonNullOf(graphql.DateTime)},
+		"task": {Type: task, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loaders(p.Context).tasks.Load(p.Source.(model.HistoryEntry).TaskID), nil
+		}},
+	}
+
+	project := &graphql.Object{Name: "Project", Fields: graphql.Fields{
+		"name":          {Type: nonNullString},
+		"taskCount":     {Type: graphql.NonNullOf(graphql.Int)},
+		"openTaskCount": {Type: graphql.NonNullOf(graphql.Int)},
+		"tasks": {Type: taskList, Args: statusArg, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			thunk := loaders(p.Context).projectTasks.LoadOr(p.Source.(model.ProjectSummary).Name)
+			return filterTasksThunk(thunk, p.Args), nil
+		}},
+	}}
+
+	tag := &graphql.Object{Name: "Tag", Fields: graphql.Fields{
+		"name":      {Type: nonNullString},
+		"taskCount": {Type: graphql.NonNullOf(graphql.Int)},
+		"tasks": {Type: taskList, Args: statusArg, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			thunk := loaders(p.Context).tagTasks.LoadOr(p.Source.(model.TagSummary).Name)
+			return filterTasksThunk(thunk, p.Args), nil
+		}},
+	}}
+
+	recommendation := &graphql.Object{Name: "Recommendation", Fields: graphql.Fields{
+		"task":    {Type: graphql.NonNullOf(task)},
+		"score":   {Type: graphql.NonNullOf(graphql.Float)},
+		"reasons": {Type: stringList},
+	}}
+
+	taskEvent := &graphql.Object{Name: "TaskEvent", Fields: graphql.Fields{
+		"id":         {Type: graphql.NonNullOf(graphql.ID)},
+		"type":       {Type: nonNullString},
+		"taskId":     {Type: graphql.NonNullOf(graphql.ID)},
+		"project":    {Type: graphql.String},
+		"field":      {Type: graphql.String},
+		"oldValue":   {Type: graphql.String},
+		"newValue":   {Type: graphql.String},
+		"actor":      {Type: nonNullString},
+		"occurredAt": {Type: graphql.NonNullOf(graphql.DateTime)},
+		"task": {Type: task, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loaders(p.Context).tasks.Load(p.Source.(model.TaskEvent).TaskID), nil
+		}},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: graphql.Fields{
+		"task": {
+			Type: task,
+			Args: graphql.Args{"id": {Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := graphqlTaskID(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				return loaders(p.Context).tasks.Load(id), nil
+			},
+		},
+		"tasks": {
+			Type: taskList,
+			Args: graphql.Args{
+				"status":  {Type: graphql.String},
+				"project": {Type: graphql.String},
+				"tag":     {Type: graphql.String},
+				"limit":   {Type: graphql.Int},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				all, err := graphqlController(p.Context).ListTasks()
+				if err != nil {
+					return nil, err
+				}
+				tasks := filterTasks(all.([]model.Task), p.Args)
+				if limit, ok := p.Args["limit"].(int); ok && limit >= 0 && limit < len(tasks) {
+					tasks = tasks[:limit]
+				}
+				return tasks, nil
+			},
+		},
+		"projects": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(project))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return graphqlController(p.Context).ListProjects()
+			},
+		},
+		"project": {
+			Type: project,
+			Args: graphql.Args{"name": {Type: nonNullString}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				projects, err := graphqlController(p.Context).ListProjects()
+				if err != nil {
+					return nil, err
+				}
+				for _, pr := range projects {
+					if pr.Name == p.Args["name"] {
+						return pr, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+		"tags": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(tag))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return graphqlController(p.Context).ListTags()
+			},
+		},
+		"recommendations": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(recommendation))),
+			Args: graphql.Args{"limit": {Type: graphql.Int}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				limit, _ := p.Args["limit"].(int)
+				return graphqlController(p.Context).RecommendTasks(limit)
+			},
+		},
+		"events": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(taskEvent))),
+			Args: graphql.Args{"after": {Type: graphql.ID}, "limit": {Type: graphql.Int}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var after int64
+				if s, ok := p.Args["after"].(string); ok {
+					var err error
+					if after, err = strconv.ParseInt(s, 10, 64); err != nil {
+						return nil, fmt.Errorf("イベントIDが不正です: %s", s)
+					}
+				}
+				limit, _ := p.Args["limit"].(int)
+				return graphqlController(p.Context).ListEvents(after, limit)
+			},
+		},
+	}}
+
+	versionArg := &graphql.Arg{Type: graphql.Int}
+	mutation := &graphql.Object{Name: "Mutation", Fields: graphql.Fields{
+		"createTask": {
+			Type: graphql.NonNullOf(task),
+			Args: graphql.Args{
+				"title":             {Type: nonNullString},
+				"description":       {Type: graphql.String},
+				"priority":          {Type: graphql.Int, DefaultValue: quickadd.DefaultPriority},
+				"dueDate":           {Type: graphql.String},
+				"estimatedDuration": {Type: graphql.Int},
+				"project":           {Type: graphql.String},
+				"tags":              {Type: graphql.ListOf(nonNullString)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				t := model.Task{Title: p.Args["title"].(string)}
+				t.Description, _ = p.Args["description"].(string)
+				t.Priority, _ = p.Args["priority"].(int)
+				t.EstimatedDuration, _ = p.Args["estimatedDuration"].(int)
+				t.Project, _ = p.Args["project"].(string)
+				if tags, ok := p.Args["tags"].([]interface{}); ok {
+					for _, tag := range tags {
+						t.Contexts = append(t.Contexts, tag.(string))
+					}
+				}
+				if s, ok := p.Args["dueDate"].(string); ok && s != "" {
+					dueDate, err := time.Parse("2006-01-02", s)
+					if err != nil {
+						return nil, fmt.Errorf("日付の形式が不正です。YYYY-MM-DD形式で指定してください")
+					}
+					t.DueDate = dueDate
+				}
+				return createGraphQLTask(p.Context, t)
+			},
+		},
+		"quickAddTask": {
+			Type: graphql.NonNullOf(task),
+			Args: graphql.Args{"text": {Type: nonNullString}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return createGraphQLTask(p.Context, quickadd.Parse(p.Args["text"].(string), time.Now()).Task())
+			},
+		},
+		"completeTask": {
+			Type: graphql.NonNullOf(task),
+			Args: graphql.Args{"id": {Type: graphql.NonNullOf(graphql.ID)}, "version": versionArg},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return updateGraphQLTask(p, func(ctrl *controller.TaskController, id int) error {
+					return ctrl.CompleteTask(id)
+				})
+			},
+		},
+		"setTaskStatus": {
+			Type: graphql.NonNullOf(task),
+			Args: graphql.Args{"id": {Type: graphql.NonNullOf(graphql.ID)}, "status": {Type: nonNullString}, "version": versionArg},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				status := model.Status(p.Args["status"].(string))
+				if !status.Valid() {
+					return nil, fmt.Errorf("不正な状態です: %s", status)
+				}
+				return updateGraphQLTask(p, func(ctrl *controller.TaskController, id int) error {
+					return ctrl.TransitionStatus(id, status)
+				})
+			},
+		},
+		"deleteTask": {
+			Type: graphql.NonNullOf(graphql.Boolean),
+			Args: graphql.Args{"id": {Type: graphql.NonNullOf(graphql.ID)}, "version": versionArg},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := graphqlTaskID(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				if err := graphqlWriter(p).DeleteTask(id); err != nil {
+					return nil, err
+				}
+				return true, nil
+			},
+		},
+	}}
+
+	subscription := &graphql.Object{Name: "Subscription", Fields: graphql.Fields{
+		"taskEvents": {
+			Type:      graphql.NonNullOf(taskEvent),
+			Args:      graphql.Args{"types": {Type: graphql.ListOf(nonNullString)}, "project": {Type: graphql.String}},
+			Subscribe: subscribeTaskEvents,
+		},
+	}}
+
+	return &graphql.Schema{
+		Query:        query,
+		Mutation:     mutation,
+		Subscription: subscription,
+		Prepare: func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, graphqlLoadersKey, newGraphQLLoaders(graphqlController(ctx)))
+		},
+	}
+}
+
+// stringOrNull 空文字列を null として返すリゾルバー
+func stringOrNull(get func(model.Task) string) graphql.ResolveFunc {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if s := get(p.Source.(model.Task)); s != "" {
+			return s, nil
+		}
+		return nil, nil
+	}
+}
+
+// filterTasks 状態、プロジェクト、タグの引数で絞り込む
+func filterTasks(tasks []model.Task, args map[string]interface{}) []model.Task {
+	var f model.TaskFilter
+	if status, ok := args["status"].(string); ok {
+		f.Status = model.Status(status)
+	}
+	f.Project, _ = args["project"].(string)
+	f.Context, _ = args["tag"].(string)
+	return f.Filter(tasks)
+}
+
+// filterTasksThunk ローダーが読み込んだタスクを引数で絞り込む
+func filterTasksThunk(thunk graphql.Thunk, args map[string]interface{}) graphql.Thunk {
+	return func() (interface{}, error) {
+		v, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		return filterTasks(v.([]model.Task), args), nil
+	}
+}
+
+func graphqlTaskID(args map[string]interface{}) (int, error) {
+	s, _ := args["id"].(string)
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("タスクIDが不正です: %s", s)
+	}
+	return id, nil
+}
+
+// graphqlWriter version の引数を反映したコントローラー
+func graphqlWriter(p graphql.ResolveParams) *controller.TaskController {
+	ctrl := graphqlController(p.Context)
+	if version, ok := p.Args["version"].(int); ok {
+		ctrl = ctrl.IfVersion(version)
+	}
+	return ctrl
+}
+
+func createGraphQLTask(ctx context.Context, t model.Task) (interface{}, error) {
+	ctrl := graphqlController(ctx)
+	id, err := ctrl.CreateTask(t)
+	if err != nil {
+		return nil, err
+	}
+	return ctrl.GetTask(id)
+}
+
+// updateGraphQLTask タスクを更新し、更新後のタスクを返す
+func updateGraphQLTask(p graphql.ResolveParams, update func(ctrl *controller.TaskController, id int) error) (interface{}, error) {
+	id, err := graphqlTaskID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(graphqlWriter(p), id); err != nil {
+		return nil, err
+	}
+	return graphqlController(p.Context).GetTask(id)
+}
+
+// subscribeTaskEvents 購読を始めた後のタスクのイベントを送る。読み込みに失敗した場合は購読を終える
+func subscribeTaskEvents(p graphql.ResolveParams) (<-chan interface{}, error) {
+	ctrl := graphqlController(p.Context)
+	types := map[model.EventType]bool{}
+	if list, ok := p.Args["types"].([]interface{}); ok {
+		for _, v := range list {
+			t := model.EventType(v.(string))
+			if !t.Valid() {
+				return nil, fmt.Errorf("不明なイベントの種類です: %s", t)
+			}
+			types[t] = true
+		}
+	}
+	project, hasProject := p.Args["project"].(string)
+
+	lastID, err := ctrl.LatestEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan interface{})
+	go func() {
+		defer close(events)
+		poll := time.NewTicker(eventPollInterval)
+		defer poll.Stop()
+		for {
+			select {
+			case <-p.Context.Done():
+				return
+			case <-poll.C:
+			}
+
+			batch, err := ctrl.ListEvents(lastID, eventBatchSize)
+			if err != nil {
+				return
+			}
+			for _, e := range batch {
+				lastID = e.ID
+				if len(types) > 0 && !types[e.Type] || hasProject && e.Project != project {
+					continue
+				}
+				select {
+				case events <- e:
+				case <-p.Context.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,32 +10,90 @@ import (
 	"time"
 
 	"task-recommender/internal/controller"
+	"task-recommender/internal/model"
+	"task-recommender/internal/service"
+	"task-recommender/internal/view"
 )
 
 type TaskHandler struct {
-	controller *controller.TaskController
+	controller         *controller.TaskController
+	calendarToken      string
+	slackSigningSecret string
+	board              *board
 }
 
-func NewTaskHandler(controller *controller.TaskController) *TaskHandler {
-	return &TaskHandler{controller: controller}
+func NewTaskHandler(controller *controller.TaskController, config Config) *TaskHandler {
+	return &TaskHandler{
+		controller:         controller,
+		calendarToken:      config.CalendarToken,
+		slackSigningSecret: config.SlackSigningSecret,
+		board:              newBoard(controller),
+	}
 }
 
 // @Summary タスク一覧を取得
-// @Description すべてのタスクの一覧を取得します
+// @Description すべてのタスクの一覧を取得します。Acceptヘッダーで出力形式 (JSON, YAML, Markdown, HTML, テキストの表) を選べます
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Produce application/yaml
+// @Produce text/markdown
+// @Produce text/html
+// @Produce text/plain
+// @Param Accept header string false "出力形式 (application/json, application/yaml, text/markdown, text/html, text/plain)"
 // @Success 200 {array} model.Task
+// @Failure 406 {object} string "対応していない形式です"
 // @Router /tasks [get]
 func (h *TaskHandler) HandleListTasks(w http.ResponseWriter, r *http.Request) {
+	renderer, ok := view.NegotiateRenderer(r.Header.Get("Accept"), "json")
+	if !ok {
+		http.Error(w, "対応していない形式です", http.StatusNotAcceptable)
+		return
+	}
+
 	tasks, err := h.controller.ListTasks()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.Header().Set("Vary", "Accept")
+	renderer.RenderTasks(w, tasks.([]model.Task))
+}
+
+// @Summary タスクを取得
+// @Description 指定されたIDのタスクを取得します。ETagヘッダーにタスクのバージョンを返すので、更新時にIf-Matchヘッダーで指定すると他のユーザーの更新を上書きせずに済みます
+// @Tags tasks
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param If-None-Match header string false "前回取得したETag。一致する場合は304を返す"
+// @Success 200 {object} model.Task
+// @Success 304 {object} string "変更されていません"
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id} [get]
+func (h *TaskHandler) HandleGetTask(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.controller.GetTask(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("ETag", etag(task.Version))
+	if r.Header.Get("If-None-Match") == etag(task.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tasks)
+	json.NewEncoder(w).Encode(task)
 }
 
 // @Summary 新しいタスクを作成
@@ -42,19 +101,13 @@ func (h *TaskHandler) HandleListTasks(w http.ResponseWriter, r *http.Request) {
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param task body object true "タスク情報"
-// @Success 201 {object} map[string]int
+// @Param task body createTaskRequest true "タスク情報"
+// @Success 201 {object} createdResponse
 // @Failure 400 {object} string "不正なリクエスト"
 // @Failure 500 {object} string "サーバーエラー"
 // @Router /tasks [post]
 func (h *TaskHandler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
-	var task struct {
-		Title             string `json:"title"`
-		Description       string `json:"description"`
-		Priority          int    `json:"priority"`
-		DueDate           string `json:"due_date"`
-		EstimatedDuration int    `json:"estimated_duration"`
-	}
+	var task createTaskRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -71,7 +124,7 @@ func (h *TaskHandler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	id, err := h.controller.AddTask(task.Title, task.Description, task.Priority, dueDate, task.EstimatedDuration)
+	id, err := h.controller.WithActor(actorFromRequest(r)).AddTask(task.Title, task.Description, task.Priority, dueDate, task.EstimatedDuration)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -79,7 +132,7 @@ func (h *TaskHandler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]int{"id": id})
+	json.NewEncoder(w).Encode(createdResponse{ID: id})
 }
 
 // @Summary タスクを完了としてマーク
@@ -88,8 +141,12 @@ func (h *TaskHandler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "タスクID"
-// @Success 200 {object} map[string]string
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
 // @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 409 {object} string "この状態には遷移できません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
 // @Failure 500 {object} string "サーバーエラー"
 // @Router /tasks/{id}/complete [put]
 func (h *TaskHandler) HandleCompleteTask(w http.ResponseWriter, r *http.Request) {
@@ -99,24 +156,73 @@ func (h *TaskHandler) HandleCompleteTask(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.controller.CompleteTask(id)
+	ctrl, err := h.taskWriter(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.CompleteTask(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	h.setETag(w, id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "completed"})
+}
+
+// @Summary 完了したタスクを再開
+// @Description 指定されたIDの完了または中止したタスクを未着手に戻し、完了日時をクリアします
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param X-User header string false "操作ユーザー名"
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 409 {object} string "完了または中止していないタスクは再開できません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/reopen [put]
+func (h *TaskHandler) HandleReopenTask(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	ctrl, err := h.taskWriter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.ReopenTask(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
 		return
 	}
 
+	h.setETag(w, id)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "completed"})
+	json.NewEncoder(w).Encode(statusResponse{Status: "reopened"})
 }
 
 // @Summary タスクを削除
-// @Description 指定されたIDのタスクを削除します
+// @Description 指定されたIDのタスクをゴミ箱に移動します。保持期間を過ぎると完全に削除されます
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "タスクID"
-// @Success 200 {object} map[string]string
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
 // @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
 // @Failure 500 {object} string "サーバーエラー"
 // @Router /tasks/{id} [delete]
 func (h *TaskHandler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
@@ -126,14 +232,126 @@ func (h *TaskHandler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.controller.DeleteTask(id)
+	ctrl, err := h.taskWriter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.DeleteTask(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "deleted"})
+}
+
+// @Summary タスクの変更履歴を取得
+// @Description 指定されたIDのタスクに対する作成・更新・削除などの操作履歴を、操作ユーザー・日時・変更前後の値とともに取得します
+// @Tags history
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {array} model.HistoryEntry
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/history [get]
+func (h *TaskHandler) HandleListHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.controller.ListHistory(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// @Summary おすすめのタスクを取得
+// @Description 優先度・期限・着手状況・残りの作業量（チェックリストの未完了の割合を反映）から、次に取り組むべきタスクを返します
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param limit query int false "取得件数 (既定値: 5)"
+// @Success 200 {array} model.Recommendation
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /recommendations [get]
+func (h *TaskHandler) HandleRecommendTasks(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	recommendations, err := h.controller.RecommendTasks(limit)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
+
+// @Summary ゴミ箱のタスク一覧を取得
+// @Description 削除されたタスクを削除日時の新しい順に取得します
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Task
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /trash [get]
+func (h *TaskHandler) HandleListTrash(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.controller.ListTrash()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+// @Summary ゴミ箱のタスクを復元
+// @Description 指定されたIDの削除されたタスクを元に戻します
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "ゴミ箱にタスクが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/restore [put]
+func (h *TaskHandler) HandleRestoreTask(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.controller.WithActor(actorFromRequest(r)).RestoreTask(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+	json.NewEncoder(w).Encode(statusResponse{Status: "restored"})
 }
 
 // @Summary タスクの優先度を更新
@@ -142,9 +360,12 @@ func (h *TaskHandler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "タスクID"
-// @Param priority body object true "優先度情報"
-// @Success 200 {object} map[string]string
+// @Param priority body priorityRequest true "優先度情報"
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
 // @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
 // @Failure 500 {object} string "サーバーエラー"
 // @Router /tasks/{id}/priority [put]
 func (h *TaskHandler) HandleUpdatePriority(w http.ResponseWriter, r *http.Request) {
@@ -154,23 +375,28 @@ func (h *TaskHandler) HandleUpdatePriority(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var data struct {
-		Priority int `json:"priority"`
-	}
+	var data priorityRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	err = h.controller.UpdatePriority(id, data.Priority)
+	ctrl, err := h.taskWriter(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.UpdatePriority(id, data.Priority)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
 		return
 	}
 
+	h.setETag(w, id)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "priority updated"})
+	json.NewEncoder(w).Encode(statusResponse{Status: "priority updated"})
 }
 
 // @Summary タスクの期限日を更新
@@ -179,9 +405,12 @@ func (h *TaskHandler) HandleUpdatePriority(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param id path int true "タスクID"
-// @Param dueDate body object true "期限日情報"
-// @Success 200 {object} map[string]string
+// @Param dueDate body dueDateRequest true "期限日情報"
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
 // @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
 // @Failure 500 {object} string "サーバーエラー"
 // @Router /tasks/{id}/due [put]
 func (h *TaskHandler) HandleUpdateDueDate(w http.ResponseWriter, r *http.Request) {
@@ -191,9 +420,7 @@ func (h *TaskHandler) HandleUpdateDueDate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	var data struct {
-		DueDate string `json:"due_date"`
-	}
+	var data dueDateRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -206,14 +433,21 @@ func (h *TaskHandler) HandleUpdateDueDate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = h.controller.UpdateDueDate(id, dueDate)
+	ctrl, err := h.taskWriter(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.UpdateDueDate(id, dueDate)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
 		return
 	}
 
+	h.setETag(w, id)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "due date updated"})
+	json.NewEncoder(w).Encode(statusResponse{Status: "due date updated"})
 }
 
 // @Summary タスクの見積時間を更新
@@ -222,9 +456,12 @@ func (h *TaskHandler) HandleUpdateDueDate(w http.ResponseWriter, r *http.Request
 // @Accept json
 // @Produce json
 // @Param id path int true "タスクID"
-// @Param duration body object true "見積時間情報"
-// @Success 200 {object} map[string]string
+// @Param duration body durationRequest true "見積時間情報"
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
 // @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
 // @Failure 500 {object} string "サーバーエラー"
 // @Router /tasks/{id}/duration [put]
 func (h *TaskHandler) HandleUpdateEstimatedDuration(w http.ResponseWriter, r *http.Request) {
@@ -234,23 +471,227 @@ func (h *TaskHandler) HandleUpdateEstimatedDuration(w http.ResponseWriter, r *ht
 		return
 	}
 
-	var data struct {
-		Duration int `json:"duration"`
-	}
+	var data durationRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	err = h.controller.UpdateEstimatedDuration(id, data.Duration)
+	ctrl, err := h.taskWriter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.UpdateEstimatedDuration(id, data.Duration)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	h.setETag(w, id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statusResponse{Status: "duration updated"})
+}
+
+// @Summary タスクの時間計測を開始
+// @Description 指定されたIDのタスクの時間計測を開始します。計測中のタイマーはユーザーごとに1つまでです
+// @Tags timers
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 201 {object} model.TimeEntry
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 409 {object} string "既に計測中のタイマーがあります"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/start [post]
+func (h *TaskHandler) HandleStartTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.controller.WithActor(actorFromRequest(r)).StartTimer(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary タスクの時間計測を停止
+// @Description 指定されたIDのタスクで計測中のタイマーを停止します
+// @Tags timers
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} model.TimeEntry
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 409 {object} string "計測中のタイマーがありません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/stop [post]
+func (h *TaskHandler) HandleStopTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.controller.WithActor(actorFromRequest(r)).StopTimer(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary タスクの時間記録一覧を取得
+// @Description 指定されたIDのタスクの時間記録を取得します
+// @Tags timers
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {array} model.TimeEntry
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/time [get]
+func (h *TaskHandler) HandleListTimeEntries(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.controller.ListTimeEntries(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// @Summary タスクの状態を遷移
+// @Description 指定されたIDのタスクの状態を遷移させます (todo → in_progress → done, blocked, cancelled)
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Param status body statusRequest true "状態情報"
+// @Param X-User header string false "操作ユーザー名"
+// @Param If-Match header string false "更新前に取得したETag（カンマ区切りで複数指定可）。いずれとも一致しない場合は412を返す"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "タスクが見つかりません"
+// @Failure 409 {object} string "この状態には遷移できません"
+// @Failure 412 {object} string "タスクが他のユーザーによって更新されています"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/status [put]
+func (h *TaskHandler) HandleTransitionStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var data statusRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := model.ParseStatus(data.Status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctrl, err := h.taskWriter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = ctrl.TransitionStatus(id, status)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	h.setETag(w, id)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "duration updated"})
+	json.NewEncoder(w).Encode(statusResponse{Status: string(status)})
+}
+
+// @Summary タスクの状態遷移の履歴を取得
+// @Description 指定されたIDのタスクの状態遷移と各遷移の日時を取得します
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "タスクID"
+// @Success 200 {array} model.StatusTransition
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/{id}/transitions [get]
+func (h *TaskHandler) HandleListStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	transitions, err := h.controller.ListStatusTransitions(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transitions)
+}
+
+// actorFromRequest リクエストヘッダーから操作ユーザー名を取得するヘルパー関数
+func actorFromRequest(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get("X-User"))
+}
+
+// statusFromError サービスのエラーをHTTPステータスに変換するヘルパー関数
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrTaskNotInTrash),
+		errors.Is(err, service.ErrCommentNotFound), errors.Is(err, service.ErrAttachmentNotFound),
+		errors.Is(err, service.ErrChecklistItemNotFound), errors.Is(err, service.ErrWebhookNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrBlobStoreNotConfigured):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrNotCommentAuthor):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrEmptyComment), errors.Is(err, service.ErrEmptyChecklistItem),
+		errors.Is(err, service.ErrInvalidChecklistOrder), errors.Is(err, service.ErrInvalidWebhook),
+		errors.Is(err, service.ErrInvalidTask):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, service.ErrTimerAlreadyRunning), errors.Is(err, service.ErrTimerNotRunning),
+		errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrTaskNotClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 // getIDFromPath URLパスからIDを抽出するヘルパー関数
@@ -261,3 +702,20 @@ func getIDFromPath(path string) (int, error) {
 	}
 	return strconv.Atoi(parts[2])
 }
+
+// getSubIDFromPath /tasks/{id}/{resource}/{subID} からタスクIDとサブリソースのIDを抽出するヘルパー関数
+func getSubIDFromPath(path string) (int, int, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 5 {
+		return 0, 0, fmt.Errorf("invalid path")
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, 0, err
+	}
+	subID, err := strconv.Atoi(parts[4])
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, subID, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"task-recommender/internal/quickadd"
+)
+
+// @Summary 自然文からタスクを追加
+// @Description 「明日までにレポート提出 !高 90分」や「submit report tomorrow p3 1h30m #work」のような文から
+// @Description タイトル、優先度、期限日、見積時間、プロジェクト (+name)、状況 (#tag, @tag) を読み取ってタスクを作成します。
+// @Description 今日、明日、来週金曜、next Friday などの相対的な日付はサーバーの日付を基準にします。
+// @Description dry_run を true にすると作成せずに解釈した結果だけを返します
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body quickAddRequest true "自然文"
+// @Success 201 {object} model.Task
+// @Success 200 {object} model.Task "dry_run の場合"
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/quick [post]
+func (h *TaskHandler) HandleQuickAddTask(w http.ResponseWriter, r *http.Request) {
+	var req quickAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		http.Error(w, "text を指定してください", http.StatusBadRequest)
+		return
+	}
+
+	task := quickadd.Parse(req.Text, time.Now()).Task()
+	if req.DryRun {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(task)
+		return
+	}
+
+	ctrl := h.controller.WithActor(actorFromRequest(r))
+	id, err := ctrl.CreateTask(task)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	created, err := ctrl.GetTask(id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
@@ -1,24 +1,130 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	"task-recommender/internal/controller"
 )
 
-// SetupRouter ルーターを設定
-func SetupRouter(taskController *controller.TaskController) http.Handler {
-	mux := http.NewServeMux()
+// APIVersionPrefix APIのルートの前に付けるパス。OpenAPIの定義の basePath と一致させる
+const APIVersionPrefix = "/v1"
+
+// legacyDeprecatedAt バージョンなしの旧パスを非推奨にした日時
+var legacyDeprecatedAt = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// Config APIサーバーの設定
+type Config struct {
+	// CalendarToken カレンダー配信用のトークン。空の場合は配信しない
+	CalendarToken string
+	// SlackSigningSecret スラッシュコマンドの署名用の秘密鍵。空の場合は受け付けない
+	SlackSigningSecret string
+}
+
+// route APIのルート。path は APIVersionPrefix を除いたパスで、パラメーターは OpenAPI の定義と同じ名前にする
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+// routes APIのルートの一覧。メソッドが一致しない場合はルーターが405を返す
+func routes(h *TaskHandler) []route {
+	return []route{
+		// タスク一覧の取得と追加
+		{http.MethodGet, "/tasks", h.HandleListTasks},
+		{http.MethodPost, "/tasks", h.HandleCreateTask},
+		// 一括操作
+		{http.MethodPost, "/tasks/bulk", h.HandleBulkTasks},
+		// 自然文からの追加
+		{http.MethodPost, "/tasks/quick", h.HandleQuickAddTask},
+		// エクスポートとインポート
+		{http.MethodGet, "/tasks/export", h.HandleExportTasks},
+		{http.MethodPost, "/tasks/import", h.HandleImportTasks},
+
+		// 個別のタスク操作
+		{http.MethodGet, "/tasks/{id}", h.HandleGetTask},
+		{http.MethodDelete, "/tasks/{id}", h.HandleDeleteTask},
+		{http.MethodPut, "/tasks/{id}/complete", h.HandleCompleteTask},
+		{http.MethodPut, "/tasks/{id}/reopen", h.HandleReopenTask},
+		{http.MethodPut, "/tasks/{id}/restore", h.HandleRestoreTask},
+		{http.MethodPut, "/tasks/{id}/status", h.HandleTransitionStatus},
+		{http.MethodGet, "/tasks/{id}/transitions", h.HandleListStatusTransitions},
+		{http.MethodGet, "/tasks/{id}/history", h.HandleListHistory},
+		{http.MethodPut, "/tasks/{id}/priority", h.HandleUpdatePriority},
+		{http.MethodPut, "/tasks/{id}/due", h.HandleUpdateDueDate},
+		{http.MethodPut, "/tasks/{id}/duration", h.HandleUpdateEstimatedDuration},
+
+		// 時間計測
+		{http.MethodPost, "/tasks/{id}/start", h.HandleStartTimer},
+		{http.MethodPost, "/tasks/{id}/stop", h.HandleStopTimer},
+		{http.MethodGet, "/tasks/{id}/time", h.HandleListTimeEntries},
+
+		// チェックリスト
+		{http.MethodGet, "/tasks/{id}/checklist", h.HandleListChecklist},
+		{http.MethodPost, "/tasks/{id}/checklist", h.HandleAddChecklistItem},
+		{http.MethodPut, "/tasks/{id}/checklist/order", h.HandleReorderChecklist},
+		{http.MethodPut, "/tasks/{id}/checklist/{itemID}/toggle", h.HandleToggleChecklistItem},
+		{http.MethodDelete, "/tasks/{id}/checklist/{itemID}", h.HandleDeleteChecklistItem},
 
+		// コメント
+		{http.MethodGet, "/tasks/{id}/comments", h.HandleListComments},
+		{http.MethodPost, "/tasks/{id}/comments", h.HandleAddComment},
+		{http.MethodPut, "/tasks/{id}/comments/{commentID}", h.HandleUpdateComment},
+		{http.MethodDelete, "/tasks/{id}/comments/{commentID}", h.HandleDeleteComment},
+
+		// 添付ファイル
+		{http.MethodGet, "/tasks/{id}/attachments", h.HandleListAttachments},
+		{http.MethodPost, "/tasks/{id}/attachments", h.HandleUploadAttachment},
+		{http.MethodGet, "/tasks/{id}/attachments/{attachmentID}", h.HandleDownloadAttachment},
+		{http.MethodDelete, "/tasks/{id}/attachments/{attachmentID}", h.HandleDeleteAttachment},
+
+		// ゴミ箱の一覧
+		{http.MethodGet, "/trash", h.HandleListTrash},
+		// おすすめのタスク
+		{http.MethodGet, "/recommendations", h.HandleRecommendTasks},
+		// タスクの変更のストリーミング
+		{http.MethodGet, "/events", h.HandleEvents},
+		// ボードのWebSocket
+		{http.MethodGet, "/board", h.HandleBoard},
+
+		// GraphQL
+		{http.MethodGet, "/graphql", h.HandleGraphQL},
+		{http.MethodPost, "/graphql", h.HandleGraphQL},
+
+		// チャットのスラッシュコマンド
+		{http.MethodPost, "/slack/commands", h.HandleSlashCommand},
+		// カレンダーの配信
+		{http.MethodGet, "/calendar.ics", h.HandleCalendarFeed},
+
+		// Webhook
+		{http.MethodGet, "/webhooks", h.HandleListWebhooks},
+		{http.MethodPost, "/webhooks", h.HandleCreateWebhook},
+		{http.MethodDelete, "/webhooks/{id}", h.HandleDeleteWebhook},
+		{http.MethodGet, "/webhooks/{id}/deliveries", h.HandleListWebhookDeliveries},
+	}
+}
+
+// SetupRouter ルーターを設定
+//
+// APIは /v1 以下で提供する。バージョンなしの旧パスも同じハンドラーで受け付けるが、
+// 非推奨であることを Deprecation ヘッダーで知らせる
+func SetupRouter(taskController *controller.TaskController, config Config) http.Handler {
 	// APIハンドラーの作成
-	taskHandler := NewTaskHandler(taskController)
+	taskHandler := NewTaskHandler(taskController, config)
+
+	api := http.NewServeMux()
+	for _, rt := range routes(taskHandler) {
+		api.HandleFunc(rt.method+" "+rt.path, rt.handler)
+	}
+
+	mux := http.NewServeMux()
 
 	// ルートパス
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/" {
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			w.Write([]byte(`
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`
                 <html>
                     <head>
                         <title>タスク管理アプリケーション</title>
@@ -29,78 +135,31 @@ func SetupRouter(taskController *controller.TaskController) http.Handler {
                     </body>
                 </html>
             `))
-			return
-		}
-		http.NotFound(w, r)
 	})
 
-	// タスク一覧の取得と追加
-	mux.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			taskHandler.HandleListTasks(w, r)
-		case http.MethodPost:
-			taskHandler.HandleCreateTask(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	// Swagger UI
+	mux.Handle("/swagger/", NewSwaggerHandler())
 
-	// 個別のタスク操作
-	mux.HandleFunc("/tasks/", func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
+	// ハンドラーはバージョンなしのパスで処理する
+	mux.Handle(APIVersionPrefix+"/", http.StripPrefix(APIVersionPrefix, api))
 
-		// 完了マーク: /tasks/{id}/complete
-		if strings.HasSuffix(path, "/complete") {
-			if r.Method == http.MethodPut {
-				taskHandler.HandleCompleteTask(w, r)
-				return
-			}
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+	// 旧パス
+	mux.Handle("/", deprecated(api))
 
-		// 優先度更新: /tasks/{id}/priority
-		if strings.HasSuffix(path, "/priority") {
-			if r.Method == http.MethodPut {
-				taskHandler.HandleUpdatePriority(w, r)
-				return
-			}
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		// 期限日更新: /tasks/{id}/due
-		if strings.HasSuffix(path, "/due") {
-			if r.Method == http.MethodPut {
-				taskHandler.HandleUpdateDueDate(w, r)
-				return
-			}
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+	return mux
+}
 
-		// 見積時間更新: /tasks/{id}/duration
-		if strings.HasSuffix(path, "/duration") {
-			if r.Method == http.MethodPut {
-				taskHandler.HandleUpdateEstimatedDuration(w, r)
-				return
+// deprecated 旧パスへの応答に Deprecation ヘッダー (RFC 9745) と、/v1 のパスを示す Link ヘッダーを付ける
+func deprecated(api *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := api.Handler(r); pattern != "" {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+			successor := APIVersionPrefix + r.URL.EscapedPath()
+			if r.URL.RawQuery != "" {
+				successor += "?" + r.URL.RawQuery
 			}
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
+			w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
 		}
-
-		// タスク削除: /tasks/{id}
-		if r.Method == http.MethodDelete {
-			taskHandler.HandleDeleteTask(w, r)
-			return
-		}
-
-		http.Error(w, "Method not allowed or invalid path", http.StatusMethodNotAllowed)
+		api.ServeHTTP(w, r)
 	})
-
-	// Swagger UI
-	mux.Handle("/swagger/", NewSwaggerHandler())
-
-	return mux
 }
@@ -0,0 +1,89 @@
+package api
+
+import "task-recommender/internal/model"
+
+// リクエストとレスポンスの本文。OpenAPIの定義 (docs/swagger.json) のスキーマにもなるため、項目を変更したら swag init で定義を更新すること
+
+// createTaskRequest タスク作成のリクエスト
+type createTaskRequest struct {
+	// タスクのタイトル
+	Title string `json:"title" example:"牛乳を買う" validate:"required"`
+	// タスクの説明
+	Description string `json:"description" example:"スーパーで低脂肪牛乳を購入する"`
+	// 優先度 (1=低, 2=中, 3=高)
+	Priority int `json:"priority" example:"2" minimum:"1" maximum:"3"`
+	// 期限日 (YYYY-MM-DD)
+	DueDate string `json:"due_date" example:"2023-12-31" format:"date"`
+	// 見積所要時間（分）
+	EstimatedDuration int `json:"estimated_duration" example:"30" minimum:"0"`
+}
+
+// createdResponse 作成したリソースのID
+type createdResponse struct {
+	ID int `json:"id" example:"1"`
+}
+
+// statusResponse 更新・削除の結果
+type statusResponse struct {
+	Status string `json:"status" example:"completed"`
+}
+
+// priorityRequest 優先度更新のリクエスト
+type priorityRequest struct {
+	// 優先度 (1=低, 2=中, 3=高)
+	Priority int `json:"priority" example:"3" minimum:"1" maximum:"3" validate:"required"`
+}
+
+// dueDateRequest 期限日更新のリクエスト
+type dueDateRequest struct {
+	// 期限日 (YYYY-MM-DD)
+	DueDate string `json:"due_date" example:"2023-12-31" format:"date" validate:"required"`
+}
+
+// durationRequest 見積時間更新のリクエスト
+type durationRequest struct {
+	// 見積所要時間（分）
+	Duration int `json:"duration" example:"45" minimum:"0"`
+}
+
+// statusRequest 状態遷移のリクエスト
+type statusRequest struct {
+	// 遷移先の状態 (todo, in_progress, blocked, done, cancelled)
+	Status string `json:"status" example:"in_progress" validate:"required"`
+}
+
+// commentRequest コメントの投稿・編集のリクエスト
+type commentRequest struct {
+	// コメント本文
+	Body string `json:"body" example:"明日までに確認します" validate:"required"`
+}
+
+// checklistItemRequest チェックリストの項目追加のリクエスト
+type checklistItemRequest struct {
+	// 項目の内容
+	Title string `json:"title" example:"見積書を送る" validate:"required"`
+}
+
+// checklistOrderRequest チェックリストの並べ替えのリクエスト
+type checklistOrderRequest struct {
+	// タスクのすべての項目のIDを新しい並び順で指定する
+	ItemIDs []int `json:"item_ids" example:"3,1,2" validate:"required"`
+}
+
+// quickAddRequest 自然文からのタスク追加のリクエスト
+type quickAddRequest struct {
+	// 自然文
+	Text string `json:"text" example:"明日までにレポート提出 !高 90分 +仕事" validate:"required"`
+	// 作成せずに解釈結果だけを返す
+	DryRun bool `json:"dry_run" example:"false"`
+}
+
+// webhookRequest Webhook登録のリクエスト
+type webhookRequest struct {
+	// 送信先のURL
+	URL string `json:"url" example:"https://example.com/hooks/tasks" validate:"required"`
+	// 署名用の秘密鍵。省略した場合は生成する
+	Secret string `json:"secret" example:"s3cr3t"`
+	// 送信するイベントの種類。空の場合はすべて
+	Events []model.EventType `json:"events"`
+}
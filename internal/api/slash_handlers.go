@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/model"
+	"task-recommender/internal/slash"
+)
+
+// slashMaxBodyBytes スラッシュコマンドの本文の最大サイズ
+const slashMaxBodyBytes = 64 * 1024
+
+// @Summary チャットのスラッシュコマンド
+// @Description Slack互換のスラッシュコマンドを受け付けます。X-Slack-Signature の署名を検証したうえで、
+// @Description text の内容に応じてタスクの追加 (add 牛乳を買う p3 due:2026-10-20)、おすすめの表示 (next)、完了 (done 12) を行います。
+// @Description 操作ユーザーは user_name です。コマンドの誤りは200で使い方とともに返します
+// @Tags chat
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param X-Slack-Request-Timestamp header string true "送信日時のUNIX時刻"
+// @Param X-Slack-Signature header string true "署名 (v0=...)"
+// @Param command formData string false "コマンド名 (例: /task)"
+// @Param text formData string false "コマンドの引数"
+// @Param user_name formData string false "実行したユーザー名"
+// @Success 200 {object} slash.Response
+// @Failure 401 {object} string "署名が不正です"
+// @Failure 404 {object} string "スラッシュコマンドが無効です"
+// @Router /slack/commands [post]
+func (h *TaskHandler) HandleSlashCommand(w http.ResponseWriter, r *http.Request) {
+	if h.slackSigningSecret == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, slashMaxBodyBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := slash.Verify(h.slackSigningSecret, r.Header, body, time.Now()); err != nil {
+		http.Error(w, "署名が不正です", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctrl := h.controller.WithActor(strings.TrimSpace(form.Get("user_name")))
+	resp := runSlashCommand(ctrl, form.Get("command"), form.Get("text"))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// runSlashCommand コマンドを実行し、チャットに返す応答を作成する
+func runSlashCommand(ctrl *controller.TaskController, command, text string) slash.Response {
+	cmd, err := slash.Parse(text)
+	if err != nil {
+		help := slash.Help(command)
+		return slash.Ephemeral("%v\n%s", err, help.Text)
+	}
+
+	switch cmd.Action {
+	case slash.ActionAdd:
+		id, err := ctrl.AddTask(cmd.Title, "", cmd.Priority, cmd.DueDate, 0)
+		if err != nil {
+			return slash.Ephemeral("タスクを追加できませんでした: %v", err)
+		}
+		due := ""
+		if !cmd.DueDate.IsZero() {
+			due = ", 期限=" + cmd.DueDate.Format("2006-01-02")
+		}
+		return slash.InChannel("タスクを追加しました: #%d %s (優先度=%s%s)", id, cmd.Title, model.PriorityLabel(cmd.Priority), due)
+
+	case slash.ActionNext:
+		recommendations, err := ctrl.RecommendTasks(cmd.Limit)
+		if err != nil {
+			return slash.Ephemeral("おすすめのタスクを取得できませんでした: %v", err)
+		}
+		if len(recommendations) == 0 {
+			return slash.Ephemeral("おすすめのタスクはありません")
+		}
+		var b strings.Builder
+		b.WriteString("おすすめのタスク:")
+		for i, rec := range recommendations {
+			fmt.Fprintf(&b, "\n%d. #%d %s", i+1, rec.Task.ID, rec.Task.Title)
+			if !rec.Task.DueDate.IsZero() {
+				fmt.Fprintf(&b, " (期限=%s)", rec.Task.DueDate.Format("2006-01-02"))
+			}
+			if len(rec.Reasons) > 0 {
+				fmt.Fprintf(&b, " — %s", strings.Join(rec.Reasons, ", "))
+			}
+		}
+		return slash.Ephemeral("%s", b.String())
+
+	case slash.ActionDone:
+		task, err := ctrl.GetTask(cmd.TaskID)
+		if err == nil {
+			err = ctrl.CompleteTask(cmd.TaskID)
+		}
+		if err != nil {
+			return slash.Ephemeral("タスク #%d を完了にできませんでした: %v", cmd.TaskID, err)
+		}
+		return slash.InChannel("タスクを完了しました: #%d %s", task.ID, task.Title)
+
+	default:
+		return slash.Help(command)
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-recommender/internal/csvio"
+	"task-recommender/internal/ical"
+	"task-recommender/internal/model"
+	"task-recommender/internal/service"
+	"task-recommender/internal/todotxt"
+)
+
+// @Summary タスクをエクスポート
+// @Description ゴミ箱にないすべてのタスクを指定した形式で出力します
+// @Tags transfer
+// @Produce text/csv
+// @Produce text/calendar
+// @Produce text/plain
+// @Param format query string false "出力形式 (csv, ics, todotxt)"
+// @Success 200 {file} file
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/export [get]
+func (h *TaskHandler) HandleExportTasks(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	tasks, err := h.controller.ListTasks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="tasks.csv"`)
+		csvio.Write(w, tasks.([]model.Task))
+	case "ics":
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="tasks.ics"`)
+		ical.Write(w, ical.Calendar{Tasks: tasks.([]model.Task)}, time.Now())
+	case "todotxt":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="todo.txt"`)
+		todotxt.Write(w, tasks.([]model.Task))
+	default:
+		http.Error(w, fmt.Sprintf("未対応の形式です: %s", format), http.StatusBadRequest)
+	}
+}
+
+// @Summary タスクをインポート
+// @Description CSV、iCalendar (VTODO)、todo.txtのタスクを外部ID (external_id) で突き合わせて作成または更新します。CSVの列名は英語・日本語の別名を自動で判定し、map で明示的に対応付けることもできます。日付の書式は列ごとに自動判定します。iCalendarではUID、todo.txtでは id: の値を外部IDとして使います。dry_run=true の場合は保存せずに行ごとの検証結果を返します
+// @Tags transfer
+// @Accept text/csv
+// @Accept text/calendar
+// @Accept text/plain
+// @Produce json
+// @Param format query string false "入力形式 (csv, ics, todotxt)"
+// @Param dry_run query bool false "検証のみ行い保存しない"
+// @Param map query string false "列名の対応 (例: 件名:title,締切:due_date)"
+// @Param file body string true "インポートするファイルの内容"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 200 {object} model.ImportReport
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 422 {object} model.ImportReport "検証エラーのある行があります"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /tasks/import [post]
+func (h *TaskHandler) HandleImportTasks(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	body, err := importBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	var records []model.ImportRecord
+	switch format {
+	case "csv":
+		mapping, err := parseColumnMapping(r.URL.Query().Get("map"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		records, err = csvio.Read(body, csvio.Options{Mapping: mapping})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case "ics":
+		records, err = ical.Read(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case "todotxt":
+		records, err = todotxt.Read(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, fmt.Sprintf("未対応の形式です: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	h.writeImportReport(w, r, records, dryRun)
+}
+
+// writeImportReport インポートを実行して結果を返す。検証エラーがあれば422で結果を返す
+func (h *TaskHandler) writeImportReport(w http.ResponseWriter, r *http.Request, records []model.ImportRecord, dryRun bool) {
+	report, err := h.controller.WithActor(actorFromRequest(r)).ImportTasks(records, dryRun)
+	if err != nil && !errors.Is(err, service.ErrImportInvalid) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// importBody リクエスト本文、またはmultipart/form-dataのfileフィールドを返す
+func importBody(r *http.Request) (io.ReadCloser, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, nil
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("file フィールドがありません: %w", err)
+	}
+	return file, nil
+}
+
+// parseColumnMapping "列名:項目名,..." 形式の列の対応を解析するヘルパー関数
+func parseColumnMapping(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return nil, fmt.Errorf("列の対応の形式が不正です: %q", pair)
+		}
+		mapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return mapping, nil
+}
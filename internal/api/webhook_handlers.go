@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Webhookを登録
+// @Description タスクのイベント（task.created, task.updated, task.completed, task.deleted）を送信するWebhookを登録します。
+// @Description secretを省略した場合は生成し、登録時のレスポンスでのみ返します。
+// @Description 送信時は X-Webhook-Signature ヘッダーに "タイムスタンプ.本文" のHMAC-SHA256署名を付けます
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body webhookRequest true "送信先のURL、秘密鍵、イベントの種類"
+// @Param X-User header string false "操作ユーザー名"
+// @Success 201 {object} model.Webhook
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /webhooks [post]
+func (h *TaskHandler) HandleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var data webhookRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.controller.WithActor(actorFromRequest(r)).CreateWebhook(data.URL, data.Secret, data.Events)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// @Summary Webhookの一覧を取得
+// @Description 登録されているWebhookを登録順に取得します。秘密鍵は含みません
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Webhook
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /webhooks [get]
+func (h *TaskHandler) HandleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.controller.ListWebhooks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hooks)
+}
+
+// @Summary Webhookを削除
+// @Description 指定されたIDのWebhookと送信ログを削除します
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "WebhookID"
+// @Success 204 "No Content"
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "Webhookが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /webhooks/{id} [delete]
+func (h *TaskHandler) HandleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.controller.DeleteWebhook(id); err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Webhookの送信ログを取得
+// @Description 指定されたIDのWebhookの送信ログを新しい順に取得します
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "WebhookID"
+// @Param limit query int false "取得する件数 (デフォルト: 100)"
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 400 {object} string "不正なリクエスト"
+// @Failure 404 {object} string "Webhookが見つかりません"
+// @Failure 500 {object} string "サーバーエラー"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *TaskHandler) HandleListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := h.controller.ListWebhookDeliveries(id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
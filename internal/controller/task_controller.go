@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"io"
 	"time"
 
+	"task-recommender/internal/model"
+	"task-recommender/internal/notify"
 	"task-recommender/internal/service"
+	"task-recommender/internal/webhook"
 )
 
 type TaskController struct {
@@ -14,10 +18,29 @@ func NewTaskController(service *service.TaskService) *TaskController {
 	return &TaskController{service: service}
 }
 
+// WithActor 操作ユーザーを設定したコントローラーを返す
+func (c *TaskController) WithActor(actor string) *TaskController {
+	return &TaskController{service: c.service.WithActor(actor)}
+}
+
+// IfVersion タスクのバージョンがいずれかと一致する場合のみ更新するコントローラーを返す
+func (c *TaskController) IfVersion(versions ...int) *TaskController {
+	return &TaskController{service: c.service.IfVersion(versions...)}
+}
+
+func (c *TaskController) GetTask(id int) (model.Task, error) {
+	return c.service.GetTask(id)
+}
+
 func (c *TaskController) AddTask(title, description string, priority int, dueDate time.Time, estimatedDuration int) (int, error) {
 	return c.service.AddTask(title, description, priority, dueDate, estimatedDuration)
 }
 
+// CreateTask プロジェクトや状況を含めてタスクを作成する
+func (c *TaskController) CreateTask(t model.Task) (int, error) {
+	return c.service.CreateTask(t)
+}
+
 func (c *TaskController) ListTasks() (interface{}, error) {
 	return c.service.ListTasks()
 }
@@ -26,10 +49,26 @@ func (c *TaskController) CompleteTask(id int) error {
 	return c.service.CompleteTask(id)
 }
 
+func (c *TaskController) ReopenTask(id int) error {
+	return c.service.ReopenTask(id)
+}
+
 func (c *TaskController) DeleteTask(id int) error {
 	return c.service.DeleteTask(id)
 }
 
+func (c *TaskController) ListTrash() ([]model.Task, error) {
+	return c.service.ListTrash()
+}
+
+func (c *TaskController) RestoreTask(id int) error {
+	return c.service.RestoreTask(id)
+}
+
+func (c *TaskController) PurgeTrash(before time.Time) (int64, error) {
+	return c.service.PurgeTrash(before)
+}
+
 func (c *TaskController) UpdatePriority(id, priority int) error {
 	return c.service.UpdatePriority(id, priority)
 }
@@ -41,3 +80,177 @@ func (c *TaskController) UpdateDueDate(id int, dueDate time.Time) error {
 func (c *TaskController) UpdateEstimatedDuration(id, duration int) error {
 	return c.service.UpdateEstimatedDuration(id, duration)
 }
+
+func (c *TaskController) SetExternalID(id int, externalID string) error {
+	return c.service.SetExternalID(id, externalID)
+}
+
+func (c *TaskController) StartTimer(id int) (model.TimeEntry, error) {
+	return c.service.StartTimer(id)
+}
+
+func (c *TaskController) StopTimer(id int) (model.TimeEntry, error) {
+	return c.service.StopTimer(id)
+}
+
+func (c *TaskController) ListTimeEntries(id int) ([]model.TimeEntry, error) {
+	return c.service.ListTimeEntries(id)
+}
+
+func (c *TaskController) TransitionStatus(id int, status model.Status) error {
+	return c.service.TransitionStatus(id, status)
+}
+
+func (c *TaskController) ListStatusTransitions(id int) ([]model.StatusTransition, error) {
+	return c.service.ListStatusTransitions(id)
+}
+
+func (c *TaskController) ListHistory(id int) ([]model.HistoryEntry, error) {
+	return c.service.ListHistory(id)
+}
+
+// ListHistoryForTasks 複数のタスクの変更履歴をまとめて取得する
+func (c *TaskController) ListHistoryForTasks(ids []int) ([]model.HistoryEntry, error) {
+	return c.service.ListHistoryForTasks(ids)
+}
+
+// GetTasks IDを指定してタスクをまとめて取得する
+func (c *TaskController) GetTasks(ids []int) ([]model.Task, error) {
+	return c.service.GetTasks(ids)
+}
+
+// ListTasksInProjects いずれかのプロジェクトに属するタスクをまとめて取得する
+func (c *TaskController) ListTasksInProjects(projects []string) ([]model.Task, error) {
+	return c.service.ListTasksInProjects(projects)
+}
+
+// ListTasksWithTags いずれかのタグが付いたタスクをまとめて取得する
+func (c *TaskController) ListTasksWithTags(tags []string) ([]model.Task, error) {
+	return c.service.ListTasksWithTags(tags)
+}
+
+// ListProjects タスクのあるプロジェクトを取得する
+func (c *TaskController) ListProjects() ([]model.ProjectSummary, error) {
+	return c.service.ListProjects()
+}
+
+// ListTags タスクに付いているタグを取得する
+func (c *TaskController) ListTags() ([]model.TagSummary, error) {
+	return c.service.ListTags()
+}
+
+func (c *TaskController) ListComments(id int) ([]model.Comment, error) {
+	return c.service.ListComments(id)
+}
+
+func (c *TaskController) AddComment(id int, body string) (model.Comment, error) {
+	return c.service.AddComment(id, body)
+}
+
+func (c *TaskController) UpdateComment(id, commentID int, body string) (model.Comment, error) {
+	return c.service.UpdateComment(id, commentID, body)
+}
+
+func (c *TaskController) DeleteComment(id, commentID int) error {
+	return c.service.DeleteComment(id, commentID)
+}
+
+func (c *TaskController) AddAttachment(id int, filename string, r io.Reader) (model.Attachment, error) {
+	return c.service.AddAttachment(id, filename, r)
+}
+
+func (c *TaskController) ListAttachments(id int) ([]model.Attachment, error) {
+	return c.service.ListAttachments(id)
+}
+
+func (c *TaskController) OpenAttachment(id, attachmentID int) (model.Attachment, io.ReadCloser, error) {
+	return c.service.OpenAttachment(id, attachmentID)
+}
+
+func (c *TaskController) DeleteAttachment(id, attachmentID int) error {
+	return c.service.DeleteAttachment(id, attachmentID)
+}
+
+func (c *TaskController) ListChecklist(id int) ([]model.ChecklistItem, error) {
+	return c.service.ListChecklist(id)
+}
+
+func (c *TaskController) AddChecklistItem(id int, title string) (model.ChecklistItem, error) {
+	return c.service.AddChecklistItem(id, title)
+}
+
+func (c *TaskController) ToggleChecklistItem(id, itemID int) (model.ChecklistItem, error) {
+	return c.service.ToggleChecklistItem(id, itemID)
+}
+
+func (c *TaskController) DeleteChecklistItem(id, itemID int) error {
+	return c.service.DeleteChecklistItem(id, itemID)
+}
+
+func (c *TaskController) ReorderChecklist(id int, itemIDs []int) error {
+	return c.service.ReorderChecklist(id, itemIDs)
+}
+
+func (c *TaskController) RecommendTasks(limit int) ([]model.Recommendation, error) {
+	return c.service.RecommendTasks(limit)
+}
+
+func (c *TaskController) PlanWorkBlocks(from time.Time, days int) ([]model.WorkBlock, error) {
+	return c.service.PlanWorkBlocks(from, days)
+}
+
+func (c *TaskController) ExecuteBulk(ops []model.BulkOperation, mode model.BulkMode) (model.BulkReport, error) {
+	return c.service.ExecuteBulk(ops, mode)
+}
+
+func (c *TaskController) ImportTasks(records []model.ImportRecord, dryRun bool) (model.ImportReport, error) {
+	return c.service.ImportTasks(records, dryRun)
+}
+
+func (c *TaskController) Backup(w io.Writer, withBlobs bool) (model.BackupSummary, error) {
+	return c.service.Backup(w, withBlobs)
+}
+
+func (c *TaskController) Restore(r io.Reader, force bool) (model.BackupSummary, error) {
+	return c.service.Restore(r, force)
+}
+
+func (c *TaskController) CreateWebhook(url, secret string, events []model.EventType) (model.Webhook, error) {
+	return c.service.CreateWebhook(url, secret, events)
+}
+
+func (c *TaskController) ListWebhooks() ([]model.Webhook, error) {
+	return c.service.ListWebhooks()
+}
+
+func (c *TaskController) DeleteWebhook(id int) error {
+	return c.service.DeleteWebhook(id)
+}
+
+func (c *TaskController) ListWebhookDeliveries(webhookID, limit int) ([]model.WebhookDelivery, error) {
+	return c.service.ListWebhookDeliveries(webhookID, limit)
+}
+
+func (c *TaskController) DeliverWebhooks(sender webhook.Sender, limit int) (int, error) {
+	return c.service.DeliverWebhooks(sender, limit)
+}
+
+func (c *TaskController) ListEvents(afterID int64, limit int) ([]model.TaskEvent, error) {
+	return c.service.ListEvents(afterID, limit)
+}
+
+func (c *TaskController) LatestEventID() (int64, error) {
+	return c.service.LatestEventID()
+}
+
+func (c *TaskController) SendReminders(notifiers []notify.Notifier, leads []time.Duration, now time.Time) (int, error) {
+	return c.service.SendReminders(notifiers, leads, now)
+}
+
+func (c *TaskController) BuildDigest(user string, day time.Time) (model.Digest, error) {
+	return c.service.BuildDigest(user, day)
+}
+
+func (c *TaskController) SendDigests(mailer notify.Mailer, recipients []model.DigestRecipient, day time.Time) (int, error) {
+	return c.service.SendDigests(mailer, recipients, day)
+}
@@ -0,0 +1,359 @@
+// Package csvio タスクをCSV形式で読み書きする
+package csvio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"task-recommender/internal/model"
+)
+
+// Columns 書き出すCSVの列。読み込み時はtracked_duration以降の集計値を無視する
+//
+// contexts は空白区切りで書き出す。読み込み時はカンマ区切りも受け付ける
+//
+// 外部IDのない行は id でタスクと突き合わせるため、書き出したファイルを編集して取り込み直すと元のタスクを更新する
+var Columns = []string{
+	"id", "external_id", "title", "description", "status", "done", "priority",
+	"due_date", "estimated_duration", "project", "contexts", "created_at", "completed_at",
+	"tracked_duration", "checklist_done", "checklist_total",
+}
+
+// headerAliases 読み込み時に列名として受け付ける別名。比較は normalizeHeader の後に行う
+var headerAliases = map[string]string{
+	"id": "id", "タスクid": "id",
+	"externalid": "external_id", "外部id": "external_id", "key": "external_id",
+	"title": "title", "name": "title", "task": "title", "タイトル": "title", "タスク": "title", "件名": "title",
+	"description": "description", "notes": "description", "note": "description", "説明": "description", "メモ": "description",
+	"status": "status", "state": "status", "状態": "status",
+	"done": "done", "completed": "done", "完了": "done",
+	"priority": "priority", "優先度": "priority",
+	"duedate": "due_date", "due": "due_date", "deadline": "due_date", "期限": "due_date", "期限日": "due_date",
+	"estimatedduration": "estimated_duration", "estimate": "estimated_duration", "duration": "estimated_duration",
+	"見積時間": "estimated_duration", "見積時間分": "estimated_duration", "見積所要時間": "estimated_duration",
+	"project": "project", "プロジェクト": "project",
+	"contexts": "contexts", "context": "contexts", "コンテキスト": "contexts",
+	"createdat": "created_at", "created": "created_at", "作成日": "created_at", "作成日時": "created_at",
+	"completedat": "completed_at", "完了日": "completed_at", "完了日時": "completed_at",
+}
+
+// dateLayouts 日付列の書式の候補。列ごとに最も多くの値を解析できた書式を採用する
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/1/2",
+	"2006.1.2",
+	"2006年1月2日",
+	"01/02/2006",
+	"02.01.2006",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// Options 読み込み時の設定
+type Options struct {
+	// Mapping CSVの列名から項目名（Columnsのいずれか）への対応。別名の自動判定より優先する
+	Mapping map[string]string
+}
+
+// Write タスクをCSV形式で書き出す
+func Write(w io.Writer, tasks []model.Task) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		record := []string{
+			strconv.Itoa(t.ID),
+			t.ExternalID,
+			t.Title,
+			t.Description,
+			string(t.Status),
+			strconv.FormatBool(t.Done),
+			strconv.Itoa(t.Priority),
+			formatTime(t.DueDate),
+			strconv.Itoa(t.EstimatedDuration),
+			t.Project,
+			strings.Join(t.Contexts, " "),
+			formatTime(t.CreatedAt),
+			formatTime(t.CompletedAt),
+			strconv.Itoa(t.TrackedDuration),
+			strconv.Itoa(t.Checklist.Done),
+			strconv.Itoa(t.Checklist.Total),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Read CSVを読み込んでインポートする行の一覧を返す
+//
+// 値を解析できなかった項目は行ごとのErrorsに記録し、ファイル全体としてはエラーにしない
+func Read(r io.Reader, opts Options) ([]model.ImportRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSVが空です")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := mapHeader(header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, record)
+	}
+
+	layouts := map[string]string{}
+	for i, field := range fields {
+		if field == "due_date" || field == "created_at" || field == "completed_at" {
+			layouts[field] = detectDateLayout(rows, i)
+		}
+	}
+
+	records := make([]model.ImportRecord, 0, len(rows))
+	for n, row := range rows {
+		records = append(records, parseRow(n+2, row, fields, layouts))
+	}
+	return records, nil
+}
+
+// mapHeader ヘッダーの各列を項目名に対応付ける。対応しない列は空文字列になる
+func mapHeader(header []string, mapping map[string]string) ([]string, error) {
+	explicit := map[string]string{}
+	for from, to := range mapping {
+		if !isColumn(to) {
+			return nil, fmt.Errorf("不明な項目名です: %s", to)
+		}
+		explicit[normalizeHeader(from)] = to
+	}
+
+	fields := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, h := range header {
+		key := normalizeHeader(h)
+		field, ok := explicit[key]
+		if !ok {
+			field = headerAliases[key]
+		}
+		if field == "" {
+			continue
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("列 %q が重複しています (%s)", h, field)
+		}
+		seen[field] = true
+		fields[i] = field
+	}
+
+	if !seen["title"] {
+		return nil, errors.New("タイトルの列が見つかりません。列名の対応を指定してください")
+	}
+	return fields, nil
+}
+
+func isColumn(name string) bool {
+	for _, c := range Columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHeader 列名を比較用に正規化する (BOM・空白・記号の除去と小文字化)
+func normalizeHeader(h string) string {
+	h = strings.TrimPrefix(h, "\ufeff")
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '(', ')', '（', '）', '　':
+			return -1
+		}
+		return r
+	}, h)
+}
+
+// detectDateLayout 列の値を最も多く解析できる日付の書式を返す。解析できない値は行ごとのエラーになる
+func detectDateLayout(rows [][]string, col int) string {
+	best, bestCount := dateLayouts[0], 0
+	for _, layout := range dateLayouts {
+		count := 0
+		for _, row := range rows {
+			if col >= len(row) || strings.TrimSpace(row[col]) == "" {
+				continue
+			}
+			if _, err := time.ParseInLocation(layout, strings.TrimSpace(row[col]), time.Local); err == nil {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = layout, count
+		}
+	}
+	return best
+}
+
+// parseRow 1行を解析する。列のある項目は空欄でもFieldsに含め、更新時に値を消す。
+// 優先度は空にできないため、空欄の場合はFieldsに含めず既存の値を残す（作成時は1になる）
+func parseRow(line int, row []string, fields []string, layouts map[string]string) model.ImportRecord {
+	rec := model.ImportRecord{Line: line, Task: model.Task{Priority: 1}, Fields: []string{}}
+	t := &rec.Task
+	addErr := func(format string, args ...interface{}) {
+		rec.Errors = append(rec.Errors, fmt.Sprintf(format, args...))
+	}
+
+	for i, field := range fields {
+		if field == "" {
+			continue
+		}
+		v := ""
+		if i < len(row) {
+			v = strings.TrimSpace(row[i])
+		}
+		if field != "priority" || v != "" {
+			rec.Fields = append(rec.Fields, field)
+		}
+		if v == "" {
+			continue
+		}
+
+		switch field {
+		case "id":
+			id, err := strconv.Atoi(v)
+			if err != nil || id <= 0 {
+				addErr("IDを解析できません: %s", v)
+			}
+			t.ID = id
+		case "external_id":
+			t.ExternalID = v
+		case "title":
+			t.Title = v
+		case "description":
+			t.Description = v
+		case "status":
+			status, ok := parseStatus(v)
+			if !ok {
+				addErr("不正な状態です: %s", v)
+			}
+			t.Status = status
+		case "done":
+			done, ok := parseBool(v)
+			if !ok {
+				addErr("完了状態を解析できません: %s", v)
+			}
+			if done && t.Status == "" {
+				t.Status = model.StatusDone
+			}
+		case "priority":
+			p, ok := parsePriority(v)
+			if !ok {
+				addErr("優先度を解析できません: %s", v)
+			}
+			t.Priority = p
+		case "estimated_duration":
+			d, err := strconv.Atoi(v)
+			if err != nil {
+				addErr("見積所要時間を解析できません: %s", v)
+			}
+			t.EstimatedDuration = d
+		case "project":
+			t.Project = v
+		case "contexts":
+			t.Contexts = strings.FieldsFunc(v, func(r rune) bool {
+				return r == ',' || r == '、' || unicode.IsSpace(r)
+			})
+		case "due_date", "created_at", "completed_at":
+			tm, err := time.ParseInLocation(layouts[field], v, time.Local)
+			if err != nil {
+				addErr("日付を解析できません: %s", v)
+				continue
+			}
+			switch field {
+			case "due_date":
+				t.DueDate = tm
+			case "created_at":
+				t.CreatedAt = tm
+			case "completed_at":
+				t.CompletedAt = tm
+			}
+		}
+	}
+	return rec
+}
+
+func parseStatus(v string) (model.Status, bool) {
+	switch strings.ToLower(v) {
+	case "todo", "未着手", "未完了", "open":
+		return model.StatusTodo, true
+	case "in_progress", "in progress", "doing", "進行中":
+		return model.StatusInProgress, true
+	case "blocked", "waiting", "保留":
+		return model.StatusBlocked, true
+	case "done", "完了", "closed":
+		return model.StatusDone, true
+	case "cancelled", "canceled", "中止":
+		return model.StatusCancelled, true
+	}
+	return "", false
+}
+
+func parseBool(v string) (bool, bool) {
+	switch strings.ToLower(v) {
+	case "true", "yes", "y", "1", "x", "完了", "済":
+		return true, true
+	case "false", "no", "n", "0", "未完了":
+		return false, true
+	}
+	return false, false
+}
+
+func parsePriority(v string) (int, bool) {
+	switch strings.ToLower(v) {
+	case "低", "low":
+		return 1, true
+	case "中", "medium", "mid":
+		return 2, true
+	case "高", "high":
+		return 3, true
+	}
+	p, err := strconv.Atoi(v)
+	return p, err == nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
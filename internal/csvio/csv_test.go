@@ -0,0 +1,81 @@
+package csvio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+func TestWriteReadKeepsTaskID(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	tasks := []model.Task{
+		{ID: 7, Title: "外部IDなし", Priority: 2, DueDate: due, Status: model.StatusTodo, Project: "home", Contexts: []string{"phone", "外出"}},
+		{ID: 8, ExternalID: "sheet-row-12", Title: "外部IDあり", Priority: 3, Status: model.StatusDone, Done: true},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Read(&buf, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(tasks) {
+		t.Fatalf("len(records) = %d, want %d", len(records), len(tasks))
+	}
+	for i, rec := range records {
+		if len(rec.Errors) > 0 {
+			t.Errorf("%d行目: %v", rec.Line, rec.Errors)
+		}
+		want := tasks[i]
+		got := rec.Task
+		if got.ID != want.ID || got.ExternalID != want.ExternalID || got.Title != want.Title ||
+			got.Priority != want.Priority || got.Status != want.Status || !got.DueDate.Equal(want.DueDate) ||
+			got.Project != want.Project || strings.Join(got.Contexts, " ") != strings.Join(want.Contexts, " ") {
+			t.Errorf("records[%d].Task = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestReadInvalidID(t *testing.T) {
+	records, err := Read(strings.NewReader("id,title\nabc,牛乳を買う\n"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || len(records[0].Errors) != 1 {
+		t.Fatalf("records = %+v, want one row with one error", records)
+	}
+}
+
+func TestReadFields(t *testing.T) {
+	records, err := Read(strings.NewReader("title,説明,priority\n空欄あり,,\n優先度あり,,高\n"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"title,description", "title,description,priority"}
+	for i, rec := range records {
+		if got := strings.Join(rec.Fields, ","); got != want[i] {
+			t.Errorf("%d行目: Fields = %s, want %s", rec.Line, got, want[i])
+		}
+		if rec.Has("due_date") {
+			t.Errorf("%d行目: 列のない due_date が含まれています", rec.Line)
+		}
+	}
+	if records[0].Task.Priority != 1 {
+		t.Errorf("優先度の既定値 = %d, want 1", records[0].Task.Priority)
+	}
+}
+
+func TestReadContexts(t *testing.T) {
+	records, err := Read(strings.NewReader("title,contexts\nメール,\"phone, office、外出\"\n"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(records[0].Task.Contexts, "|"); got != "phone|office|外出" {
+		t.Errorf("Contexts = %s", got)
+	}
+}
@@ -0,0 +1,59 @@
+// Package digest 毎朝のまとめをテキストとHTMLのテンプレートから作成する
+package digest
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var funcs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02")
+	},
+	"datetime": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04")
+	},
+	"weekday": func(t time.Time) string {
+		return [...]string{"日", "月", "火", "水", "木", "金", "土"}[t.Weekday()]
+	},
+	"priority": model.PriorityLabel,
+	"join":     strings.Join,
+	"inc":      func(i int) int { return i + 1 },
+}
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.New("digest.txt.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/digest.txt.tmpl"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/digest.html.tmpl"))
+)
+
+// Subject まとめのメールの件名
+func Subject(d model.Digest) string {
+	return "今日のタスク (" + d.Date.Format("2006-01-02") + ")"
+}
+
+// Render まとめをテキストとHTMLで作成する
+func Render(d model.Digest) (text, html string, err error) {
+	var tb, hb bytes.Buffer
+	if err := textTemplate.Execute(&tb, d); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplate.Execute(&hb, d); err != nil {
+		return "", "", err
+	}
+	return tb.String(), hb.String(), nil
+}
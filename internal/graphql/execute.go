@@ -0,0 +1,726 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Request GraphQLのリクエスト
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response GraphQLの応答。リクエスト自体に誤りがある場合は Data を含まない
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error 応答に含めるエラー。フィールドのエラーの場合は Path にフィールドの位置を含める
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// ErrSubscriptionRequired subscription の操作を Execute で実行しようとした
+var ErrSubscriptionRequired = errors.New("subscription は購読できる接続で実行してください")
+
+// OperationKind リクエストで実行する操作の種類 (query, mutation, subscription)。判断できない場合は空を返す
+func OperationKind(req Request) string {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return ""
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return ""
+	}
+	return op.kind
+}
+
+// Execute query または mutation を実行する
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	e, err := s.prepare(req)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if e.op.kind == "subscription" {
+		return errorResponse(ErrSubscriptionRequired)
+	}
+	return e.execute(ctx, nil, nil)
+}
+
+// Subscribe 操作を実行し、応答を送るチャネルを返す
+//
+// subscription の場合はイベントごとに応答を送り、イベントが終わるかcontextが終了したらチャネルを閉じる。
+// query と mutation の場合は応答を1つ送って閉じる
+func (s *Schema) Subscribe(ctx context.Context, req Request) <-chan *Response {
+	ch := make(chan *Response, 1)
+	e, err := s.prepare(req)
+	if err != nil {
+		ch <- errorResponse(err)
+		close(ch)
+		return ch
+	}
+	if e.op.kind != "subscription" {
+		ch <- e.execute(ctx, nil, nil)
+		close(ch)
+		return ch
+	}
+
+	groups := e.collect(e.root, [][]selection{e.op.selectionSet})
+	if len(groups) != 1 {
+		ch <- errorResponse(errors.New("subscription ではルートのフィールドを1つだけ指定してください"))
+		close(ch)
+		return ch
+	}
+	f := groups[0].fields[0]
+	def := e.root.Fields[f.name]
+	if def == nil || def.Subscribe == nil {
+		ch <- errorResponse(fmt.Errorf("フィールド %q は購読できません", f.name))
+		close(ch)
+		return ch
+	}
+	args, err := e.coerceArgs(def, f)
+	if err == nil {
+		var events <-chan interface{}
+		events, err = def.Subscribe(ResolveParams{Context: ctx, Args: args})
+		if err == nil {
+			go func() {
+				defer close(ch)
+				for event := range events {
+					select {
+					case ch <- e.execute(ctx, groups, event):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return ch
+		}
+	}
+	ch <- &Response{Errors: []*Error{{Message: err.Error(), Path: []interface{}{groups[0].key}}}}
+	close(ch)
+	return ch
+}
+
+func errorResponse(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
+
+// executor 1つの操作の実行状態
+type executor struct {
+	schema *Schema
+	doc    *document
+	op     *operation
+	root   *Object
+	vars   map[string]interface{}
+
+	ctx    context.Context
+	errors []*Error
+}
+
+// prepare クエリを解析して検証し、変数を準備する
+func (s *Schema) prepare(req Request) (*executor, error) {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &executor{schema: s, doc: doc, op: op, vars: map[string]interface{}{}}
+	switch op.kind {
+	case "query":
+		if s.Query != nil {
+			e.root = s.queryRoot()
+		}
+	case "mutation":
+		e.root = s.Mutation
+	case "subscription":
+		e.root = s.Subscription
+	}
+	if e.root == nil {
+		return nil, fmt.Errorf("%s には対応していません", op.kind)
+	}
+
+	defined := map[string]bool{}
+	for _, v := range op.variables {
+		defined[v.name] = true
+		val, ok := req.Variables[v.name]
+		if !ok && v.hasDefault {
+			val, ok = literal(v.defaultValue, nil), true
+		}
+		if v.nonNull && val == nil {
+			return nil, fmt.Errorf("変数 $%s を指定してください", v.name)
+		}
+		if ok {
+			e.vars[v.name] = val
+		}
+	}
+	if err := e.validate(e.root, op.selectionSet, defined, map[string]bool{}, 1); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) != 1 {
+			return nil, errors.New("操作が複数あるため operationName を指定してください")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("操作 %q がありません", name)
+}
+
+// validate 選択したフィールドと引数、フラグメントがスキーマに合っているか検証する
+//
+// depth は set のフィールドの深さ。フラグメントを展開した後の深さが MaxDepth を超える場合もエラーにする
+func (e *executor) validate(typ *Object, set []selection, defined, visiting map[string]bool, depth int) error {
+	if depth > MaxDepth {
+		return fmt.Errorf("クエリが深すぎます (上限 %d)", MaxDepth)
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *field:
+			if sel.name == "__typename" {
+				if sel.selectionSet != nil {
+					return errors.New("__typename は選択できません")
+				}
+				continue
+			}
+			def := typ.Fields[sel.name]
+			if def == nil {
+				return fmt.Errorf("フィールド %q は %s にありません", sel.name, typ.Name)
+			}
+			given := map[string]bool{}
+			for _, a := range sel.arguments {
+				if def.Args[a.name] == nil {
+					return fmt.Errorf("引数 %q は %s.%s にありません", a.name, typ.Name, sel.name)
+				}
+				if err := checkVariables(a.value, defined); err != nil {
+					return err
+				}
+				given[a.name] = true
+			}
+			for name, arg := range def.Args {
+				if _, ok := arg.Type.(*NonNull); ok && arg.DefaultValue == nil && !given[name] {
+					return fmt.Errorf("%s.%s の引数 %q を指定してください", typ.Name, sel.name, name)
+				}
+			}
+			obj, isObject := namedType(def.Type).(*Object)
+			switch {
+			case isObject && sel.selectionSet == nil:
+				return fmt.Errorf("%s.%s はフィールドを選択してください", typ.Name, sel.name)
+			case !isObject && sel.selectionSet != nil:
+				return fmt.Errorf("%s.%s はフィールドを選択できません", typ.Name, sel.name)
+			case isObject:
+				if err := e.validate(obj, sel.selectionSet, defined, visiting, depth+1); err != nil {
+					return err
+				}
+			}
+		case *fragmentSpread:
+			f := e.doc.fragments[sel.name]
+			if f == nil {
+				return fmt.Errorf("フラグメント %q がありません", sel.name)
+			}
+			if f.typeCondition != typ.Name {
+				return fmt.Errorf("フラグメント %q は %s に使えません", sel.name, typ.Name)
+			}
+			if visiting[sel.name] {
+				return fmt.Errorf("フラグメント %q が循環しています", sel.name)
+			}
+			visiting[sel.name] = true
+			err := e.validate(typ, f.selectionSet, defined, visiting, depth)
+			delete(visiting, sel.name)
+			if err != nil {
+				return err
+			}
+		case *inlineFragment:
+			if sel.typeCondition != "" && sel.typeCondition != typ.Name {
+				return fmt.Errorf("インラインフラグメントの型 %s は %s に使えません", sel.typeCondition, typ.Name)
+			}
+			if err := e.validate(typ, sel.selectionSet, defined, visiting, depth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkVariables(v value, defined map[string]bool) error {
+	switch v := v.(type) {
+	case variable:
+		if !defined[string(v)] {
+			return fmt.Errorf("変数 $%s が定義されていません", v)
+		}
+	case []value:
+		for _, item := range v {
+			if err := checkVariables(item, defined); err != nil {
+				return err
+			}
+		}
+	case map[string]value:
+		for _, item := range v {
+			if err := checkVariables(item, defined); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// namedType リストと非nullを取り除いた型
+func namedType(t Type) Type {
+	for {
+		switch w := t.(type) {
+		case *List:
+			t = w.OfType
+		case *NonNull:
+			t = w.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// fieldGroup 同じ結果のキーにまとめたフィールド
+type fieldGroup struct {
+	key    string
+	fields []*field
+}
+
+// collect 選択をフラグメントとディレクティブを展開して結果のキーごとにまとめる
+func (e *executor) collect(typ *Object, sets [][]selection) []*fieldGroup {
+	var groups []*fieldGroup
+	index := map[string]*fieldGroup{}
+	var walk func(set []selection, visited map[string]bool)
+	walk = func(set []selection, visited map[string]bool) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *field:
+				if !e.included(sel.directives) {
+					continue
+				}
+				key := sel.responseKey()
+				if g := index[key]; g != nil {
+					g.fields = append(g.fields, sel)
+					continue
+				}
+				g := &fieldGroup{key: key, fields: []*field{sel}}
+				index[key] = g
+				groups = append(groups, g)
+			case *fragmentSpread:
+				if !e.included(sel.directives) || visited[sel.name] {
+					continue
+				}
+				visited[sel.name] = true
+				walk(e.doc.fragments[sel.name].selectionSet, visited)
+			case *inlineFragment:
+				if e.included(sel.directives) {
+					walk(sel.selectionSet, visited)
+				}
+			}
+		}
+	}
+	visited := map[string]bool{}
+	for _, set := range sets {
+		walk(set, visited)
+	}
+	return groups
+}
+
+// included @skip と @include の指定に従って選択に含めるかどうか
+func (e *executor) included(dirs []*directive) bool {
+	for _, d := range dirs {
+		for _, a := range d.arguments {
+			if a.name != "if" {
+				continue
+			}
+			cond, _ := literal(a.value, e.vars).(bool)
+			if d.name == "skip" && cond || d.name == "include" && !cond {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// literal 値の変数を置き換え、Goの値に変換する
+func literal(v value, vars map[string]interface{}) interface{} {
+	switch v := v.(type) {
+	case variable:
+		return vars[string(v)]
+	case []value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = literal(item, vars)
+		}
+		return list
+	case map[string]value:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			obj[k] = literal(item, vars)
+		}
+		return obj
+	default:
+		return v
+	}
+}
+
+// coerceArgs フィールドの引数を引数の型に合わせて変換する
+func (e *executor) coerceArgs(def *Field, f *field) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	for name, arg := range def.Args {
+		var v interface{}
+		given := false
+		for _, a := range f.arguments {
+			if a.name != name {
+				continue
+			}
+			if vr, ok := a.value.(variable); ok {
+				v, given = e.vars[string(vr)]
+			} else {
+				v, given = literal(a.value, e.vars), true
+			}
+		}
+		if !given {
+			if arg.DefaultValue != nil {
+				args[name] = arg.DefaultValue
+			} else if _, ok := arg.Type.(*NonNull); ok {
+				return nil, fmt.Errorf("引数 %q を指定してください", name)
+			}
+			continue
+		}
+		coerced, err := coerce(arg.Type, v)
+		if err != nil {
+			return nil, fmt.Errorf("引数 %q: %w", name, err)
+		}
+		args[name] = coerced
+	}
+	return args, nil
+}
+
+func coerce(t Type, v interface{}) (interface{}, error) {
+	if nn, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, errors.New("null は指定できません")
+		}
+		return coerce(nn.OfType, v)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			c, err := coerce(t.OfType, item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = c
+		}
+		return list, nil
+	case *Scalar:
+		return t.Coerce(v)
+	default:
+		return nil, fmt.Errorf("%s は引数に使えません", t)
+	}
+}
+
+// slot 値を書き込む場所。非nullの場所に null を書き込むと、親の値を null にする
+type slot struct {
+	set     func(v interface{})
+	nonNull bool
+	parent  *slot
+	nulled  bool
+}
+
+// null 値を null にし、必要であれば親に伝える
+func (s *slot) null() {
+	for ; s != nil; s = s.parent {
+		s.nulled = true
+		s.set(nil)
+		if !s.nonNull {
+			return
+		}
+	}
+}
+
+// dead 自身か親が null になっているかどうか
+func (s *slot) dead() bool {
+	for ; s != nil; s = s.parent {
+		if s.nulled {
+			return true
+		}
+	}
+	return false
+}
+
+// job フィールドを解決するオブジェクト
+type job struct {
+	typ    *Object
+	source interface{}
+	groups []*fieldGroup
+	out    *orderedMap
+	slot   *slot
+	path   []interface{}
+}
+
+// resolved 解決したフィールドの値
+type resolved struct {
+	job   *job
+	group *fieldGroup
+	def   *Field
+	value interface{}
+	err   error
+}
+
+// execute 操作を実行する。groupsを指定した場合はそのフィールドだけを実行する（subscription のイベント）
+//
+// 実行中のcontextとエラーは実行ごとに持つため、subscription ではイベントごとに複製して実行する
+func (e *executor) execute(ctx context.Context, groups []*fieldGroup, source interface{}) *Response {
+	e = &executor{schema: e.schema, doc: e.doc, op: e.op, root: e.root, vars: e.vars}
+
+	data := newOrderedMap()
+	var result interface{} = data
+	root := &slot{set: func(v interface{}) {
+		if v == nil {
+			result = json.RawMessage("null")
+		}
+	}}
+
+	if groups == nil {
+		groups = e.collect(e.root, [][]selection{e.op.selectionSet})
+	}
+	if e.op.kind == "mutation" {
+		// mutation はフィールドごとに、入れ子のフィールドまで解決してから次を実行する。
+		// 前のフィールドで読み込んだ値が後のフィールドの変更を隠さないよう、フィールドごとに Prepare を呼ぶ
+		for _, g := range groups {
+			e.ctx = e.schema.prepareContext(ctx)
+			e.run([]*job{{typ: e.root, source: source, groups: []*fieldGroup{g}, out: data, slot: root}})
+		}
+	} else {
+		e.ctx = e.schema.prepareContext(ctx)
+		e.run([]*job{{typ: e.root, source: source, groups: groups, out: data, slot: root}})
+	}
+	return &Response{Data: result, Errors: e.errors}
+}
+
+func (s *Schema) prepareContext(ctx context.Context) context.Context {
+	if s.Prepare != nil {
+		return s.Prepare(ctx)
+	}
+	return ctx
+}
+
+// run 深さごとにフィールドを解決する
+//
+// 同じ深さのすべてのフィールドのリゾルバーを呼んでから Thunk を評価するため、
+// Loader は同じ深さで要求されたキーをまとめて読み込める
+func (e *executor) run(jobs []*job) {
+	for len(jobs) > 0 {
+		var fields []*resolved
+		for _, j := range jobs {
+			if j.slot.dead() {
+				continue
+			}
+			for _, g := range j.groups {
+				f := g.fields[0]
+				if f.name == "__typename" {
+					j.out.set(g.key, j.typ.Name)
+					continue
+				}
+				j.out.set(g.key, nil)
+				r := &resolved{job: j, group: g, def: j.typ.Fields[f.name]}
+				args, err := e.coerceArgs(r.def, f)
+				if err != nil {
+					r.err = err
+				} else {
+					r.value, r.err = e.resolve(r.def, j.source, args, f.name)
+				}
+				fields = append(fields, r)
+			}
+		}
+
+		for _, r := range fields {
+			for r.err == nil {
+				thunk, ok := r.value.(Thunk)
+				if !ok {
+					break
+				}
+				r.value, r.err = e.call(func() (interface{}, error) { return thunk() })
+			}
+		}
+
+		var next []*job
+		for _, r := range fields {
+			out, key := r.job.out, r.group.key
+			s := &slot{set: func(v interface{}) { out.set(key, v) }, nonNull: isNonNull(r.def.Type), parent: r.job.slot}
+			path := appendPath(r.job.path, key)
+			if r.err != nil {
+				e.fail(r.err, path, s)
+				continue
+			}
+			next = e.complete(r.def.Type, r.group, r.value, s, path, next)
+		}
+		jobs = next
+	}
+}
+
+func (e *executor) resolve(def *Field, source interface{}, args map[string]interface{}, name string) (interface{}, error) {
+	if def.Resolve == nil {
+		if def.Subscribe != nil {
+			return source, nil
+		}
+		return defaultResolve(source, name)
+	}
+	return e.call(func() (interface{}, error) {
+		return def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+	})
+}
+
+// call リゾルバーのpanicをエラーに変換する
+func (e *executor) call(fn func() (interface{}, error)) (v interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, fmt.Errorf("内部エラー: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// complete 解決した値を型に合わせて書き込む。オブジェクトは次の深さで解決する job として返す
+func (e *executor) complete(t Type, g *fieldGroup, v interface{}, s *slot, path []interface{}, next []*job) []*job {
+	if nn, ok := t.(*NonNull); ok {
+		if isNull(v) {
+			e.fail(errors.New("null にできないフィールドが null です"), path, s)
+			return next
+		}
+		t = nn.OfType
+	}
+	if isNull(v) {
+		s.set(nil)
+		return next
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		out, err := t.Serialize(v)
+		if err != nil {
+			e.fail(err, path, s)
+			return next
+		}
+		s.set(out)
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fail(fmt.Errorf("リストではありません: %T", v), path, s)
+			return next
+		}
+		items := make([]interface{}, rv.Len())
+		s.set(items)
+		for i := range items {
+			i := i
+			item := &slot{set: func(v interface{}) { items[i] = v }, nonNull: isNonNull(t.OfType), parent: s}
+			next = e.complete(t.OfType, g, rv.Index(i).Interface(), item, appendPath(path, i), next)
+		}
+	case *Object:
+		out := newOrderedMap()
+		s.set(out)
+		sets := make([][]selection, len(g.fields))
+		for i, f := range g.fields {
+			sets[i] = f.selectionSet
+		}
+		next = append(next, &job{typ: t, source: v, groups: e.collect(t, sets), out: out, slot: s, path: path})
+	}
+	return next
+}
+
+func (e *executor) fail(err error, path []interface{}, s *slot) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+	s.null()
+}
+
+func isNonNull(t Type) bool {
+	_, ok := t.(*NonNull)
+	return ok
+}
+
+// isNull nil か nil のポインターかどうか。nil のスライスは空のリストとして扱う
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	p := make([]interface{}, len(path)+1)
+	copy(p, path)
+	p[len(path)] = elem
+	return p
+}
+
+// orderedMap 選択した順にキーを出力するオブジェクト
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: map[string]interface{}{}}
+}
+
+func (m *orderedMap) set(key string, v interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,439 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testProject struct {
+	ID   int
+	Name string
+}
+
+type testTask struct {
+	ID        int
+	Title     string
+	ProjectID int
+}
+
+type loaderKey struct{}
+
+// testSchema タスクとプロジェクトのスキーマ。batches にプロジェクトの読み込みごとのキーを記録する
+type testSchema struct {
+	*Schema
+	batches [][]int
+}
+
+func newTestSchema() *testSchema {
+	ts := &testSchema{}
+	tasks := []*testTask{
+		{ID: 1, Title: "牛乳を買う", ProjectID: 10},
+		{ID: 2, Title: "レポートを書く", ProjectID: 20},
+		{ID: 3, Title: "パンを買う", ProjectID: 10},
+		{ID: 4, Title: "プロジェクトなし"},
+	}
+	projects := map[int]*testProject{10: {ID: 10, Name: "買い物"}, 20: {ID: 20, Name: "仕事"}}
+
+	projectType := &Object{Name: "Project", Fields: Fields{
+		"id":   {Type: NonNullOf(ID)},
+		"name": {Type: NonNullOf(String)},
+	}}
+	taskType := &Object{Name: "Task"}
+	taskType.Fields = Fields{
+		"id":    {Type: NonNullOf(ID)},
+		"title": {Type: NonNullOf(String)},
+		"project": {
+			Type: projectType,
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				loader := p.Context.Value(loaderKey{}).(*Loader[int, *testProject])
+				return loader.Load(p.Source.(*testTask).ProjectID), nil
+			},
+		},
+		// self 深さの検証に使う、自身を返すフィールド
+		"self": {Type: taskType, Resolve: func(p ResolveParams) (interface{}, error) { return p.Source, nil }},
+		"broken": {Type: NonNullOf(String), Resolve: func(p ResolveParams) (interface{}, error) {
+			return nil, errors.New("壊れています")
+		}},
+	}
+
+	ts.Schema = &Schema{
+		Query: &Object{Name: "Query", Fields: Fields{
+			"tasks": {Type: NonNullOf(ListOf(NonNullOf(taskType))), Resolve: func(p ResolveParams) (interface{}, error) {
+				return tasks, nil
+			}},
+			"task": {
+				Type: taskType,
+				Args: Args{"id": {Type: NonNullOf(ID)}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					for _, t := range tasks {
+						if fmt.Sprint(t.ID) == p.Args["id"] {
+							return t, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			"search": {
+				Type: ListOf(taskType),
+				Args: Args{"limit": {Type: Int, DefaultValue: 2}, "words": {Type: ListOf(String)}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					var found []*testTask
+					for _, t := range tasks {
+						for _, w := range p.Args["words"].([]interface{}) {
+							if strings.Contains(t.Title, w.(string)) && len(found) < p.Args["limit"].(int) {
+								found = append(found, t)
+							}
+						}
+					}
+					return found, nil
+				},
+			},
+		}},
+		Mutation: &Object{Name: "Mutation", Fields: Fields{
+			"rename": {
+				Type: taskType,
+				Args: Args{"id": {Type: NonNullOf(ID)}, "title": {Type: NonNullOf(String)}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					for _, t := range tasks {
+						if fmt.Sprint(t.ID) == p.Args["id"] {
+							t.Title = p.Args["title"].(string)
+							return t, nil
+						}
+					}
+					return nil, errors.New("タスクがありません")
+				},
+			},
+			"renameProject": {
+				Type: projectType,
+				Args: Args{"id": {Type: NonNullOf(ID)}, "name": {Type: NonNullOf(String)}},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					for _, pr := range projects {
+						if fmt.Sprint(pr.ID) == p.Args["id"] {
+							pr.Name = p.Args["name"].(string)
+							return pr, nil
+						}
+					}
+					return nil, errors.New("プロジェクトがありません")
+				},
+			},
+		}},
+		Subscription: &Object{Name: "Subscription", Fields: Fields{
+			"taskAdded": {
+				Type: NonNullOf(taskType),
+				Args: Args{"count": {Type: NonNullOf(Int)}},
+				Subscribe: func(p ResolveParams) (<-chan interface{}, error) {
+					count := p.Args["count"].(int)
+					if count < 0 {
+						return nil, errors.New("count は0以上で指定してください")
+					}
+					ch := make(chan interface{})
+					go func() {
+						defer close(ch)
+						for i := 0; i < count; i++ {
+							select {
+							case ch <- tasks[i%len(tasks)]:
+							case <-p.Context.Done():
+								return
+							}
+						}
+						<-p.Context.Done()
+					}()
+					return ch, nil
+				},
+			},
+		}},
+		Prepare: func(ctx context.Context) context.Context {
+			loader := NewLoader(func(keys []int) (map[int]*testProject, error) {
+				ts.batches = append(ts.batches, keys)
+				found := map[int]*testProject{}
+				for _, k := range keys {
+					if p, ok := projects[k]; ok {
+						found[k] = p
+					}
+				}
+				return found, nil
+			})
+			return context.WithValue(ctx, loaderKey{}, loader)
+		},
+	}
+	return ts
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{
+			"aliases and typename",
+			Request{Query: `{ first: task(id: 1) { __typename id title } second: task(id: "2") { title } }`},
+			`{"data":{"first":{"__typename":"Task","id":"1","title":"牛乳を買う"},"second":{"title":"レポートを書く"}}}`,
+		},
+		{
+			"variables and defaults",
+			Request{
+				Query:     `query Find($words: [String], $limit: Int = 1) { search(words: $words, limit: $limit) { id } }`,
+				Variables: map[string]interface{}{"words": []interface{}{"買う"}},
+			},
+			`{"data":{"search":[{"id":"1"}]}}`,
+		},
+		{
+			"argument default and single value as list",
+			Request{Query: `{ search(words: "買う") { id } }`},
+			`{"data":{"search":[{"id":"1"},{"id":"3"}]}}`,
+		},
+		{
+			"fragments and directives",
+			Request{
+				Query: `query ($full: Boolean!) { task(id: 1) { ...F ... @include(if: $full) { project { name } } } }
+                    fragment F on Task { id title @skip(if: true) }`,
+				Variables: map[string]interface{}{"full": true},
+			},
+			`{"data":{"task":{"id":"1","project":{"name":"買い物"}}}}`,
+		},
+		{
+			"missing value is null",
+			Request{Query: `{ task(id: 99) { id } }`},
+			`{"data":{"task":null}}`,
+		},
+		{
+			"non-null error propagates to nullable parent",
+			Request{Query: `{ task(id: 1) { id broken } }`},
+			`{"data":{"task":null},"errors":[{"message":"壊れています","path":["task","broken"]}]}`,
+		},
+		{
+			"operation name selects operation",
+			Request{Query: `query A { task(id: 1) { id } } query B { task(id: 2) { id } }`, OperationName: "B"},
+			`{"data":{"task":{"id":"2"}}}`,
+		},
+		{
+			"mutation",
+			Request{Query: `mutation { a: rename(id: 4, title: "x") { title } b: rename(id: 4, title: "y") { title } }`},
+			`{"data":{"a":{"title":"x"},"b":{"title":"y"}}}`,
+		},
+		{
+			"subscription requires Subscribe",
+			Request{Query: `subscription { taskAdded(count: 1) { id } }`},
+			`{"errors":[{"message":"` + ErrSubscriptionRequired.Error() + `"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toJSON(t, newTestSchema().Execute(context.Background(), tt.req))
+			if got != tt.want {
+				t.Errorf("Execute() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     Request
+		message string
+	}{
+		{"syntax error", Request{Query: `{ tasks { id }`}, "Syntax Error: 予期しない終端です (1:15)"},
+		{"unknown field", Request{Query: `{ tasks { id name } }`}, `フィールド "name" は Task にありません`},
+		{"unknown argument", Request{Query: `{ task(id: 1, title: "x") { id } }`}, `引数 "title" は Query.task にありません`},
+		{"missing required argument", Request{Query: `{ task { id } }`}, `Query.task の引数 "id" を指定してください`},
+		{"object without selection", Request{Query: `{ tasks }`}, "Query.tasks はフィールドを選択してください"},
+		{"scalar with selection", Request{Query: `{ tasks { id { x } } }`}, "Task.id はフィールドを選択できません"},
+		{"typename with selection", Request{Query: `{ __typename { x } }`}, "__typename は選択できません"},
+		{"undefined variable", Request{Query: `{ task(id: $id) { id } }`}, "変数 $id が定義されていません"},
+		{"missing non-null variable", Request{Query: `query ($id: ID!) { task(id: $id) { id } }`}, "変数 $id を指定してください"},
+		{"unknown fragment", Request{Query: `{ tasks { ...F } }`}, `フラグメント "F" がありません`},
+		{"fragment on wrong type", Request{Query: `{ tasks { ...F } } fragment F on Project { id }`}, `フラグメント "F" は Task に使えません`},
+		{"fragment cycle", Request{Query: `{ tasks { ...A } } fragment A on Task { ...B } fragment B on Task { ...A }`}, `フラグメント "A" が循環しています`},
+		{"inline fragment on wrong type", Request{Query: `{ tasks { ... on Project { id } } }`}, "インラインフラグメントの型 Project は Task に使えません"},
+		{"ambiguous operation", Request{Query: `query A { tasks { id } } query B { tasks { id } }`}, "操作が複数あるため operationName を指定してください"},
+		{"unknown operation", Request{Query: `query A { tasks { id } }`, OperationName: "B"}, `操作 "B" がありません`},
+		{"unsupported operation", Request{Query: `subscription { x }`}, `フィールド "x" は Subscription にありません`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := newTestSchema().Execute(context.Background(), tt.req)
+			if resp.Data != nil || len(resp.Errors) != 1 || resp.Errors[0].Message != tt.message {
+				t.Errorf("Execute() = %s, want error %q", toJSON(t, resp), tt.message)
+			}
+		})
+	}
+}
+
+func TestExecuteArgumentErrors(t *testing.T) {
+	resp := newTestSchema().Execute(context.Background(), Request{
+		Query:     `query ($limit: Int) { search(words: ["買う"], limit: $limit) { id } }`,
+		Variables: map[string]interface{}{"limit": 1.5},
+	})
+	want := `{"data":{"search":null},"errors":[{"message":"引数 \"limit\": Int ではありません: 1.5","path":["search"]}]}`
+	if got := toJSON(t, resp); got != want {
+		t.Errorf("Execute() = %s, want %s", got, want)
+	}
+}
+
+func TestExecuteRejectsDeepQueries(t *testing.T) {
+	query := func(depth int) string {
+		// tasks が1段目、self を depth-2 段重ね、最後に id を選ぶ
+		return "{ tasks { " + strings.Repeat("self { ", depth-2) + "id" + strings.Repeat(" }", depth-2) + " } }"
+	}
+	if resp := newTestSchema().Execute(context.Background(), Request{Query: query(MaxDepth)}); len(resp.Errors) != 0 {
+		t.Fatalf("depth %d: %s", MaxDepth, toJSON(t, resp))
+	}
+	resp := newTestSchema().Execute(context.Background(), Request{Query: query(MaxDepth + 1)})
+	if len(resp.Errors) != 1 || resp.Data != nil {
+		t.Fatalf("depth %d: %s, want an error", MaxDepth+1, toJSON(t, resp))
+	}
+
+	// フラグメントで入れ子を分けても、展開した後の深さで制限する
+	var b strings.Builder
+	b.WriteString("{ tasks { ...F0 } }")
+	for i := 0; i < MaxDepth; i++ {
+		fmt.Fprintf(&b, " fragment F%d on Task { self { ...F%d } }", i, i+1)
+	}
+	fmt.Fprintf(&b, " fragment F%d on Task { id }", MaxDepth)
+	resp = newTestSchema().Execute(context.Background(), Request{Query: b.String()})
+	want := fmt.Sprintf("クエリが深すぎます (上限 %d)", MaxDepth)
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != want {
+		t.Errorf("fragments: %s, want %q", toJSON(t, resp), want)
+	}
+}
+
+func TestLoaderBatchesPerDepth(t *testing.T) {
+	ts := newTestSchema()
+	resp := ts.Execute(context.Background(), Request{Query: `{
+        tasks { id project { name } self { project { id } } }
+        task(id: 2) { project { name } }
+    }`})
+	if len(resp.Errors) != 0 {
+		t.Fatal(toJSON(t, resp))
+	}
+	if got := toJSON(t, resp); !strings.Contains(got, `{"id":"4","project":null,"self":{"project":null}}`) {
+		t.Errorf("task without project = %s", got)
+	}
+
+	// 2段目の project はすべて1回で読み込み、3段目の project は読み込み済みの値を使う
+	if len(ts.batches) != 1 {
+		t.Fatalf("batches = %v, want 1 batch", ts.batches)
+	}
+	if got := fmt.Sprint(ts.batches[0]); got != "[10 20 0]" {
+		t.Errorf("batch keys = %s, want each key once", got)
+	}
+
+	// 実行ごとに Loader を作り直すため、次の実行では再び読み込む
+	ts.Execute(context.Background(), Request{Query: `{ task(id: 1) { project { name } } }`})
+	if len(ts.batches) != 2 || fmt.Sprint(ts.batches[1]) != "[10]" {
+		t.Errorf("batches = %v, want a new batch for the next request", ts.batches)
+	}
+}
+
+func TestLoaderResetBetweenMutationFields(t *testing.T) {
+	ts := newTestSchema()
+	resp := ts.Execute(context.Background(), Request{Query: `mutation {
+        before: rename(id: 1, title: "a") { project { name } }
+        renameProject(id: 10, name: "食料品") { id }
+        after: rename(id: 1, title: "b") { project { name } }
+    }`})
+	// 後のフィールドは前のフィールドで読み込んだ値ではなく、変更後の値を返す
+	want := `{"data":{"before":{"project":{"name":"買い物"}},"renameProject":{"id":"10"},"after":{"project":{"name":"食料品"}}}}`
+	if got := toJSON(t, resp); got != want {
+		t.Errorf("Execute() =\n%s\nwant\n%s", got, want)
+	}
+	if len(ts.batches) != 2 {
+		t.Errorf("batches = %v, want one batch per mutation field that loads", ts.batches)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	calls := 0
+	loader := NewLoader(func(keys []string) (map[string]int, error) {
+		calls++
+		return nil, errors.New("読み込めません")
+	})
+	a, b := loader.Load("a"), loader.LoadOr("b")
+	if _, err := a(); err == nil {
+		t.Error("Load: error = nil")
+	}
+	if _, err := b(); err == nil {
+		t.Error("LoadOr: error = nil")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := newTestSchema().Subscribe(ctx, Request{Query: `subscription { taskAdded(count: 3) { id project { name } } }`})
+	want := []string{
+		`{"data":{"taskAdded":{"id":"1","project":{"name":"買い物"}}}}`,
+		`{"data":{"taskAdded":{"id":"2","project":{"name":"仕事"}}}}`,
+		`{"data":{"taskAdded":{"id":"3","project":{"name":"買い物"}}}}`,
+	}
+	for i, w := range want {
+		select {
+		case resp := <-ch:
+			if got := toJSON(t, resp); got != w {
+				t.Errorf("event %d = %s, want %s", i, got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d: timed out", i)
+		}
+	}
+
+	cancel()
+	select {
+	case resp, ok := <-ch:
+		if ok {
+			t.Errorf("unexpected response after cancel: %s", toJSON(t, resp))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel was not closed after cancel")
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"two root fields", `subscription { a: taskAdded(count: 1) { id } b: taskAdded(count: 1) { id } }`,
+			`{"errors":[{"message":"subscription ではルートのフィールドを1つだけ指定してください"}]}`},
+		{"subscribe error", `subscription { taskAdded(count: -1) { id } }`,
+			`{"errors":[{"message":"count は0以上で指定してください","path":["taskAdded"]}]}`},
+		{"query through Subscribe", `{ task(id: 1) { id } }`,
+			`{"data":{"task":{"id":"1"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := newTestSchema().Subscribe(context.Background(), Request{Query: tt.query})
+			resp, ok := <-ch
+			if !ok {
+				t.Fatal("no response")
+			}
+			if got := toJSON(t, resp); got != tt.want {
+				t.Errorf("response = %s, want %s", got, tt.want)
+			}
+			if _, ok := <-ch; ok {
+				t.Error("channel was not closed")
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package graphql
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// イントロスペクションの型。列挙型には対応していないため __TypeKind などは String として返す。
+// 説明と非推奨の情報は持たないため、常に null と false を返す
+var (
+	introSchema    = &Object{Name: "__Schema"}
+	introType      = &Object{Name: "__Type"}
+	introField     = &Object{Name: "__Field"}
+	introInput     = &Object{Name: "__InputValue"}
+	introEnumValue = &Object{Name: "__EnumValue"}
+	introDirective = &Object{Name: "__Directive"}
+)
+
+// fieldInfo __Field の値
+type fieldInfo struct {
+	name  string
+	field *Field
+}
+
+// inputValue __InputValue の値
+type inputValue struct {
+	name string
+	arg  *Arg
+}
+
+// directiveInfo __Directive の値
+type directiveInfo struct {
+	name      string
+	locations []string
+	args      []inputValue
+}
+
+// directives 対応しているディレクティブ
+var directives = []directiveInfo{
+	{"include", []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"}, []inputValue{{"if", &Arg{Type: NonNullOf(Boolean)}}}},
+	{"skip", []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"}, []inputValue{{"if", &Arg{Type: NonNullOf(Boolean)}}}},
+}
+
+func init() {
+	nullable := func(p ResolveParams) (interface{}, error) { return nil, nil }
+	notDeprecated := func(p ResolveParams) (interface{}, error) { return false, nil }
+	includeDeprecated := Args{"includeDeprecated": {Type: Boolean, DefaultValue: false}}
+	typeList := NonNullOf(ListOf(NonNullOf(introType)))
+	inputList := NonNullOf(ListOf(NonNullOf(introInput)))
+
+	introSchema.Fields = Fields{
+		"description": {Type: String, Resolve: nullable},
+		"types": {Type: typeList, Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(*Schema).types(), nil
+		}},
+		"queryType": {Type: NonNullOf(introType), Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(*Schema).Query, nil
+		}},
+		"mutationType": {Type: introType, Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(*Schema).Mutation, nil
+		}},
+		"subscriptionType": {Type: introType, Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(*Schema).Subscription, nil
+		}},
+		"directives": {Type: NonNullOf(ListOf(NonNullOf(introDirective))), Resolve: func(p ResolveParams) (interface{}, error) {
+			return directives, nil
+		}},
+	}
+
+	introType.Fields = Fields{
+		"kind": {Type: NonNullOf(String), Resolve: func(p ResolveParams) (interface{}, error) {
+			switch p.Source.(type) {
+			case *Scalar:
+				return "SCALAR", nil
+			case *Object:
+				return "OBJECT", nil
+			case *List:
+				return "LIST", nil
+			default:
+				return "NON_NULL", nil
+			}
+		}},
+		"name": {Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			switch t := p.Source.(type) {
+			case *Scalar:
+				return t.Name, nil
+			case *Object:
+				return t.Name, nil
+			}
+			return nil, nil
+		}},
+		"description":    {Type: String, Resolve: nullable},
+		"specifiedByURL": {Type: String, Resolve: nullable},
+		"fields": {Type: ListOf(NonNullOf(introField)), Args: includeDeprecated, Resolve: func(p ResolveParams) (interface{}, error) {
+			o, ok := p.Source.(*Object)
+			if !ok {
+				return nil, nil
+			}
+			fields := make([]fieldInfo, 0, len(o.Fields))
+			for name, f := range o.Fields {
+				fields = append(fields, fieldInfo{name, f})
+			}
+			sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+			return fields, nil
+		}},
+		"interfaces": {Type: ListOf(NonNullOf(introType)), Resolve: func(p ResolveParams) (interface{}, error) {
+			if _, ok := p.Source.(*Object); ok {
+				return []Type{}, nil
+			}
+			return nil, nil
+		}},
+		"possibleTypes": {Type: ListOf(NonNullOf(introType)), Resolve: nullable},
+		"enumValues":    {Type: ListOf(NonNullOf(introEnumValue)), Args: includeDeprecated, Resolve: nullable},
+		"inputFields":   {Type: ListOf(NonNullOf(introInput)), Args: includeDeprecated, Resolve: nullable},
+		"ofType": {Type: introType, Resolve: func(p ResolveParams) (interface{}, error) {
+			switch t := p.Source.(type) {
+			case *List:
+				return t.OfType, nil
+			case *NonNull:
+				return t.OfType, nil
+			}
+			return nil, nil
+		}},
+	}
+
+	introField.Fields = Fields{
+		"name":        {Type: NonNullOf(String), Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(fieldInfo).name, nil }},
+		"description": {Type: String, Resolve: nullable},
+		"args": {Type: inputList, Args: includeDeprecated, Resolve: func(p ResolveParams) (interface{}, error) {
+			return sortedArgs(p.Source.(fieldInfo).field.Args), nil
+		}},
+		"type":              {Type: NonNullOf(introType), Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(fieldInfo).field.Type, nil }},
+		"isDeprecated":      {Type: NonNullOf(Boolean), Resolve: notDeprecated},
+		"deprecationReason": {Type: String, Resolve: nullable},
+	}
+
+	introInput.Fields = Fields{
+		"name":        {Type: NonNullOf(String), Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(inputValue).name, nil }},
+		"description": {Type: String, Resolve: nullable},
+		"type":        {Type: NonNullOf(introType), Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(inputValue).arg.Type, nil }},
+		// defaultValue 既定値をGraphQLの値の書式で返す
+		"defaultValue": {Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			v := p.Source.(inputValue).arg.DefaultValue
+			if v == nil {
+				return nil, nil
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		}},
+		"isDeprecated":      {Type: NonNullOf(Boolean), Resolve: notDeprecated},
+		"deprecationReason": {Type: String, Resolve: nullable},
+	}
+
+	introEnumValue.Fields = Fields{
+		"name":              {Type: NonNullOf(String)},
+		"description":       {Type: String, Resolve: nullable},
+		"isDeprecated":      {Type: NonNullOf(Boolean), Resolve: notDeprecated},
+		"deprecationReason": {Type: String, Resolve: nullable},
+	}
+
+	introDirective.Fields = Fields{
+		"name":        {Type: NonNullOf(String), Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(directiveInfo).name, nil }},
+		"description": {Type: String, Resolve: nullable},
+		"locations": {Type: NonNullOf(ListOf(NonNullOf(String))), Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(directiveInfo).locations, nil
+		}},
+		"args": {Type: inputList, Args: includeDeprecated, Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(directiveInfo).args, nil
+		}},
+		"isRepeatable": {Type: NonNullOf(Boolean), Resolve: notDeprecated},
+	}
+}
+
+func sortedArgs(args Args) []inputValue {
+	list := make([]inputValue, 0, len(args))
+	for name, a := range args {
+		list = append(list, inputValue{name, a})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+// queryRoot Query に __schema と __type を加えたルートの型。
+// イントロスペクションのフィールドは __schema の結果には含めない
+func (s *Schema) queryRoot() *Object {
+	fields := make(Fields, len(s.Query.Fields)+2)
+	for name, f := range s.Query.Fields {
+		fields[name] = f
+	}
+	fields["__schema"] = &Field{Type: NonNullOf(introSchema), Resolve: func(p ResolveParams) (interface{}, error) {
+		return s, nil
+	}}
+	fields["__type"] = &Field{
+		Type: introType,
+		Args: Args{"name": {Type: NonNullOf(String)}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			for _, t := range s.types() {
+				if t.String() == p.Args["name"] {
+					return t, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+	return &Object{Name: s.Query.Name, Fields: fields}
+}
+
+// types スキーマから参照しているすべての名前付きの型を名前の順に返す
+func (s *Schema) types() []Type {
+	seen := map[string]bool{}
+	var list []Type
+	var walk func(t Type)
+	walk = func(t Type) {
+		t = namedType(t)
+		if t == nil || seen[t.String()] {
+			return
+		}
+		seen[t.String()] = true
+		list = append(list, t)
+		if o, ok := t.(*Object); ok {
+			for _, f := range o.Fields {
+				walk(f.Type)
+				for _, a := range f.Args {
+					walk(a.Type)
+				}
+			}
+		}
+	}
+	// イントロスペクションで使う String と Boolean も常に含める
+	for _, t := range []Type{s.Query, s.Mutation, s.Subscription, introSchema, String, Boolean} {
+		if o, ok := t.(*Object); ok && o == nil {
+			continue
+		}
+		walk(t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].String() < list[j].String() })
+	return list
+}
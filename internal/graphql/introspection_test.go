@@ -0,0 +1,186 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// introspectionQuery GraphiQL などのツールが送るイントロスペクションのクエリ
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    description
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      isRepeatable
+      locations
+      args(includeDeprecated: true) { ...InputValue }
+    }
+  }
+}
+fragment FullType on __Type {
+  kind
+  name
+  description
+  specifiedByURL
+  fields(includeDeprecated: true) {
+    name
+    description
+    args(includeDeprecated: true) { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields(includeDeprecated: true) { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+  isDeprecated
+  deprecationReason
+}
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}`
+
+func TestIntrospectionQuery(t *testing.T) {
+	resp := newTestSchema().Execute(context.Background(), Request{Query: introspectionQuery})
+	if len(resp.Errors) != 0 {
+		t.Fatal(toJSON(t, resp))
+	}
+
+	type typeRef struct {
+		Kind   string   `json:"kind"`
+		Name   *string  `json:"name"`
+		OfType *typeRef `json:"ofType"`
+	}
+	var got struct {
+		Data struct {
+			Schema struct {
+				QueryType        struct{ Name string } `json:"queryType"`
+				MutationType     struct{ Name string } `json:"mutationType"`
+				SubscriptionType struct{ Name string } `json:"subscriptionType"`
+				Types            []struct {
+					Kind   string `json:"kind"`
+					Name   string `json:"name"`
+					Fields []struct {
+						Name string  `json:"name"`
+						Type typeRef `json:"type"`
+						Args []struct {
+							Name         string  `json:"name"`
+							Type         typeRef `json:"type"`
+							DefaultValue *string `json:"defaultValue"`
+						} `json:"args"`
+					} `json:"fields"`
+				} `json:"types"`
+				Directives []struct {
+					Name string `json:"name"`
+				} `json:"directives"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(toJSON(t, resp)), &got); err != nil {
+		t.Fatal(err)
+	}
+	schema := got.Data.Schema
+	if schema.QueryType.Name != "Query" || schema.MutationType.Name != "Mutation" || schema.SubscriptionType.Name != "Subscription" {
+		t.Errorf("root types = %+v %+v %+v", schema.QueryType, schema.MutationType, schema.SubscriptionType)
+	}
+	if len(schema.Directives) != 2 || schema.Directives[0].Name != "include" || schema.Directives[1].Name != "skip" {
+		t.Errorf("directives = %+v", schema.Directives)
+	}
+
+	var names []string
+	kinds := map[string]string{}
+	for _, typ := range schema.Types {
+		names = append(names, typ.Name)
+		kinds[typ.Name] = typ.Kind
+	}
+	want := []string{"Boolean", "ID", "Int", "Mutation", "Project", "Query", "String", "Subscription", "Task",
+		"__Directive", "__EnumValue", "__Field", "__InputValue", "__Schema", "__Type"}
+	if toJSON(t, names) != toJSON(t, want) {
+		t.Errorf("types = %v, want %v", names, want)
+	}
+	if kinds["Task"] != "OBJECT" || kinds["ID"] != "SCALAR" {
+		t.Errorf("kinds = %v", kinds)
+	}
+
+	// Query のフィールドにはイントロスペクションのフィールドを含めず、名前の順に並べる
+	for _, typ := range schema.Types {
+		if typ.Name != "Query" {
+			continue
+		}
+		var fields []string
+		for _, f := range typ.Fields {
+			fields = append(fields, f.Name)
+		}
+		if toJSON(t, fields) != `["search","task","tasks"]` {
+			t.Errorf("Query fields = %v", fields)
+		}
+		search := typ.Fields[0]
+		if len(search.Args) != 2 || search.Args[0].Name != "limit" || search.Args[0].DefaultValue == nil || *search.Args[0].DefaultValue != "2" {
+			t.Errorf("search args = %s", toJSON(t, search.Args))
+		}
+		// [Task!]! は NON_NULL, LIST, NON_NULL, OBJECT の順に入れ子にする
+		tasks := typ.Fields[2].Type
+		if got := toJSON(t, tasks); got != `{"kind":"NON_NULL","name":null,"ofType":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null,"ofType":{"kind":"OBJECT","name":"Task","ofType":null}}}}` {
+			t.Errorf("tasks type = %s", got)
+		}
+	}
+}
+
+func TestIntrospectionType(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{
+			"object",
+			Request{Query: `{ __type(name: "Project") { kind name fields { name type { kind ofType { name } } } interfaces { name } ofType { name } } }`},
+			`{"data":{"__type":{"kind":"OBJECT","name":"Project","fields":[{"name":"id","type":{"kind":"NON_NULL","ofType":{"name":"ID"}}},{"name":"name","type":{"kind":"NON_NULL","ofType":{"name":"String"}}}],"interfaces":[],"ofType":null}}}`,
+		},
+		{
+			"scalar",
+			Request{Query: `{ __type(name: "Int") { kind name fields { name } } }`},
+			`{"data":{"__type":{"kind":"SCALAR","name":"Int","fields":null}}}`,
+		},
+		{
+			"unknown type",
+			Request{Query: `query ($name: String!) { __type(name: $name) { name } }`, Variables: map[string]interface{}{"name": "Tag"}},
+			`{"data":{"__type":null}}`,
+		},
+		{
+			"typename of introspection types",
+			Request{Query: `{ __schema { __typename queryType { __typename } } }`},
+			`{"data":{"__schema":{"__typename":"__Schema","queryType":{"__typename":"__Type"}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toJSON(t, newTestSchema().Execute(context.Background(), tt.req))
+			if got != tt.want {
+				t.Errorf("Execute() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	// イントロスペクションのフィールドは query のルートだけで使える
+	resp := newTestSchema().Execute(context.Background(), Request{Query: `mutation { __schema { types { name } } }`})
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != `フィールド "__schema" は Mutation にありません` {
+		t.Errorf("mutation: %s", toJSON(t, resp))
+	}
+}
@@ -0,0 +1,76 @@
+package graphql
+
+// BatchFunc キーをまとめて読み込む。見つからないキーは結果に含めない
+type BatchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// Loader リゾルバーが要求したキーをためておき、最初に値が必要になった時点でまとめて読み込む
+//
+// 読み込んだ値は実行が終わるまで保持する。実行は1つのゴルーチンで行うため排他制御はしない。
+// 実行ごと（mutation ではルートのフィールドごと）に Schema.Prepare で作り直す
+type Loader[K comparable, V any] struct {
+	batch   BatchFunc[K, V]
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+// NewLoader Loader を作成する
+func NewLoader[K comparable, V any](batch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch:  batch,
+		queued: map[K]bool{},
+		values: map[K]V{},
+		errs:   map[K]error{},
+	}
+}
+
+// Load キーを読み込み待ちに加え、値を返す Thunk を返す。見つからない場合は nil を返す
+func (l *Loader[K, V]) Load(key K) Thunk {
+	l.enqueue(key)
+	return func() (interface{}, error) {
+		v, ok, err := l.get(key)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// LoadOr キーを読み込み待ちに加え、値を返す Thunk を返す。見つからない場合はゼロ値を返す
+func (l *Loader[K, V]) LoadOr(key K) Thunk {
+	l.enqueue(key)
+	return func() (interface{}, error) {
+		v, _, err := l.get(key)
+		return v, err
+	}
+}
+
+func (l *Loader[K, V]) enqueue(key K) {
+	if l.queued[key] {
+		return
+	}
+	l.queued[key] = true
+	l.pending = append(l.pending, key)
+}
+
+func (l *Loader[K, V]) get(key K) (V, bool, error) {
+	if len(l.pending) > 0 {
+		keys := l.pending
+		l.pending = nil
+		values, err := l.batch(keys)
+		for _, k := range keys {
+			if err != nil {
+				l.errs[k] = err
+			} else if v, ok := values[k]; ok {
+				l.values[k] = v
+			}
+		}
+	}
+	if err := l.errs[key]; err != nil {
+		var zero V
+		return zero, false, err
+	}
+	v, ok := l.values[key]
+	return v, ok, nil
+}
@@ -0,0 +1,562 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document 解析したクエリ文書
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation query, mutation, subscription のいずれかの操作
+type operation struct {
+	kind         string
+	name         string
+	variables    []*variableDefinition
+	selectionSet []selection
+}
+
+type variableDefinition struct {
+	name         string
+	nonNull      bool
+	defaultValue value
+	hasDefault   bool
+}
+
+// selection *field, *fragmentSpread, *inlineFragment のいずれか
+type selection interface{}
+
+type field struct {
+	alias        string
+	name         string
+	arguments    []*argument
+	directives   []*directive
+	selectionSet []selection
+}
+
+// responseKey 結果のキー。別名がある場合は別名
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type argument struct {
+	name  string
+	value value
+}
+
+type directive struct {
+	name      string
+	arguments []*argument
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+}
+
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selectionSet  []selection
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	selectionSet  []selection
+}
+
+// value 引数などの値。リテラルは int, float64, string, bool, nil, []value, map[string]value で表し、変数は variable で表す
+type value interface{}
+
+type variable string
+
+// SyntaxError クエリ文書の構文の誤り
+type SyntaxError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("Syntax Error: %s (%d:%d)", e.Message, e.Line, e.Column)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// MaxDepth 選択セットと値の入れ子の上限。フラグメントを展開した後のフィールドの深さにも適用する
+const MaxDepth = 15
+
+// parser 再帰下降でクエリ文書を解析する
+type parser struct {
+	src   string
+	pos   int
+	tok   token
+	depth int
+}
+
+// parse クエリ文書を解析する。型定義などの実行できない定義は受け付けない
+func parse(src string) (doc *document, err error) {
+	p := &parser{src: src}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, e
+		}
+	}()
+
+	p.next()
+	doc = &document{fragments: map[string]*fragment{}}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			doc.operations = append(doc.operations, &operation{kind: "query", selectionSet: p.parseSelectionSet()})
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			doc.operations = append(doc.operations, p.parseOperation())
+		case p.peek(tokenName, "fragment"):
+			f := p.parseFragment()
+			if _, ok := doc.fragments[f.name]; ok {
+				p.fail("フラグメント %q が重複しています", f.name)
+			}
+			doc.fragments[f.name] = f
+		default:
+			p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		p.fail("操作がありません")
+	}
+	return doc, nil
+}
+
+func (p *parser) parseOperation() *operation {
+	op := &operation{kind: p.expect(tokenName, "").text}
+	if p.tok.kind == tokenName {
+		op.name = p.expect(tokenName, "").text
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			p.expect(tokenPunct, "$")
+			v := &variableDefinition{name: p.expect(tokenName, "").text}
+			p.expect(tokenPunct, ":")
+			v.nonNull = p.parseType()
+			if p.skip("=") {
+				v.defaultValue, v.hasDefault = p.parseValue(true), true
+			}
+			p.parseDirectives()
+			op.variables = append(op.variables, v)
+		}
+	}
+	p.parseDirectives()
+	op.selectionSet = p.parseSelectionSet()
+	return op
+}
+
+// parseType 変数の型を読み飛ばし、最も外側が非nullかどうかを返す。値の検査は引数の型で行う
+func (p *parser) parseType() bool {
+	if p.skip("[") {
+		p.enter()
+		p.parseType()
+		p.leave()
+		p.expect(tokenPunct, "]")
+	} else {
+		p.expect(tokenName, "")
+	}
+	return p.skip("!")
+}
+
+func (p *parser) parseFragment() *fragment {
+	p.expect(tokenName, "fragment")
+	f := &fragment{name: p.expect(tokenName, "").text}
+	if f.name == "on" {
+		p.fail("フラグメント名に on は使えません")
+	}
+	p.expect(tokenName, "on")
+	f.typeCondition = p.expect(tokenName, "").text
+	p.parseDirectives()
+	f.selectionSet = p.parseSelectionSet()
+	return f
+}
+
+func (p *parser) parseSelectionSet() []selection {
+	p.expect(tokenPunct, "{")
+	p.enter()
+	defer p.leave()
+	var set []selection
+	for !p.skip("}") {
+		set = append(set, p.parseSelection())
+	}
+	if len(set) == 0 {
+		p.fail("選択するフィールドがありません")
+	}
+	return set
+}
+
+func (p *parser) parseSelection() selection {
+	if p.skip("...") {
+		if p.peek(tokenName, "") && !p.peek(tokenName, "on") {
+			return &fragmentSpread{name: p.expect(tokenName, "").text, directives: p.parseDirectives()}
+		}
+		f := &inlineFragment{}
+		if p.peek(tokenName, "on") {
+			p.next()
+			f.typeCondition = p.expect(tokenName, "").text
+		}
+		f.directives = p.parseDirectives()
+		f.selectionSet = p.parseSelectionSet()
+		return f
+	}
+
+	f := &field{name: p.expect(tokenName, "").text}
+	if p.skip(":") {
+		f.alias, f.name = f.name, p.expect(tokenName, "").text
+	}
+	f.arguments = p.parseArguments()
+	f.directives = p.parseDirectives()
+	if p.peek(tokenPunct, "{") {
+		f.selectionSet = p.parseSelectionSet()
+	}
+	return f
+}
+
+func (p *parser) parseArguments() []*argument {
+	var args []*argument
+	if p.skip("(") {
+		for !p.skip(")") {
+			a := &argument{name: p.expect(tokenName, "").text}
+			p.expect(tokenPunct, ":")
+			a.value = p.parseValue(false)
+			args = append(args, a)
+		}
+	}
+	return args
+}
+
+func (p *parser) parseDirectives() []*directive {
+	var dirs []*directive
+	for p.skip("@") {
+		dirs = append(dirs, &directive{name: p.expect(tokenName, "").text, arguments: p.parseArguments()})
+	}
+	return dirs
+}
+
+// parseValue 値を解析する。constの場合は変数を使えない
+func (p *parser) parseValue(constant bool) value {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.text {
+		case "$":
+			if constant {
+				p.fail("既定値に変数は使えません")
+			}
+			p.next()
+			return variable(p.expect(tokenName, "").text)
+		case "[":
+			p.next()
+			p.enter()
+			defer p.leave()
+			list := []value{}
+			for !p.skip("]") {
+				list = append(list, p.parseValue(constant))
+			}
+			return list
+		case "{":
+			p.next()
+			p.enter()
+			defer p.leave()
+			obj := map[string]value{}
+			for !p.skip("}") {
+				name := p.expect(tokenName, "").text
+				p.expect(tokenPunct, ":")
+				obj[name] = p.parseValue(constant)
+			}
+			return obj
+		}
+	case tokenInt:
+		p.next()
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			p.fail("整数が大きすぎます: %s", tok.text)
+		}
+		return n
+	case tokenFloat:
+		p.next()
+		f, _ := strconv.ParseFloat(tok.text, 64)
+		return f
+	case tokenString:
+		p.next()
+		return tok.text
+	case tokenName:
+		p.next()
+		switch tok.text {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		// 列挙値は文字列として扱う
+		return tok.text
+	}
+	p.unexpected()
+	return nil
+}
+
+// enter 入れ子を1段深くする。MaxDepth を超える場合は解析をやめる
+func (p *parser) enter() {
+	p.depth++
+	if p.depth > MaxDepth {
+		p.fail("入れ子が深すぎます (上限 %d)", MaxDepth)
+	}
+}
+
+func (p *parser) leave() { p.depth-- }
+
+func (p *parser) peek(kind tokenKind, text string) bool {
+	return p.tok.kind == kind && (text == "" || p.tok.text == text)
+}
+
+// skip 次の記号がtextの場合は読み進めてtrueを返す
+func (p *parser) skip(text string) bool {
+	if p.peek(tokenPunct, text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, text string) token {
+	if !p.peek(kind, text) {
+		p.unexpected()
+	}
+	tok := p.tok
+	p.next()
+	return tok
+}
+
+func (p *parser) unexpected() {
+	if p.tok.kind == tokenEOF {
+		p.failAt(p.tok.pos, "予期しない終端です")
+	}
+	p.failAt(p.tok.pos, "予期しないトークンです: %s", p.tok.text)
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	p.failAt(p.tok.pos, format, args...)
+}
+
+func (p *parser) failAt(pos int, format string, args ...interface{}) {
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	column := 1 + utf8.RuneCountInString(p.src[strings.LastIndex(p.src[:pos], "\n")+1:pos])
+	panic(&SyntaxError{Message: fmt.Sprintf(format, args...), Line: line, Column: column})
+}
+
+// next 次のトークンを読む。空白、カンマ、コメントは無視する
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+		} else if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		} else if strings.HasPrefix(p.src[p.pos:], "\ufeff") {
+			p.pos += len("\ufeff")
+		} else {
+			break
+		}
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, text: "...", pos: start}
+	case strings.IndexByte("!$()[]{}:=@|&", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, text: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, text: p.src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		p.lexNumber(start)
+	case c == '"':
+		p.lexString(start)
+	default:
+		p.failAt(start, "不正な文字です: %q", c)
+	}
+}
+
+func (p *parser) lexNumber(start int) {
+	kind := tokenInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := func() {
+		n := p.pos
+		for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+			p.pos++
+		}
+		if n == p.pos {
+			p.failAt(p.pos, "数値が不正です")
+		}
+	}
+	digits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		kind = tokenFloat
+		p.pos++
+		digits()
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		kind = tokenFloat
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		digits()
+	}
+	p.tok = token{kind: kind, text: p.src[start:p.pos], pos: start}
+}
+
+func (p *parser) lexString(start int) {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		// \""" は終端ではなく """ を表す
+		end := p.pos + 3
+		for {
+			i := strings.Index(p.src[end:], `"""`)
+			if i < 0 {
+				p.failAt(start, "文字列が閉じていません")
+			}
+			end += i
+			if p.src[end-1] != '\\' {
+				break
+			}
+			end += 3
+		}
+		raw := p.src[p.pos+3 : end]
+		p.pos = end + 3
+		p.tok = token{kind: tokenString, text: blockString(raw), pos: start}
+		return
+	}
+
+	var b strings.Builder
+	p.pos++
+	for {
+		if p.pos >= len(p.src) || p.src[p.pos] == '\n' {
+			p.failAt(start, "文字列が閉じていません")
+		}
+		c := p.src[p.pos]
+		if c == '"' {
+			p.pos++
+			break
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		if p.pos+1 >= len(p.src) {
+			p.failAt(p.pos, "エスケープが不正です")
+		}
+		esc := p.src[p.pos+1]
+		p.pos += 2
+		switch esc {
+		case '"', '\\', '/':
+			b.WriteByte(esc)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if p.pos+4 > len(p.src) {
+				p.failAt(p.pos, "エスケープが不正です")
+			}
+			r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+			if err != nil {
+				p.failAt(p.pos, "エスケープが不正です")
+			}
+			b.WriteRune(rune(r))
+			p.pos += 4
+		default:
+			p.failAt(p.pos-2, "エスケープが不正です")
+		}
+	}
+	p.tok = token{kind: tokenString, text: b.String(), pos: start}
+}
+
+// blockString """ で囲んだ文字列の共通のインデントと前後の空行を取り除く
+func blockString(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, `\"""`, `"""`), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
@@ -0,0 +1,172 @@
+package graphql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+		line    int
+		column  int
+	}{
+		{"empty document", "", "操作がありません", 1, 1},
+		{"only fragment", "fragment F on Task { id }", "操作がありません", 1, 26},
+		{"unclosed selection set", "{ tasks { id }", "予期しない終端です", 1, 15},
+		{"empty selection set", "{ tasks { } }", "選択するフィールドがありません", 1, 13},
+		{"unexpected token", "{ tasks(: 1) { id } }", "予期しないトークンです: :", 1, 9},
+		{"invalid character", "{ tasks ? }", "不正な文字です: '?'", 1, 9},
+		{"unclosed string", "{ task(title: \"abc) { id } }", "文字列が閉じていません", 1, 15},
+		{"bad escape", `{ task(title: "\q") { id } }`, "エスケープが不正です", 1, 16},
+		{"bad number", "{ task(id: 1.) { id } }", "数値が不正です", 1, 14},
+		{"integer overflow", "{ task(id: 99999999999999999999) { id } }", "整数が大きすぎます: 99999999999999999999", 1, 32},
+		{"variable in default", "query ($a: Int = $b) { task(id: $a) { id } }", "既定値に変数は使えません", 1, 18},
+		{"fragment named on", "fragment on on Task { id } { tasks { id } }", "フラグメント名に on は使えません", 1, 13},
+		{"duplicate fragment", "fragment F on Task { id } fragment F on Task { id } { tasks { ...F } }", `フラグメント "F" が重複しています`, 1, 53},
+		{"error on later line", "{\n  tasks {\n    id\n  ]\n}", "予期しないトークンです: ]", 4, 3},
+		{"type definition", "type Task { id: Int }", "予期しないトークンです: type", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.query)
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("parse() error = %v, want SyntaxError", err)
+			}
+			if se.Message != tt.message || se.Line != tt.line || se.Column != tt.column {
+				t.Errorf("parse() error = %q at %d:%d, want %q at %d:%d", se.Message, se.Line, se.Column, tt.message, tt.line, tt.column)
+			}
+		})
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	doc, err := parse(`
+        # コメントとカンマは無視する
+        query Tasks($status: String = "todo", $ids: [ID!]!) @cache {
+            first: task(id: 1) { id, title }
+            tasks(status: $status, ids: $ids, filter: {project: "仕事", tags: ["a", "b"]}) {
+                ...TaskFields
+                ... on Task @include(if: true) { title }
+                ... @skip(if: false) { id }
+            }
+        }
+        fragment TaskFields on Task { id description(format: PLAIN) }
+        mutation { complete(id: -1.5e3) { id } }
+    `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.operations) != 2 || len(doc.fragments) != 1 {
+		t.Fatalf("operations = %d, fragments = %d", len(doc.operations), len(doc.fragments))
+	}
+
+	op := doc.operations[0]
+	if op.kind != "query" || op.name != "Tasks" || len(op.variables) != 2 {
+		t.Fatalf("operation = %+v", op)
+	}
+	if v := op.variables[0]; v.nonNull || !v.hasDefault || v.defaultValue != "todo" {
+		t.Errorf("$status = %+v", v)
+	}
+	if v := op.variables[1]; !v.nonNull || v.hasDefault {
+		t.Errorf("$ids = %+v", v)
+	}
+
+	first := op.selectionSet[0].(*field)
+	if first.alias != "first" || first.name != "task" || first.responseKey() != "first" || first.arguments[0].value != 1 {
+		t.Errorf("first = %+v", first)
+	}
+	tasks := op.selectionSet[1].(*field)
+	if tasks.arguments[0].value != variable("status") {
+		t.Errorf("status argument = %#v", tasks.arguments[0].value)
+	}
+	filter := tasks.arguments[2].value.(map[string]value)
+	if filter["project"] != "仕事" || len(filter["tags"].([]value)) != 2 {
+		t.Errorf("filter = %#v", filter)
+	}
+	if _, ok := tasks.selectionSet[0].(*fragmentSpread); !ok {
+		t.Errorf("selection 0 = %T, want fragment spread", tasks.selectionSet[0])
+	}
+	if f, ok := tasks.selectionSet[1].(*inlineFragment); !ok || f.typeCondition != "Task" || len(f.directives) != 1 {
+		t.Errorf("selection 1 = %+v", tasks.selectionSet[1])
+	}
+	if f, ok := tasks.selectionSet[2].(*inlineFragment); !ok || f.typeCondition != "" {
+		t.Errorf("selection 2 = %+v", tasks.selectionSet[2])
+	}
+
+	if enum := doc.fragments["TaskFields"].selectionSet[1].(*field).arguments[0].value; enum != "PLAIN" {
+		t.Errorf("enum argument = %#v", enum)
+	}
+	if n := doc.operations[1].selectionSet[0].(*field).arguments[0].value; n != -1500.0 {
+		t.Errorf("float argument = %#v", n)
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    string
+	}{
+		{`"plain"`, "plain"},
+		{`"tab\tquote\"slash\/"`, "tab\tquote\"slash/"},
+		{`"あ"`, "あ"},
+		{`"日本語"`, "日本語"},
+		{"\"\"\"\n    block\n      indented\n    \"\"\"", "block\n  indented"},
+		{`"""escaped \""" quote"""`, `escaped """ quote`},
+	}
+	for _, tt := range tests {
+		doc, err := parse("{ task(title: " + tt.literal + ") { id } }")
+		if err != nil {
+			t.Errorf("%s: %v", tt.literal, err)
+			continue
+		}
+		if got := doc.operations[0].selectionSet[0].(*field).arguments[0].value; got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.literal, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsDeepNesting(t *testing.T) {
+	nested := func(open, inner, close string, n int) string {
+		return strings.Repeat(open, n) + inner + strings.Repeat(close, n)
+	}
+	// 引数の値は選択セットの中にあるため、選択セットの分を1段として数える
+	tests := []struct {
+		name  string
+		query func(n int) string
+	}{
+		{"selection sets", func(n int) string {
+			return nested("{ a ", "", "}", n)
+		}},
+		{"list values", func(n int) string {
+			return "{ a(v: " + nested("[", "", "]", n-1) + ") }"
+		}},
+		{"object values", func(n int) string {
+			return "{ a(v: " + nested("{ k: ", "1", " }", n-1) + ") }"
+		}},
+		{"variable types", func(n int) string {
+			return "query ($v: " + nested("[", "Int", "]", n) + ") { a }"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parse(tt.query(MaxDepth)); err != nil {
+				t.Errorf("depth %d: %v", MaxDepth, err)
+			}
+			_, err := parse(tt.query(MaxDepth + 1))
+			var se *SyntaxError
+			if !errors.As(err, &se) || !strings.Contains(se.Message, "入れ子が深すぎます") {
+				t.Errorf("depth %d: error = %v, want nesting error", MaxDepth+1, err)
+			}
+		})
+	}
+
+	// 上限を大きく超える入力でもスタックを使い果たさずにエラーを返す
+	if _, err := parse(strings.Repeat("{ a ", 1_000_000)); err == nil {
+		t.Error("deeply nested query was accepted")
+	}
+}
@@ -0,0 +1,281 @@
+// Package graphql 外部のライブラリに頼らずにGraphQLのクエリを解析し、Goで定義したスキーマに対して実行する
+//
+// 対応している範囲:
+//
+//   - query, mutation, subscription の操作と、変数、別名、フラグメント、@include / @skip
+//   - スカラー (Int, Float, String, Boolean, ID と独自のスカラー)、オブジェクト、リスト、非null
+//
+// インターフェース、ユニオン、列挙型と入力オブジェクトには対応していない。
+// イントロスペクションは __typename と、query の __schema と __type に対応する。
+// 入れ子とフィールドの深さは MaxDepth までに制限する。
+//
+// 実行は深さごとにまとめて行う。同じ深さのフィールドをすべて解決してから Thunk を評価するため、
+// Loader を使うリゾルバーはリストの要素ごとではなく1回の問い合わせでまとめて読み込める
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type スキーマの型。*Scalar, *Object, *List, *NonNull のいずれか
+type Type interface {
+	String() string
+}
+
+// Scalar スカラー型
+type Scalar struct {
+	Name string
+	// Serialize リゾルバーが返した値を結果に出力する値に変換する
+	Serialize func(v interface{}) (interface{}, error)
+	// Coerce 引数の値をリゾルバーに渡す値に変換する
+	Coerce func(v interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object オブジェクト型。フィールドが互いを参照する場合は、型を作ってからFieldsを設定する
+type Object struct {
+	Name   string
+	Fields Fields
+}
+
+func (o *Object) String() string { return o.Name }
+
+// Fields フィールド名からフィールドへの対応
+type Fields map[string]*Field
+
+// List リスト型
+type List struct {
+	OfType Type
+}
+
+func (l *List) String() string { return "[" + l.OfType.String() + "]" }
+
+// NonNull 非null型
+type NonNull struct {
+	OfType Type
+}
+
+func (n *NonNull) String() string { return n.OfType.String() + "!" }
+
+// ListOf リスト型を作成する
+func ListOf(t Type) *List { return &List{OfType: t} }
+
+// NonNullOf 非null型を作成する
+func NonNullOf(t Type) *NonNull { return &NonNull{OfType: t} }
+
+// Field オブジェクトのフィールド
+type Field struct {
+	Type Type
+	Args Args
+	// Resolve フィールドの値を返す。Thunk を返すと同じ深さのフィールドをすべて解決してから評価する。
+	// nilの場合は親の値の同名の構造体フィールド（大文字小文字を区別しない）か、マップの同名のキーを使う
+	Resolve ResolveFunc
+	// Subscribe subscription のフィールドで、イベントを送るチャネルを返す。
+	// チャネルはcontextが終了したら閉じる。イベントごとにResolveのSourceにイベントを渡して実行する
+	Subscribe SubscribeFunc
+}
+
+// Args 引数名から引数への対応
+type Args map[string]*Arg
+
+// Arg フィールドの引数
+type Arg struct {
+	Type         Type
+	DefaultValue interface{}
+}
+
+// ResolveParams リゾルバーに渡す値
+type ResolveParams struct {
+	Context context.Context
+	// Source 親のオブジェクトの値。ルートのフィールドでは nil（subscription ではイベント）
+	Source interface{}
+	// Args 引数の値。指定されず既定値もない引数は含まない
+	Args map[string]interface{}
+}
+
+// ResolveFunc フィールドの値を返す関数
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// SubscribeFunc イベントを送るチャネルを返す関数
+type SubscribeFunc func(p ResolveParams) (<-chan interface{}, error)
+
+// Thunk 後で評価する値。Loader がまとめて読み込むために使う
+type Thunk func() (interface{}, error)
+
+// Schema スキーマ。QueryのほかにMutationとSubscriptionは省略できる
+type Schema struct {
+	Query        *Object
+	Mutation     *Object
+	Subscription *Object
+	// Prepare 実行のたびに（subscription ではイベントごと、mutation ではルートのフィールドごとに）呼び出し、
+	// Loader など実行ごとの値をcontextに設定する
+	Prepare func(ctx context.Context) context.Context
+}
+
+var (
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(v interface{}) (interface{}, error) {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return rv.Int(), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return rv.Uint(), nil
+			}
+			return nil, fmt.Errorf("Int として出力できません: %v", v)
+		},
+		Coerce: func(v interface{}) (interface{}, error) {
+			switch n := v.(type) {
+			case int:
+				return n, nil
+			case float64:
+				// JSONの変数の数値はfloat64になる
+				if n == float64(int(n)) {
+					return int(n), nil
+				}
+			}
+			return nil, fmt.Errorf("Int ではありません: %v", v)
+		},
+	}
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(v interface{}) (interface{}, error) {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.Float32, reflect.Float64:
+				return rv.Float(), nil
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(rv.Int()), nil
+			}
+			return nil, fmt.Errorf("Float として出力できません: %v", v)
+		},
+		Coerce: func(v interface{}) (interface{}, error) {
+			switch n := v.(type) {
+			case int:
+				return float64(n), nil
+			case float64:
+				return n, nil
+			}
+			return nil, fmt.Errorf("Float ではありません: %v", v)
+		},
+	}
+	String = &Scalar{
+		Name: "String",
+		Serialize: func(v interface{}) (interface{}, error) {
+			if s, ok := v.(fmt.Stringer); ok && reflect.TypeOf(v).Kind() != reflect.String {
+				return s.String(), nil
+			}
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+				return rv.String(), nil
+			}
+			return nil, fmt.Errorf("String として出力できません: %v", v)
+		},
+		Coerce: func(v interface{}) (interface{}, error) {
+			if s, ok := v.(string); ok {
+				return s, nil
+			}
+			return nil, fmt.Errorf("String ではありません: %v", v)
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(v interface{}) (interface{}, error) {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Bool {
+				return rv.Bool(), nil
+			}
+			return nil, fmt.Errorf("Boolean として出力できません: %v", v)
+		},
+		Coerce: func(v interface{}) (interface{}, error) {
+			if b, ok := v.(bool); ok {
+				return b, nil
+			}
+			return nil, fmt.Errorf("Boolean ではありません: %v", v)
+		},
+	}
+	// ID 文字列として出力する。引数は文字列と整数のどちらも受け付け、文字列で渡す
+	ID = &Scalar{
+		Name: "ID",
+		Serialize: func(v interface{}) (interface{}, error) {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.String:
+				return rv.String(), nil
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return strconv.FormatInt(rv.Int(), 10), nil
+			}
+			return nil, fmt.Errorf("ID として出力できません: %v", v)
+		},
+		Coerce: func(v interface{}) (interface{}, error) {
+			switch id := v.(type) {
+			case string:
+				return id, nil
+			case int:
+				return strconv.Itoa(id), nil
+			case float64:
+				if id == float64(int(id)) {
+					return strconv.Itoa(int(id)), nil
+				}
+			}
+			return nil, fmt.Errorf("ID ではありません: %v", v)
+		},
+	}
+	// DateTime RFC 3339 形式の日時。ゼロ値は null として出力する
+	DateTime = &Scalar{
+		Name: "DateTime",
+		Serialize: func(v interface{}) (interface{}, error) {
+			t, ok := v.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("DateTime として出力できません: %v", v)
+			}
+			if t.IsZero() {
+				return nil, nil
+			}
+			return t.Format(time.RFC3339), nil
+		},
+		Coerce: func(v interface{}) (interface{}, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("DateTime ではありません: %v", v)
+			}
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("DateTime ではありません: %v", v)
+			}
+			return t, nil
+		},
+	}
+)
+
+// defaultResolve 親の値から同名の構造体フィールドかマップのキーの値を取り出す
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		f := rv.FieldByNameFunc(func(s string) bool { return strings.EqualFold(s, name) })
+		if f.IsValid() {
+			return f.Interface(), nil
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+			if !v.IsValid() {
+				return nil, nil
+			}
+			return v.Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("%s を取り出せません", name)
+}
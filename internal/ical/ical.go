@@ -0,0 +1,234 @@
+// Package ical タスクをiCalendar (RFC 5545) 形式で読み書きする
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"task-recommender/internal/model"
+)
+
+// ProdID カレンダーの作成元を表す識別子
+const ProdID = "-//task-recommender//Task Recommender//JA"
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// Calendar 書き出すカレンダーの内容
+type Calendar struct {
+	Name   string
+	Tasks  []model.Task
+	Blocks []model.WorkBlock
+}
+
+// Write カレンダーをiCalendar形式で書き出す
+func Write(w io.Writer, cal Calendar, now time.Time) error {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	stamp := now.UTC().Format(dateTimeLayout)
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", ProdID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		cw.line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+
+	for _, t := range cal.Tasks {
+		cw.line("BEGIN", "VTODO")
+		cw.line("UID", TaskUID(t))
+		cw.line("DTSTAMP", stamp)
+		if !t.CreatedAt.IsZero() {
+			cw.line("CREATED", t.CreatedAt.UTC().Format(dateTimeLayout))
+		}
+		cw.line("SUMMARY", escapeText(t.Title))
+		if t.Description != "" {
+			cw.line("DESCRIPTION", escapeText(t.Description))
+		}
+		if !t.DueDate.IsZero() {
+			if isDate(t.DueDate) {
+				cw.line("DUE;VALUE=DATE", t.DueDate.Format(dateLayout))
+			} else {
+				cw.line("DUE", t.DueDate.UTC().Format(dateTimeLayout))
+			}
+		}
+		cw.line("PRIORITY", strconv.Itoa(toICalPriority(t.Priority)))
+		cw.line("STATUS", toICalStatus(t.Status))
+		if t.Status == model.StatusDone && !t.CompletedAt.IsZero() {
+			cw.line("COMPLETED", t.CompletedAt.UTC().Format(dateTimeLayout))
+		}
+		if t.Checklist.Total > 0 {
+			cw.line("PERCENT-COMPLETE", strconv.Itoa(t.Checklist.Done*100/t.Checklist.Total))
+		}
+		if t.EstimatedDuration > 0 {
+			cw.line("X-ESTIMATED-DURATION", fmt.Sprintf("PT%dM", t.EstimatedDuration))
+		}
+		cw.line("END", "VTODO")
+	}
+
+	for _, b := range cal.Blocks {
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", fmt.Sprintf("plan-%d-%s@task-recommender", b.TaskID, b.Start.UTC().Format(dateTimeLayout)))
+		cw.line("DTSTAMP", stamp)
+		cw.line("DTSTART", b.Start.UTC().Format(dateTimeLayout))
+		cw.line("DTEND", b.End.UTC().Format(dateTimeLayout))
+		cw.line("SUMMARY", escapeText("作業: "+b.Title))
+		cw.line("RELATED-TO", fmt.Sprintf("task-%d@task-recommender", b.TaskID))
+		cw.line("TRANSP", "OPAQUE")
+		cw.line("END", "VEVENT")
+	}
+
+	cw.line("END", "VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// taskUIDPattern 外部IDのないタスクに付けるUID。取り込み時はタスクのIDに戻す
+var taskUIDPattern = regexp.MustCompile(`^task-([1-9][0-9]*)@task-recommender$`)
+
+// TaskUID タスクのUID。外部IDがあればそれを使い、取り込んだ予定と同じUIDを保つ
+func TaskUID(t model.Task) string {
+	if t.ExternalID != "" {
+		return escapeText(t.ExternalID)
+	}
+	return fmt.Sprintf("task-%d@task-recommender", t.ID)
+}
+
+// taskIDFromUID TaskUID が外部IDのないタスクに付けたUIDであれば、タスクのIDを返す
+func taskIDFromUID(uid string) (int, bool) {
+	m := taskUIDPattern.FindStringSubmatch(uid)
+	if m == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// contentWriter 75オクテットで折り返してCRLFで行を書き出す
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	s := name + ":" + value
+	limit := 75
+	for len(s) > limit {
+		// マルチバイト文字の途中で折り返さない
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		// 2行目以降は先頭の空白を含めて75オクテットに収める
+		limit = 74
+		if _, cw.err = cw.w.WriteString(s[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		s = s[cut:]
+	}
+	_, cw.err = cw.w.WriteString(s + "\r\n")
+}
+
+// escapeText TEXT型の値をエスケープする
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// unescapeText TEXT型の値のエスケープを戻す
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// isDate 時刻を持たない日付かどうか（ローカル時刻またはUTCで0時ちょうど）
+func isDate(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h == 0 && m == 0 && s == 0 && t.Nanosecond() == 0
+}
+
+// toICalPriority 優先度 (1=低, 2=中, 3=高) をiCalendarの優先度 (1=最高〜9=最低) に変換する
+func toICalPriority(p int) int {
+	switch {
+	case p >= 3:
+		return 1
+	case p == 2:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// fromICalPriority iCalendarの優先度を変換する。0（未定義）は低とする
+func fromICalPriority(p int) int {
+	switch {
+	case p >= 1 && p <= 4:
+		return 3
+	case p == 5:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func toICalStatus(s model.Status) string {
+	switch s {
+	case model.StatusInProgress:
+		return "IN-PROCESS"
+	case model.StatusDone:
+		return "COMPLETED"
+	case model.StatusCancelled:
+		return "CANCELLED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+func fromICalStatus(s string) (model.Status, bool) {
+	switch strings.ToUpper(s) {
+	case "NEEDS-ACTION":
+		return model.StatusTodo, true
+	case "IN-PROCESS":
+		return model.StatusInProgress, true
+	case "COMPLETED":
+		return model.StatusDone, true
+	case "CANCELLED":
+		return model.StatusCancelled, true
+	}
+	return "", false
+}
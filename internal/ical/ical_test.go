@@ -0,0 +1,61 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+func TestWriteReadRoundTripUID(t *testing.T) {
+	tasks := []model.Task{
+		{ID: 12, Title: "外部IDなし", Priority: 2},
+		{ID: 13, ExternalID: "abc@calendar.example.com", Title: "外部IDあり", Priority: 3},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, Calendar{Tasks: tasks}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "UID:task-12@task-recommender\r\n") {
+		t.Fatalf("UID of task 12 not written:\n%s", buf.String())
+	}
+
+	records, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+	if got := records[0].Task; got.ID != 12 || got.ExternalID != "" {
+		t.Errorf("records[0]: ID=%d ExternalID=%q, want ID=12 without external ID", got.ID, got.ExternalID)
+	}
+	if got := records[1].Task; got.ID != 0 || got.ExternalID != "abc@calendar.example.com" {
+		t.Errorf("records[1]: ID=%d ExternalID=%q, want external ID only", got.ID, got.ExternalID)
+	}
+}
+
+func TestTaskIDFromUID(t *testing.T) {
+	tests := []struct {
+		uid    string
+		wantID int
+		wantOK bool
+	}{
+		{"task-1@task-recommender", 1, true},
+		{"task-12345@task-recommender", 12345, true},
+		{"task-0@task-recommender", 0, false},
+		{"task-01@task-recommender", 0, false},
+		{"task-1@example.com", 0, false},
+		{"xtask-1@task-recommender", 0, false},
+		{"task-99999999999999999999@task-recommender", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		id, ok := taskIDFromUID(tt.uid)
+		if id != tt.wantID || ok != tt.wantOK {
+			t.Errorf("taskIDFromUID(%q) = %d, %v, want %d, %v", tt.uid, id, ok, tt.wantID, tt.wantOK)
+		}
+	}
+}
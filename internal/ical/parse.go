@@ -0,0 +1,229 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// importFields VTODOで表せる項目。プロジェクトとコンテキストは取り込み先の値を残す
+var importFields = []string{"title", "description", "priority", "due_date", "estimated_duration"}
+
+// property コンテンツ行を名前・パラメーター・値に分けたもの
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Read iCalendarのVTODOをインポートする行の一覧として読み込む。UIDは外部IDになる
+//
+// 配信したカレンダーを取り込み直せるよう、TaskUID が外部IDのないタスクに付けたUIDはタスクのIDとして読み込む。
+//
+// 値を解析できなかった項目はVTODOごとのErrorsに記録する。Lineは BEGIN:VTODO の行番号
+func Read(r io.Reader) ([]model.ImportRecord, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []model.ImportRecord
+	var current *model.ImportRecord
+	depth := 0
+	for _, l := range lines {
+		p, err := parseLine(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("%d行目: %w", l.number, err)
+		}
+
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VTODO"):
+			current = &model.ImportRecord{Line: l.number, Task: model.Task{Priority: 1}, Fields: importFields}
+			depth = 0
+			continue
+		case current == nil:
+			continue
+		case p.name == "BEGIN":
+			// VALARMなどの入れ子のコンポーネントは読み飛ばす
+			depth++
+			continue
+		case p.name == "END" && depth > 0:
+			depth--
+			continue
+		case p.name == "END" && strings.EqualFold(p.value, "VTODO"):
+			records = append(records, *current)
+			current = nil
+			continue
+		case depth > 0:
+			continue
+		}
+
+		applyProperty(current, p)
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%d行目: VTODOが閉じられていません", current.Line)
+	}
+	return records, nil
+}
+
+func applyProperty(rec *model.ImportRecord, p property) {
+	t := &rec.Task
+	addErr := func(format string, args ...interface{}) {
+		rec.Errors = append(rec.Errors, fmt.Sprintf(format, args...))
+	}
+
+	switch p.name {
+	case "UID":
+		uid := unescapeText(p.value)
+		if id, ok := taskIDFromUID(uid); ok {
+			t.ID = id
+			return
+		}
+		t.ExternalID = uid
+	case "SUMMARY":
+		t.Title = unescapeText(p.value)
+	case "DESCRIPTION":
+		t.Description = unescapeText(p.value)
+	case "PRIORITY":
+		n, err := strconv.Atoi(p.value)
+		if err != nil {
+			addErr("PRIORITYを解析できません: %s", p.value)
+			return
+		}
+		t.Priority = fromICalPriority(n)
+	case "STATUS":
+		s, ok := fromICalStatus(p.value)
+		if !ok {
+			addErr("STATUSを解析できません: %s", p.value)
+			return
+		}
+		t.Status = s
+	case "DUE", "CREATED", "COMPLETED":
+		tm, err := parseTime(p)
+		if err != nil {
+			addErr("%sを解析できません: %s", p.name, p.value)
+			return
+		}
+		switch p.name {
+		case "DUE":
+			t.DueDate = tm
+		case "CREATED":
+			t.CreatedAt = tm
+		case "COMPLETED":
+			t.CompletedAt = tm
+			if t.Status == "" {
+				t.Status = model.StatusDone
+			}
+		}
+	case "X-ESTIMATED-DURATION", "DURATION":
+		d, err := parseDuration(p.value)
+		if err != nil {
+			addErr("%sを解析できません: %s", p.name, p.value)
+			return
+		}
+		t.EstimatedDuration = int(d.Minutes())
+	}
+}
+
+type rawLine struct {
+	number int
+	text   string
+}
+
+// unfold 折り返された行を連結する
+func unfold(r io.Reader) ([]rawLine, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []rawLine
+	n := 0
+	for sc.Scan() {
+		n++
+		text := strings.TrimRight(sc.Text(), "\r")
+		if n == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		lines = append(lines, rawLine{number: n, text: text})
+	}
+	return lines, sc.Err()
+}
+
+// parseLine "NAME;PARAM=VALUE:value" 形式の行を分解する。引用符内の ":" と ";" は区切りとみなさない
+func parseLine(s string) (property, error) {
+	p := property{params: map[string]string{}}
+	inQuote := false
+	colon := -1
+	for i, r := range s {
+		if r == '"' {
+			inQuote = !inQuote
+		}
+		if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return p, fmt.Errorf("不正な行です: %q", s)
+	}
+
+	head := s[:colon]
+	p.value = s[colon+1:]
+	parts := strings.Split(head, ";")
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+// parseTime DATE、UTCのDATE-TIME、TZID付きまたはローカルのDATE-TIMEを解析する
+func parseTime(p property) (time.Time, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len(dateLayout) {
+		return time.ParseInLocation(dateLayout, p.value, time.Local)
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		return time.Parse(dateTimeLayout, p.value)
+	}
+
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation("20060102T150405", p.value, loc)
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration "PT1H30M" のようなDURATION型の値を解析する
+func parseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimPrefix(s, "+"))
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+1])
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
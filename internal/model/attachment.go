@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// @swagger:model Attachment
+type Attachment struct {
+	// @添付ファイルのID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @元のファイル名
+	// @example: receipt.pdf
+	Filename string `json:"filename"`
+
+	// @内容から判定したContent-Type
+	// @example: application/pdf
+	ContentType string `json:"content_type"`
+
+	// @ファイルサイズ（バイト）
+	// @example: 20480
+	Size int64 `json:"size"`
+
+	// @アップロードしたユーザー名
+	// @example: yamada
+	UploadedBy string `json:"uploaded_by"`
+
+	// @アップロード日時
+	// @example: 2023-01-02T09:00:00Z
+	CreatedAt time.Time `json:"created_at"`
+
+	// ストレージ上のキー。APIには公開しない
+	StorageKey string `json:"-"`
+}
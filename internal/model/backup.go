@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// BackupSummary バックアップまたは復元した内容の概要
+type BackupSummary struct {
+	// バックアップの形式のバージョン
+	Version int
+	// バックアップを作成した日時
+	CreatedAt time.Time
+	// テーブルごとの行数（外部キーの参照先から順に並ぶ）
+	Tables []BackupTableSummary
+	// 添付ファイルの内容の件数
+	Blobs int
+	// 保存先に内容が見つからなかった添付ファイルのキー
+	MissingBlobs []string
+}
+
+// BackupTableSummary テーブルごとの行数
+type BackupTableSummary struct {
+	Name string
+	Rows int
+}
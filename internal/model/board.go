@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// PresenceState ボードを見ているユーザーの状態
+type PresenceState string
+
+const (
+	PresenceViewing PresenceState = "viewing"
+	PresenceEditing PresenceState = "editing"
+	// PresenceIdle ボードを開いているが、特定のタスクを見ていない
+	PresenceIdle PresenceState = "idle"
+)
+
+// Valid 定義済みの状態かどうか
+func (s PresenceState) Valid() bool {
+	switch s {
+	case PresenceViewing, PresenceEditing, PresenceIdle:
+		return true
+	default:
+		return false
+	}
+}
+
+// @swagger:model Presence
+type Presence struct {
+	// @接続のID。同じユーザーが複数の画面から接続している場合に区別する
+	// @example: 3
+	ConnectionID int64 `json:"connection_id"`
+
+	// @ユーザー名
+	// @example: yamada
+	User string `json:"user"`
+
+	// @状態 (viewing, editing, idle)
+	// @example: editing
+	State PresenceState `json:"state"`
+
+	// @見ている、または編集しているタスクのID
+	// @example: 1
+	TaskID int `json:"task_id,omitempty"`
+
+	// @状態が変わった日時
+	// @example: 2023-01-02T09:00:00Z
+	Since time.Time `json:"since"`
+}
@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+// BulkOp 一括操作の種類
+type BulkOp string
+
+const (
+	BulkCreate      BulkOp = "create"
+	BulkComplete    BulkOp = "complete"
+	BulkDelete      BulkOp = "delete"
+	BulkSetPriority BulkOp = "set_priority"
+	BulkSetDueDate  BulkOp = "set_due_date"
+	BulkSetDuration BulkOp = "set_duration"
+	BulkSetStatus   BulkOp = "set_status"
+	BulkSetProject  BulkOp = "set_project"
+)
+
+// BulkMode 一括操作で失敗した操作があった場合の扱い
+type BulkMode string
+
+const (
+	// BulkAtomic 1件でも失敗したらすべての操作を取り消す
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort 失敗した操作だけを取り消し、残りは反映する
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// BulkOperation 一括操作の1件分
+type BulkOperation struct {
+	Op BulkOp
+	// 操作対象のタスクID（create以外）
+	TaskID int
+	// 操作対象のタスクのバージョン。0以外の場合は一致するときのみ操作する
+	Version           int
+	Title             string
+	Description       string
+	Priority          int
+	DueDate           time.Time
+	EstimatedDuration int
+	Status            Status
+	Project           string
+}
+
+// BulkItemStatus 一括操作の1件ごとの結果
+type BulkItemStatus string
+
+const (
+	BulkItemOK BulkItemStatus = "ok"
+	// BulkItemError 操作が失敗した
+	BulkItemError BulkItemStatus = "error"
+	// BulkItemRolledBack 成功したが、他の操作の失敗により取り消された
+	BulkItemRolledBack BulkItemStatus = "rolled_back"
+)
+
+// @swagger:model BulkResult
+type BulkResult struct {
+	// @操作の順番（0始まり）
+	// @example: 0
+	Index int `json:"index"`
+
+	// @操作の種類
+	// @example: complete
+	Op BulkOp `json:"op"`
+
+	// @操作したタスクのID（createでは作成したタスクのID）
+	// @example: 1
+	TaskID int `json:"task_id,omitempty"`
+
+	// @結果 (ok, error, rolled_back)
+	// @example: ok
+	Status BulkItemStatus `json:"status"`
+
+	// @エラーの内容
+	// @example: タスクが見つかりません
+	Error string `json:"error,omitempty"`
+}
+
+// @swagger:model BulkReport
+type BulkReport struct {
+	// @失敗時の扱い (atomic, best_effort)
+	// @example: atomic
+	Mode BulkMode `json:"mode"`
+
+	// @変更を保存したかどうか
+	// @example: true
+	Committed bool `json:"committed"`
+
+	// @成功した件数
+	// @example: 3
+	Succeeded int `json:"succeeded"`
+
+	// @失敗した件数
+	// @example: 0
+	Failed int `json:"failed"`
+
+	// @操作ごとの結果
+	Results []BulkResult `json:"results"`
+}
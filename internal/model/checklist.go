@@ -0,0 +1,61 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// @swagger:model ChecklistItem
+type ChecklistItem struct {
+	// @チェックリスト項目のID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @表示順（0始まり）
+	// @example: 0
+	Position int `json:"position"`
+
+	// @項目の内容
+	// @example: 牛乳売り場を確認する
+	// @required: true
+	Title string `json:"title"`
+
+	// @項目の完了状態
+	// @example: false
+	Done bool `json:"done"`
+
+	// @項目の完了日時
+	// @example: 2023-01-02T15:30:00Z
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// @swagger:model ChecklistProgress
+type ChecklistProgress struct {
+	// @完了した項目数
+	// @example: 3
+	Done int `json:"done"`
+
+	// @項目の総数
+	// @example: 5
+	Total int `json:"total"`
+}
+
+// Remaining 未完了の項目の割合 (0〜1)。項目がない場合は1
+func (p ChecklistProgress) Remaining() float64 {
+	if p.Total == 0 {
+		return 1
+	}
+	return float64(p.Total-p.Done) / float64(p.Total)
+}
+
+// String 進捗を "3/5" の形式で返す
+func (p ChecklistProgress) String() string {
+	if p.Total == 0 {
+		return "-"
+	}
+	return strconv.Itoa(p.Done) + "/" + strconv.Itoa(p.Total)
+}
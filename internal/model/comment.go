@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// @swagger:model Comment
+type Comment struct {
+	// @コメントのID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @コメントの投稿者
+	// @example: yamada
+	Author string `json:"author"`
+
+	// @コメント本文（Markdown）
+	// @example: 低脂肪牛乳が**売り切れ**なら普通の牛乳で
+	// @required: true
+	Body string `json:"body"`
+
+	// @投稿日時
+	// @example: 2023-01-02T09:00:00Z
+	CreatedAt time.Time `json:"created_at"`
+
+	// @最終編集日時
+	// @example: 2023-01-02T09:30:00Z
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
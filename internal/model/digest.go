@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// DigestRecipient 毎朝のまとめを受け取るユーザーとメールアドレス
+type DigestRecipient struct {
+	User  string
+	Email string
+}
+
+// Digest ユーザーごとの毎朝のまとめ
+type Digest struct {
+	User string
+	// Date まとめの対象日（その日の0時）
+	Date time.Time
+	// Recommendations 今日のおすすめのタスク
+	Recommendations []Recommendation
+	// Overdue 期限を過ぎた未完了のタスク
+	Overdue []Task
+	// Completed 前日にユーザーが完了したタスク
+	Completed []Task
+}
+
+// Empty まとめに載せる内容がないかどうか
+func (d Digest) Empty() bool {
+	return len(d.Recommendations) == 0 && len(d.Overdue) == 0 && len(d.Completed) == 0
+}
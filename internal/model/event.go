@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+// EventType タスクのライフサイクルイベントの種類
+type EventType string
+
+const (
+	EventTaskCreated   EventType = "task.created"
+	EventTaskUpdated   EventType = "task.updated"
+	EventTaskCompleted EventType = "task.completed"
+	EventTaskDeleted   EventType = "task.deleted"
+)
+
+// EventTypes 定義済みのイベントの種類
+var EventTypes = []EventType{EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted}
+
+// Valid 定義済みのイベントの種類かどうか
+func (t EventType) Valid() bool {
+	for _, e := range EventTypes {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
+
+// EventTypeForHistory 変更履歴の操作種別に対応するイベントの種類。イベントにしない操作は空を返す
+//
+// 状態が done になった変更は task.completed、ゴミ箱への移動は task.deleted、
+// 復元や他の状態への遷移を含むそれ以外の変更は task.updated とする。完全な削除はイベントにしない
+func EventTypeForHistory(action HistoryAction, newValue string) EventType {
+	switch action {
+	case HistoryCreated:
+		return EventTaskCreated
+	case HistoryStatusChanged:
+		if Status(newValue) == StatusDone {
+			return EventTaskCompleted
+		}
+		return EventTaskUpdated
+	case HistoryUpdated, HistoryRestored:
+		return EventTaskUpdated
+	case HistoryDeleted:
+		return EventTaskDeleted
+	default:
+		return ""
+	}
+}
+
+// @swagger:model TaskEvent
+type TaskEvent struct {
+	// @イベントのID（変更履歴のID）
+	// @example: 42
+	ID int64 `json:"id"`
+
+	// @イベントの種類 (task.created, task.updated, task.completed, task.deleted)
+	// @example: task.completed
+	Type EventType `json:"type"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @対象タスクのプロジェクト（現在の値）
+	// @example: 買い物
+	Project string `json:"project,omitempty"`
+
+	// @変更した項目
+	// @example: status
+	Field string `json:"field,omitempty"`
+
+	// @変更前の値
+	// @example: in_progress
+	OldValue string `json:"old_value,omitempty"`
+
+	// @変更後の値
+	// @example: done
+	NewValue string `json:"new_value,omitempty"`
+
+	// @操作したユーザー名
+	// @example: yamada
+	Actor string `json:"actor"`
+
+	// @発生日時
+	// @example: 2023-01-02T09:00:00Z
+	OccurredAt time.Time `json:"occurred_at"`
+}
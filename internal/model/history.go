@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// HistoryAction 変更履歴の操作種別
+type HistoryAction string
+
+const (
+	HistoryCreated       HistoryAction = "created"
+	HistoryUpdated       HistoryAction = "updated"
+	HistoryStatusChanged HistoryAction = "status_changed"
+	HistoryDeleted       HistoryAction = "deleted"
+	HistoryRestored      HistoryAction = "restored"
+	HistoryPurged        HistoryAction = "purged"
+)
+
+// @swagger:model HistoryEntry
+type HistoryEntry struct {
+	// @変更履歴のID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @操作種別 (created, updated, status_changed, deleted, restored, purged)
+	// @example: updated
+	Action HistoryAction `json:"action"`
+
+	// @変更した項目（作成・削除などの場合は空）
+	// @example: priority
+	Field string `json:"field,omitempty"`
+
+	// @変更前の値
+	// @example: 1
+	OldValue string `json:"old_value,omitempty"`
+
+	// @変更後の値
+	// @example: 3
+	NewValue string `json:"new_value,omitempty"`
+
+	// @操作したユーザー名
+	// @example: yamada
+	Actor string `json:"actor"`
+
+	// @操作日時
+	// @example: 2023-01-02T09:00:00Z
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,86 @@
+package model
+
+// ImportRecord インポートするファイルの1行分
+type ImportRecord struct {
+	// ファイル上の行番号（ヘッダーを含めて1始まり）
+	Line int
+	Task Task
+	// 値の解析に失敗した項目
+	Errors []string
+	// ファイルに含まれていた項目名 (title, description, priority, due_date, estimated_duration, project, contexts など)。
+	// 既存のタスクを更新するときは含まれていた項目だけを書き換える。nilの場合はすべての項目を書き換える
+	Fields []string
+}
+
+// Has 項目がファイルに含まれていたかを返す
+func (r ImportRecord) Has(field string) bool {
+	if r.Fields == nil {
+		return true
+	}
+	for _, f := range r.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportAction インポートした行の処理内容
+type ImportAction string
+
+const (
+	ImportCreate ImportAction = "create"
+	ImportUpdate ImportAction = "update"
+	ImportError  ImportAction = "error"
+	// ImportSkip 他の行にエラーがあったため保存しなかった行
+	ImportSkip ImportAction = "skip"
+)
+
+// @swagger:model ImportResult
+type ImportResult struct {
+	// @ファイル上の行番号
+	// @example: 2
+	Line int `json:"line"`
+
+	// @外部ID
+	// @example: sheet-row-12
+	ExternalID string `json:"external_id,omitempty"`
+
+	// @タスクのタイトル
+	// @example: 牛乳を買う
+	Title string `json:"title"`
+
+	// @処理内容 (create, update, skip, error)
+	// @example: create
+	Action ImportAction `json:"action"`
+
+	// @作成・更新したタスクのID（ドライランでは更新対象のIDのみ）
+	// @example: 1
+	TaskID int `json:"task_id,omitempty"`
+
+	// @検証エラー
+	// @example: ["優先度は1〜3で指定してください"]
+	Errors []string `json:"errors,omitempty"`
+}
+
+// @swagger:model ImportReport
+type ImportReport struct {
+	// @ドライランかどうか
+	// @example: true
+	DryRun bool `json:"dry_run"`
+
+	// @作成した（する）件数
+	// @example: 3
+	Created int `json:"created"`
+
+	// @更新した（する）件数
+	// @example: 1
+	Updated int `json:"updated"`
+
+	// @エラーの件数
+	// @example: 0
+	Failed int `json:"failed"`
+
+	// @行ごとの結果
+	Results []ImportResult `json:"results"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// @swagger:model WorkBlock
+type WorkBlock struct {
+	// @作業するタスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @作業するタスクのタイトル
+	// @example: 牛乳を買う
+	Title string `json:"title"`
+
+	// @作業の開始日時
+	// @example: 2023-01-02T09:00:00+09:00
+	Start time.Time `json:"start"`
+
+	// @作業の終了日時
+	// @example: 2023-01-02T10:30:00+09:00
+	End time.Time `json:"end"`
+}
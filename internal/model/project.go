@@ -0,0 +1,27 @@
+package model
+
+// @swagger:model ProjectSummary
+type ProjectSummary struct {
+	// @プロジェクト名
+	// @example: 買い物
+	Name string `json:"name"`
+
+	// @ゴミ箱にないタスクの数
+	// @example: 5
+	TaskCount int `json:"task_count"`
+
+	// @完了も中止もしていないタスクの数
+	// @example: 3
+	OpenTaskCount int `json:"open_task_count"`
+}
+
+// @swagger:model TagSummary
+type TagSummary struct {
+	// @タグ（タスクの状況）
+	// @example: 外出
+	Name string `json:"name"`
+
+	// @タグが付いたゴミ箱にないタスクの数
+	// @example: 4
+	TaskCount int `json:"task_count"`
+}
@@ -0,0 +1,19 @@
+package model
+
+// @swagger:model Recommendation
+type Recommendation struct {
+	// @おすすめのタスク
+	Task Task `json:"task"`
+
+	// @おすすめ度（大きいほど先に取り組むべき）
+	// @example: 42.5
+	Score float64 `json:"score"`
+
+	// @残りの見積所要時間（分）。チェックリストの未完了の割合を反映する
+	// @example: 18
+	RemainingEffort int `json:"remaining_effort"`
+
+	// @おすすめの理由
+	// @example: ["優先度が高い", "期限が明日"]
+	Reasons []string `json:"reasons"`
+}
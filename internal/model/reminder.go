@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ReminderKind リマインダーの種類
+type ReminderKind string
+
+const (
+	// ReminderDueSoon 期限が近づいた
+	ReminderDueSoon ReminderKind = "due_soon"
+	// ReminderOverdue 期限を過ぎた
+	ReminderOverdue ReminderKind = "overdue"
+)
+
+// Reminder 期限が近い、または過ぎたタスクの通知1件分
+type Reminder struct {
+	Kind ReminderKind
+	// Lead 期限のどれだけ前に知らせるか。期限切れの場合は0
+	Lead time.Duration
+	Task Task
+}
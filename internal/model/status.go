@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status タスクの状態
+type Status string
+
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusBlocked    Status = "blocked"
+	StatusDone       Status = "done"
+	StatusCancelled  Status = "cancelled"
+)
+
+// statusTransitions 各状態から遷移可能な状態。完了・中止からはtodoへの再開のみ
+var statusTransitions = map[Status][]Status{
+	StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusBlocked, StatusDone, StatusCancelled},
+	StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+	StatusDone:       {StatusTodo},
+	StatusCancelled:  {StatusTodo},
+}
+
+// ParseStatus 文字列を状態に変換する
+func ParseStatus(s string) (Status, error) {
+	st := Status(s)
+	if !st.Valid() {
+		return "", fmt.Errorf("不正な状態です: %q", s)
+	}
+	return st, nil
+}
+
+// Valid 定義済みの状態かどうか
+func (s Status) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo 指定した状態へ遷移できるかどうか
+func (s Status) CanTransitionTo(to Status) bool {
+	for _, next := range statusTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Closed 完了または中止済みかどうか
+func (s Status) Closed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
+
+// @swagger:model StatusTransition
+type StatusTransition struct {
+	// @状態遷移のID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @遷移前の状態（作成時は空）
+	// @example: todo
+	From Status `json:"from,omitempty"`
+
+	// @遷移後の状態
+	// @example: in_progress
+	To Status `json:"to"`
+
+	// @遷移させたユーザー名
+	// @example: yamada
+	Actor string `json:"actor"`
+
+	// @遷移日時
+	// @example: 2023-01-02T09:00:00Z
+	TransitionedAt time.Time `json:"transitioned_at"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// SyncConflict ファイルとデータベースの両方で変更されていた行
+type SyncConflict struct {
+	ExternalID string
+	// ファイル側の行
+	Local string
+	// データベース側の行
+	Remote string
+	// 採用した側 (file, db)
+	Resolved string
+}
+
+// SyncReport 同期の結果
+type SyncReport struct {
+	DryRun bool
+	// データベースからファイルへ反映した行数
+	Pulled int
+	// ファイルからデータベースへ反映した（作成・更新した）タスク数
+	Pushed int
+	// ファイルから削除されたためゴミ箱に移動したタスク数
+	Trashed int
+	// データベースから削除されたためファイルから取り除いた行数
+	Removed   int
+	Conflicts []SyncConflict
+}
@@ -1,15 +1,25 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// MaxTextLength タイトルなど VARCHAR(255) の列に保存する項目の最大文字数
+const MaxTextLength = 255
+
 // @swagger:model Task
 type Task struct {
 	// @タスクのID
 	// @example: 1
 	ID int `json:"id"`
 
+	// @外部システムでのID。インポート時の突き合わせに使う
+	// @example: sheet-row-12
+	ExternalID string `json:"external_id,omitempty"`
+
 	// タスクのタイトル
 	// @example: 牛乳を買う
 	// @required: true
@@ -19,10 +29,18 @@ type Task struct {
 	// @example: スーパーで低脂肪牛乳を購入する
 	Description string `json:"description"`
 
-	// @タスクの完了状態
+	// @タスクの完了状態（後方互換のため status が done の場合に true）
 	// @example: false
 	Done bool `json:"done"`
 
+	// @タスクの状態 (todo, in_progress, blocked, done, cancelled)
+	// @example: in_progress
+	Status Status `json:"status"`
+
+	// @タスクの状態が最後に変わった日時
+	// @example: 2023-01-02T09:00:00Z
+	StatusChangedAt time.Time `json:"status_changed_at"`
+
 	// @タスクの優先度 (1=低, 2=中, 3=高)
 	// @example: 2
 	// @min: 1
@@ -38,6 +56,22 @@ type Task struct {
 	// @min: 0
 	EstimatedDuration int `json:"estimated_duration"`
 
+	// @タスクの実績時間（分）。時間記録の合計
+	// @example: 45
+	// @min: 0
+	TrackedDuration int `json:"tracked_duration"`
+
+	// @タスクが属するプロジェクト
+	// @example: 買い物
+	Project string `json:"project,omitempty"`
+
+	// @タスクを行う状況（場所や道具など）
+	// @example: ["外出", "電話"]
+	Contexts []string `json:"contexts,omitempty"`
+
+	// @チェックリストの進捗
+	Checklist ChecklistProgress `json:"checklist"`
+
 	// @タスクの作成日時
 	// @example: 2023-01-01T10:00:00Z
 	CreatedAt time.Time `json:"created_at"`
@@ -45,4 +79,111 @@ type Task struct {
 	// @タスクの完了日時
 	// @example: 2023-01-02T15:30:00Z
 	CompletedAt time.Time `json:"completed_at,omitempty"`
+
+	// @タスクをゴミ箱に移動した日時
+	// @example: 2023-01-03T12:00:00Z
+	DeletedAt time.Time `json:"deleted_at,omitempty"`
+
+	// @タスクのバージョン。更新のたびに1つ増える
+	// @example: 3
+	Version int `json:"version"`
+}
+
+// PriorityLabel 優先度を表示用の文字列 (高・中・低) に変換する
+func PriorityLabel(priority int) string {
+	switch {
+	case priority >= 3:
+		return "高"
+	case priority == 2:
+		return "中"
+	default:
+		return "低"
+	}
+}
+
+// PriorityLabel タスクの優先度を表示用の文字列に変換する
+func (t Task) PriorityLabel() string {
+	return PriorityLabel(t.Priority)
+}
+
+// DueDateOnly 期限が日付だけで、時刻を指定していない（0時0分）かどうか
+func (t Task) DueDateOnly() bool {
+	if t.DueDate.IsZero() {
+		return false
+	}
+	h, m, s := t.DueDate.Clock()
+	return h == 0 && m == 0 && s == 0 && t.DueDate.Nanosecond() == 0
+}
+
+// DueAt 期限の時刻。日付だけの期限はその日の終わり（翌日の0時）とする
+func (t Task) DueAt() time.Time {
+	if !t.DueDateOnly() {
+		return t.DueDate
+	}
+	y, m, d := t.DueDate.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.DueDate.Location())
+}
+
+// Validate タスクの入力値を検証し、問題点の一覧を返す
+func (t Task) Validate() []string {
+	var problems []string
+	if strings.TrimSpace(t.Title) == "" {
+		problems = append(problems, "タイトルは必須です")
+	}
+	if utf8.RuneCountInString(t.Title) > MaxTextLength {
+		problems = append(problems, fmt.Sprintf("タイトルは%d文字以内で指定してください", MaxTextLength))
+	}
+	if utf8.RuneCountInString(t.ExternalID) > MaxTextLength {
+		problems = append(problems, fmt.Sprintf("外部IDは%d文字以内で指定してください", MaxTextLength))
+	}
+	if utf8.RuneCountInString(t.Project) > MaxTextLength {
+		problems = append(problems, fmt.Sprintf("プロジェクトは%d文字以内で指定してください", MaxTextLength))
+	}
+	if t.Priority < 1 || t.Priority > 3 {
+		problems = append(problems, "優先度は1〜3で指定してください")
+	}
+	if t.EstimatedDuration < 0 {
+		problems = append(problems, "見積所要時間は0以上で指定してください")
+	}
+	if t.Status != "" && !t.Status.Valid() {
+		problems = append(problems, "不正な状態です: "+string(t.Status))
+	}
+	return problems
+}
+
+// TaskFilter タスクの絞り込み条件。空の項目では絞り込まない
+type TaskFilter struct {
+	Status  Status
+	Project string
+	Context string
+}
+
+// Match タスクが条件に一致するかどうか
+func (f TaskFilter) Match(t Task) bool {
+	if f.Status != "" && t.Status != f.Status {
+		return false
+	}
+	if f.Project != "" && t.Project != f.Project {
+		return false
+	}
+	if f.Context != "" {
+		for _, c := range t.Contexts {
+			if c == f.Context {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Filter 条件に一致するタスクだけを返す
+func (f TaskFilter) Filter(tasks []Task) []Task {
+	filtered := []Task{}
+	for _, t := range tasks {
+		if f.Match(t) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
 }
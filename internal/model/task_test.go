@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTaskValidate(t *testing.T) {
+	valid := Task{Title: "牛乳を買う", Priority: 2}
+	tests := []struct {
+		name   string
+		modify func(*Task)
+		want   []string
+	}{
+		{"valid", func(*Task) {}, nil},
+		{"empty title", func(t *Task) { t.Title = "  " }, []string{"タイトルは必須です"}},
+		{"title at limit", func(t *Task) { t.Title = strings.Repeat("あ", MaxTextLength) }, nil},
+		{"title too long", func(t *Task) { t.Title = strings.Repeat("あ", MaxTextLength+1) }, []string{"タイトルは255文字以内で指定してください"}},
+		{"external id too long", func(t *Task) { t.ExternalID = strings.Repeat("x", MaxTextLength+1) }, []string{"外部IDは255文字以内で指定してください"}},
+		{"project too long", func(t *Task) { t.Project = strings.Repeat("x", MaxTextLength+1) }, []string{"プロジェクトは255文字以内で指定してください"}},
+		{"priority out of range", func(t *Task) { t.Priority = 4 }, []string{"優先度は1〜3で指定してください"}},
+		{"negative duration", func(t *Task) { t.EstimatedDuration = -1 }, []string{"見積所要時間は0以上で指定してください"}},
+		{"unknown status", func(t *Task) { t.Status = "later" }, []string{"不正な状態です: later"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := valid
+			tt.modify(&task)
+			got := task.Validate()
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriorityLabel(t *testing.T) {
+	tests := []struct {
+		priority int
+		want     string
+	}{
+		{0, "低"}, {1, "低"}, {2, "中"}, {3, "高"}, {4, "高"},
+	}
+	for _, tt := range tests {
+		if got := PriorityLabel(tt.priority); got != tt.want {
+			t.Errorf("PriorityLabel(%d) = %q, want %q", tt.priority, got, tt.want)
+		}
+		if got := (Task{Priority: tt.priority}).PriorityLabel(); got != tt.want {
+			t.Errorf("Task.PriorityLabel() with %d = %q, want %q", tt.priority, got, tt.want)
+		}
+	}
+}
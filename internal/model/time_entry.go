@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// @swagger:model TimeEntry
+type TimeEntry struct {
+	// @時間記録のID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @対象タスクのID
+	// @example: 1
+	TaskID int `json:"task_id"`
+
+	// @計測したユーザー名
+	// @example: yamada
+	User string `json:"user"`
+
+	// @計測開始日時
+	// @example: 2023-01-02T09:00:00Z
+	StartedAt time.Time `json:"started_at"`
+
+	// @計測終了日時（計測中は空）
+	// @example: 2023-01-02T09:45:00Z
+	StoppedAt time.Time `json:"stopped_at,omitempty"`
+}
+
+// Running 計測中かどうか
+func (e TimeEntry) Running() bool {
+	return e.StoppedAt.IsZero()
+}
+
+// Duration 計測時間（計測中の場合はnowまで）
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	if e.Running() {
+		return now.Sub(e.StartedAt)
+	}
+	return e.StoppedAt.Sub(e.StartedAt)
+}
@@ -0,0 +1,91 @@
+package model
+
+import "time"
+
+// @swagger:model Webhook
+type Webhook struct {
+	// @WebhookのID
+	// @example: 1
+	ID int `json:"id"`
+
+	// @送信先のURL
+	// @example: https://example.com/hooks/tasks
+	URL string `json:"url"`
+
+	// @署名用の秘密鍵。登録時のレスポンスでのみ返す
+	// @example: 3f9a1c2b4d5e6f708192a3b4c5d6e7f8
+	Secret string `json:"secret,omitempty"`
+
+	// @送信するイベントの種類。空の場合はすべて
+	// @example: ["task.created", "task.completed"]
+	Events []EventType `json:"events"`
+
+	// @登録したユーザー名
+	// @example: yamada
+	CreatedBy string `json:"created_by"`
+
+	// @登録日時
+	// @example: 2023-01-01T10:00:00Z
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Accepts イベントの種類が送信対象かどうか
+func (w Webhook) Accepts(t EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus Webhookの送信状態
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed 再試行の上限に達した
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// @swagger:model WebhookDelivery
+type WebhookDelivery struct {
+	// @送信のID
+	// @example: 10
+	ID int64 `json:"id"`
+
+	// @WebhookのID
+	// @example: 1
+	WebhookID int `json:"webhook_id"`
+
+	// @送信するイベント
+	Event TaskEvent `json:"event"`
+
+	// @送信状態 (pending, succeeded, failed)
+	// @example: succeeded
+	Status DeliveryStatus `json:"status"`
+
+	// @送信を試みた回数
+	// @example: 1
+	Attempts int `json:"attempts"`
+
+	// @次に送信を試みる日時
+	// @example: 2023-01-02T09:00:30Z
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+
+	// @最後の送信で受け取ったHTTPステータスコード
+	// @example: 200
+	LastStatusCode int `json:"last_status_code,omitempty"`
+
+	// @最後の送信のエラー
+	// @example: connection refused
+	LastError string `json:"last_error,omitempty"`
+
+	// @送信に成功した日時
+	// @example: 2023-01-02T09:00:01Z
+	DeliveredAt time.Time `json:"delivered_at,omitempty"`
+}
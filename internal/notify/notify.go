@@ -0,0 +1,113 @@
+// Package notify メール、Webhook、Slackの Incoming Webhook へ通知を送る
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// Message 通知の内容
+type Message struct {
+	// Kind 通知の種類（例: reminder.due_soon）。受け取る側で振り分けるために使う
+	Kind    string
+	Subject string
+	Text    string
+	// Task 通知の対象のタスク。タスクに関係しない通知ではnil
+	Task *model.Task
+}
+
+// Notifier 通知の送り先
+type Notifier interface {
+	// Name 送り先の名前。同じ通知を送り先ごとに1度だけ送るために使う
+	Name() string
+	Notify(m Message) error
+}
+
+// Mailer 宛先を指定してメールを送る
+type Mailer interface {
+	Send(to []string, subject, text, html string) error
+}
+
+// Webhook 通知をJSONでPOSTする汎用のWebhook
+type Webhook struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewWebhook URLにPOSTするWebhookを作成
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	return &Webhook{URL: url, HTTP: &http.Client{Timeout: timeout}}
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+// webhookPayload Webhookに送る本文
+type webhookPayload struct {
+	Kind    string      `json:"kind"`
+	Subject string      `json:"subject"`
+	Text    string      `json:"text"`
+	Task    *model.Task `json:"task,omitempty"`
+	SentAt  time.Time   `json:"sent_at"`
+}
+
+func (w *Webhook) Notify(m Message) error {
+	return postJSON(w.HTTP, w.URL, webhookPayload{
+		Kind:    m.Kind,
+		Subject: m.Subject,
+		Text:    m.Text,
+		Task:    m.Task,
+		SentAt:  time.Now(),
+	})
+}
+
+// Slack Slack互換の Incoming Webhook。件名を太字にした1つのメッセージとして送る
+type Slack struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewSlack Incoming WebhookのURLに送るSlackを作成
+func NewSlack(url string, timeout time.Duration) *Slack {
+	return &Slack{URL: url, HTTP: &http.Client{Timeout: timeout}}
+}
+
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+func (s *Slack) Notify(m Message) error {
+	return postJSON(s.HTTP, s.URL, map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", slackEscape(m.Subject), slackEscape(m.Text)),
+	})
+}
+
+// slackEscape Slackのメッセージで特別な意味を持つ文字をエスケープする
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+// postJSON vをJSONでPOSTし、2xx以外の応答をエラーにする
+func postJSON(client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: %s が %d を返しました", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// ReminderMessage 期限のリマインダーの通知を作成する
+func ReminderMessage(r model.Reminder, now time.Time) Message {
+	t := r.Task
+	due := t.DueDate.Format("2006-01-02 15:04")
+	if t.DueDateOnly() {
+		due = t.DueDate.Format("2006-01-02")
+	}
+
+	m := Message{Kind: "reminder." + string(r.Kind), Task: &t}
+	switch r.Kind {
+	case model.ReminderOverdue:
+		m.Subject = fmt.Sprintf("期限切れのタスク: %s", t.Title)
+		m.Text = fmt.Sprintf("タスク「%s」(ID=%d) は期限 %s を%s過ぎています。", t.Title, t.ID, due, formatDuration(now.Sub(t.DueAt())))
+	default:
+		m.Subject = fmt.Sprintf("期限が近いタスク: %s", t.Title)
+		m.Text = fmt.Sprintf("タスク「%s」(ID=%d) の期限は %s です（あと%s）。", t.Title, t.ID, due, formatDuration(t.DueAt().Sub(now)))
+	}
+	if t.Project != "" {
+		m.Text += fmt.Sprintf("\nプロジェクト: %s", t.Project)
+	}
+	return m
+}
+
+// formatDuration 期間を「約N日」「約N時間」「N分」のように表す
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("約%d日", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("約%d時間", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d分", int(d.Minutes()))
+	}
+}
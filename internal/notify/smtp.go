@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// SMTP 通知をメールで送る
+type SMTP struct {
+	// Addr SMTPサーバーのアドレス (host:port)
+	Addr string
+	// Username 空の場合は認証しない
+	Username string
+	Password string
+	From     string
+	// To 通知を送る宛先
+	To []string
+}
+
+func (s *SMTP) Name() string {
+	return "email"
+}
+
+func (s *SMTP) Notify(m Message) error {
+	return s.Send(s.To, m.Subject, m.Text, "")
+}
+
+// Send メールを送る。htmlが空でない場合はテキストとHTMLの両方を含む multipart/alternative で送る
+func (s *SMTP) Send(to []string, subject, text, html string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("notify: メールの宛先がありません")
+	}
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("notify: 送信元のアドレスが不正です: %w", err)
+	}
+	recipients := make([]*mail.Address, 0, len(to))
+	for _, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("notify: 宛先のアドレスが不正です: %w", err)
+		}
+		recipients = append(recipients, a)
+	}
+
+	msg, err := buildMessage(from, recipients, subject, text, html, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+
+	rcpt := make([]string, 0, len(recipients))
+	for _, a := range recipients {
+		rcpt = append(rcpt, a.Address)
+	}
+	return smtp.SendMail(s.Addr, auth, from.Address, rcpt, msg)
+}
+
+// buildMessage ヘッダーと本文からなるメールを組み立てる
+func buildMessage(from *mail.Address, to []*mail.Address, subject, text, html string, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	addrs := make([]string, 0, len(to))
+	for _, a := range to {
+		addrs = append(addrs, a.String())
+	}
+	header("From", from.String())
+	header("To", strings.Join(addrs, ", "))
+	header("Subject", mime.QEncoding.Encode("UTF-8", subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from, now))
+	header("MIME-Version", "1.0")
+
+	if html == "" {
+		header("Content-Type", "text/plain; charset=UTF-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	header("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable 改行をCRLFにそろえ、quoted-printableで書き出す
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID 送信元のドメインを使ったMessage-IDを生成する
+func messageID(from *mail.Address, now time.Time) string {
+	domain := "localhost"
+	if i := strings.LastIndexByte(from.Address, '@'); i >= 0 {
+		domain = from.Address[i+1:]
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	return fmt.Sprintf("<%d.%s@%s>", now.UnixNano(), hex.EncodeToString(b), domain)
+}
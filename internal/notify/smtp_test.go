@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSink 1通だけ受け取るSMTPサーバー。認証やSTARTTLSは提供しない
+type smtpSink struct {
+	addr     string
+	from     string
+	rcpt     []string
+	data     chan []byte
+	listener net.Listener
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpSink{addr: l.Addr().String(), data: make(chan []byte, 1), listener: l}
+	t.Cleanup(func() { l.Close() })
+	go s.serve(t)
+	return s
+}
+
+func (s *smtpSink) serve(t *testing.T) {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(code int, msg string) {
+		if err := tp.PrintfLine("%d %s", code, msg); err != nil {
+			t.Errorf("smtp sink: %v", err)
+		}
+	}
+
+	reply(220, "sink ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply(250, "sink")
+		case "MAIL":
+			s.from = arg
+			reply(250, "OK")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, arg)
+			reply(250, "OK")
+		case "DATA":
+			reply(354, "end with <CRLF>.<CRLF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				t.Errorf("smtp sink: %v", err)
+				return
+			}
+			s.data <- data
+			reply(250, "queued")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "not implemented")
+		}
+	}
+}
+
+func TestSMTPSendMultipartAlternative(t *testing.T) {
+	sink := newSMTPSink(t)
+	m := &SMTP{Addr: sink.addr, From: "タスク管理 <noreply@example.com>"}
+
+	subject := "今日のまとめ: おすすめ3件"
+	text := "おはようございます\n期限切れ: 1件"
+	html := "<p>おはようございます</p>"
+	if err := m.Send([]string{"user@example.com", "Other <other@example.com>"}, subject, text, html); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if sink.from != "FROM:<noreply@example.com>" {
+		t.Errorf("MAIL %s", sink.from)
+	}
+	if strings.Join(sink.rcpt, " ") != "TO:<user@example.com> TO:<other@example.com>" {
+		t.Errorf("RCPT %v", sink.rcpt)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(<-sink.data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawSubject := msg.Header.Get("Subject")
+	if !strings.HasPrefix(strings.ToLower(rawSubject), "=?utf-8?q?") {
+		t.Errorf("Subject is not Q-encoded: %q", rawSubject)
+	}
+	if got, err := new(mime.WordDecoder).DecodeHeader(rawSubject); err != nil || got != subject {
+		t.Errorf("Subject = %q, %v, want %q", got, err, subject)
+	}
+	if msg.Header.Get("MIME-Version") != "1.0" || msg.Header.Get("Message-ID") == "" || msg.Header.Get("Date") == "" {
+		t.Errorf("headers = %v", msg.Header)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	want := []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	}
+	for i, w := range want {
+		part, err := mr.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Errorf("part %d Content-Type = %q, want %q", i, got, w.contentType)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Errorf("part %d Content-Transfer-Encoding = %q", i, got)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 改行はCRLFで送る。quoted-printableの読み込みで行末の扱いが変わるため、LFにそろえて比べる
+		if strings.ReplaceAll(string(body), "\r\n", "\n") != w.body {
+			t.Errorf("part %d body = %q, want %q", i, body, w.body)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("extra part: %v", err)
+	}
+}
+
+func TestBuildMessagePlainText(t *testing.T) {
+	from := &mail.Address{Address: "noreply@example.com"}
+	to := []*mail.Address{{Address: "user@example.com"}}
+	raw, err := buildMessage(from, to, "Reminder", "line1\nline2", "", time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(raw))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ReplaceAll(string(body), "\r\n", "\n") != "line1\nline2" {
+		t.Errorf("body = %q", body)
+	}
+}
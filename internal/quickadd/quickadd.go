@@ -0,0 +1,301 @@
+// Package quickadd 「明日までにレポート提出 !高 90分」や「submit report tomorrow p3 1h30m #work」のような
+// 自然文から、タスクのタイトル、優先度、期限日、見積所要時間、プロジェクトとタグを取り出す
+//
+// 認識する書き方:
+//
+//	優先度      !高 !中 !低, 優先度高, !high !medium !low, !1〜!3, p1〜p3（3が高）
+//	期限日      今日 明日 明後日 N日後 N週間後 金曜 今週金曜 来週金曜 来週の金曜 10月20日 10/20 2026-10-20 due:2026-10-20
+//	            today tomorrow "in 3 days" "in 2 weeks" friday "this friday" "next friday"
+//	            日付の後の「まで」「までに」「までの」「の」、前の by / due / on も取り除く
+//	見積時間    90分 1時間 1.5時間 1時間30分, 90m 90min 1h 1h30m "2 hours"
+//	プロジェクト +name
+//	タグ        #name @name
+//
+// 全角の英数字と記号は半角として扱う。認識した部分を取り除いた残りがタイトルになる
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/width"
+
+	"task-recommender/internal/model"
+)
+
+// Result 解釈した結果。指定がなかった項目はゼロ値になる
+type Result struct {
+	Title             string
+	Priority          int
+	DueDate           time.Time
+	EstimatedDuration int
+	Project           string
+	Tags              []string
+}
+
+// DefaultPriority 優先度の指定がない場合の優先度
+const DefaultPriority = 1
+
+// Task 結果からタスクを作成する。タグはタスクの状況 (Contexts) として扱う
+func (r Result) Task() model.Task {
+	priority := r.Priority
+	if priority == 0 {
+		priority = DefaultPriority
+	}
+	return model.Task{
+		Title:             r.Title,
+		Priority:          priority,
+		DueDate:           r.DueDate,
+		EstimatedDuration: r.EstimatedDuration,
+		Project:           r.Project,
+		Contexts:          r.Tags,
+	}
+}
+
+// field 規則が設定する項目
+type field int
+
+const (
+	fieldTags field = iota
+	fieldProject
+	fieldPriority
+	fieldDueDate
+	fieldDuration
+)
+
+// rule 自然文の一部を認識する規則。applyがfalseを返した場合は取り除かない
+//
+// タグ以外の項目は最初に認識した1か所だけを使い、残りはタイトルの一部とする
+type rule struct {
+	field field
+	re    *regexp.Regexp
+	apply func(m []string, r *Result, today time.Time) bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"日": time.Sunday, "月": time.Monday, "火": time.Tuesday, "水": time.Wednesday,
+	"木": time.Thursday, "金": time.Friday, "土": time.Saturday,
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// weekWords 曜日の前に付けて週を指定する語
+var weekWords = map[string]string{"来週": "next", "来週の": "next", "次の": "next", "今週": "this", "今週の": "this"}
+
+var priorities = map[string]int{
+	"高": 3, "中": 2, "低": 1,
+	"high": 3, "medium": 2, "med": 2, "low": 1,
+	"3": 3, "2": 2, "1": 1,
+}
+
+// 日付の前後に付く語
+const (
+	jaDueSuffix = `(?:までに|までの|まで|の)?`
+	enDuePrefix = `(?:\b(?:by|due|on)\s+)?`
+)
+
+// rules 適用する順に並べる。タグを先に取り除き、期限日は見積時間より先に認識する
+var rules = []rule{
+	// タグとプロジェクト
+	{field: fieldTags, re: regexp.MustCompile(`(?:^|\s)[#@]([^\s#@]+)`), apply: func(m []string, r *Result, _ time.Time) bool {
+		r.Tags = append(r.Tags, m[1])
+		return true
+	}},
+	{field: fieldProject, re: regexp.MustCompile(`(?:^|\s)\+([^\s+]+)`), apply: func(m []string, r *Result, _ time.Time) bool {
+		r.Project = m[1]
+		return true
+	}},
+
+	// 優先度
+	{field: fieldPriority, re: regexp.MustCompile(`(?i)(?:!|優先度[:：]?\s*)(高|中|低|high|medium|med|low|[1-3])`), apply: setPriority},
+	{field: fieldPriority, re: regexp.MustCompile(`(?i)\bp([1-3])\b`), apply: setPriority},
+
+	// 期限日（日付の指定）
+	{field: fieldDueDate, re: regexp.MustCompile(`(?i)` + enDuePrefix + `(?:due:)?(\d{4})-(\d{1,2})-(\d{1,2})` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		return setDate(r, atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(\d{4})年(\d{1,2})月(\d{1,2})日` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		return setDate(r, atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(\d{1,2})月(\d{1,2})日` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		return setMonthDay(r, today, atoi(m[1]), atoi(m[2]))
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(?i)` + enDuePrefix + `\b(\d{1,2})/(\d{1,2})\b` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		return setMonthDay(r, today, atoi(m[1]), atoi(m[2]))
+	}},
+
+	// 期限日（相対的な指定）
+	{field: fieldDueDate, re: regexp.MustCompile(`(今日|本日|明後日|あさって|明日|あした)` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		days := map[string]int{"今日": 0, "本日": 0, "明日": 1, "あした": 1, "明後日": 2, "あさって": 2}[m[1]]
+		r.DueDate = today.AddDate(0, 0, days)
+		return true
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(?i)` + enDuePrefix + `\b(day after tomorrow|today|tonight|tomorrow)\b`), apply: func(m []string, r *Result, today time.Time) bool {
+		days := map[string]int{"today": 0, "tonight": 0, "tomorrow": 1, "day after tomorrow": 2}[strings.ToLower(m[1])]
+		r.DueDate = today.AddDate(0, 0, days)
+		return true
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(\d+)(日|週間)後` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		n := atoi(m[1])
+		if m[2] == "週間" {
+			n *= 7
+		}
+		r.DueDate = today.AddDate(0, 0, n)
+		return true
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(?i)\bin\s+(\d+)\s+(days?|weeks?)\b`), apply: func(m []string, r *Result, today time.Time) bool {
+		n := atoi(m[1])
+		if strings.HasPrefix(strings.ToLower(m[2]), "week") {
+			n *= 7
+		}
+		r.DueDate = today.AddDate(0, 0, n)
+		return true
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(来週の?|今週の?|次の)?([日月火水木金土])曜日?` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		r.DueDate = weekdayDate(today, weekdays[m[2]], weekWords[m[1]])
+		return true
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(?i)` + enDuePrefix + `\b(?:(next|this)\s+)?(sunday|monday|tuesday|wednesday|thursday|friday|saturday)\b`), apply: func(m []string, r *Result, today time.Time) bool {
+		r.DueDate = weekdayDate(today, weekdays[strings.ToLower(m[2])], strings.ToLower(m[1]))
+		return true
+	}},
+	{field: fieldDueDate, re: regexp.MustCompile(`(?i)` + enDuePrefix + `\bnext\s+week\b|来週` + jaDueSuffix), apply: func(m []string, r *Result, today time.Time) bool {
+		r.DueDate = today.AddDate(0, 0, 7)
+		return true
+	}},
+
+	// 見積時間
+	{field: fieldDuration, re: regexp.MustCompile(`(\d+(?:\.\d+)?)時間(?:(\d+)分)?|(\d+)分`), apply: func(m []string, r *Result, _ time.Time) bool {
+		return setDuration(r, m[1], m[2], m[3])
+	}},
+	{field: fieldDuration, re: regexp.MustCompile(`(?i)\b(?:(\d+(?:\.\d+)?)\s*(?:h|hrs?|hours?)(?:\s*(\d+)\s*(?:m|mins?|minutes?))?|(\d+)\s*(?:m|mins?|minutes?))\b`), apply: func(m []string, r *Result, _ time.Time) bool {
+		return setDuration(r, m[1], m[2], m[3])
+	}},
+}
+
+// Parse 自然文を解釈する。相対的な日付はnowの日付を基準にする
+//
+// 期限日は他の期限日の指定と同じく、その日の0時 (UTC) にする
+func Parse(input string, now time.Time) Result {
+	text := width.Fold.String(input)
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+
+	var r Result
+	for _, rule := range rules {
+		text = rule.re.ReplaceAllStringFunc(text, func(s string) string {
+			if rule.field.set(&r) {
+				return s
+			}
+			if !rule.apply(rule.re.FindStringSubmatch(s), &r, today) {
+				return s
+			}
+			return " "
+		})
+	}
+
+	r.Title = cleanTitle(text)
+	return r
+}
+
+// set 項目が既に決まっているかどうか
+func (f field) set(r *Result) bool {
+	switch f {
+	case fieldProject:
+		return r.Project != ""
+	case fieldPriority:
+		return r.Priority != 0
+	case fieldDueDate:
+		return !r.DueDate.IsZero()
+	case fieldDuration:
+		return r.EstimatedDuration != 0
+	default:
+		return false
+	}
+}
+
+func setPriority(m []string, r *Result, _ time.Time) bool {
+	p, ok := priorities[strings.ToLower(m[1])]
+	if !ok {
+		return false
+	}
+	r.Priority = p
+	return true
+}
+
+// setDate 年月日が正しい日付の場合だけ期限日にする
+func setDate(r *Result, year, month, day int) bool {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day {
+		return false
+	}
+	r.DueDate = t
+	return true
+}
+
+// setMonthDay 月日を期限日にする。今日より前になる場合は翌年とする
+func setMonthDay(r *Result, today time.Time, month, day int) bool {
+	year := today.Year()
+	if month < int(today.Month()) || (month == int(today.Month()) && day < today.Day()) {
+		year++
+	}
+	return setDate(r, year, month, day)
+}
+
+// weekdayDate 曜日の日付を求める。週は月曜から始まる
+//
+// whichが "next" の場合は翌週、"this" の場合は今週のその曜日、空の場合は今日以降で最も近いその曜日。
+// 今週のその曜日が既に過ぎている場合は、期限日が過去にならないよう翌週にする
+func weekdayDate(today time.Time, wd time.Weekday, which string) time.Time {
+	iso := func(w time.Weekday) int { return (int(w)+6)%7 + 1 }
+	monday := today.AddDate(0, 0, 1-iso(today.Weekday()))
+	switch which {
+	case "next":
+		return monday.AddDate(0, 0, 7+iso(wd)-1)
+	case "this":
+		d := monday.AddDate(0, 0, iso(wd)-1)
+		if d.Before(today) {
+			d = d.AddDate(0, 0, 7)
+		}
+		return d
+	default:
+		return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7)
+	}
+}
+
+// setDuration 時間と分、または分だけの見積時間を設定する
+func setDuration(r *Result, hours, minutes, onlyMinutes string) bool {
+	total := 0.0
+	if hours != "" {
+		h, err := strconv.ParseFloat(hours, 64)
+		if err != nil {
+			return false
+		}
+		total = h * 60
+		if minutes != "" {
+			total += float64(atoi(minutes))
+		}
+	} else {
+		total = float64(atoi(onlyMinutes))
+	}
+	if total <= 0 {
+		return false
+	}
+	r.EstimatedDuration = int(total + 0.5)
+	return true
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// cleanTitle 余分な空白と、前後に残った読点などを取り除く
+func cleanTitle(s string) string {
+	s = spaces.ReplaceAllString(s, " ")
+	return strings.Trim(s, " 、,。.・")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,112 @@
+package quickadd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	// 2026-10-17 は土曜日
+	saturday := time.Date(2026, 10, 17, 21, 30, 0, 0, time.Local)
+	// 2026-10-14 は水曜日
+	wednesday := time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input string
+		now   time.Time
+		want  Result
+	}{
+		// リクエストとパッケージのドキュメントの例
+		{"明日までにレポート提出 !高 90分", saturday, Result{Title: "レポート提出", Priority: 3, DueDate: date(2026, 10, 18), EstimatedDuration: 90}},
+		{"submit report tomorrow p3 1h30m #work", saturday, Result{Title: "submit report", Priority: 3, DueDate: date(2026, 10, 18), EstimatedDuration: 90, Tags: []string{"work"}}},
+
+		// 優先度
+		{"メール返信 !中", saturday, Result{Title: "メール返信", Priority: 2}},
+		{"優先度高 書類提出", saturday, Result{Title: "書類提出", Priority: 3}},
+		{"掃除 !low", saturday, Result{Title: "掃除", Priority: 1}},
+		{"!2 請求書", saturday, Result{Title: "請求書", Priority: 2}},
+		{"p1 refactor", saturday, Result{Title: "refactor", Priority: 1}},
+
+		// 相対的な期限日
+		{"今日 買い物", saturday, Result{Title: "買い物", DueDate: date(2026, 10, 17)}},
+		{"明後日 歯医者", saturday, Result{Title: "歯医者", DueDate: date(2026, 10, 19)}},
+		{"3日後までに 支払い", saturday, Result{Title: "支払い", DueDate: date(2026, 10, 20)}},
+		{"2週間後 レビュー", saturday, Result{Title: "レビュー", DueDate: date(2026, 10, 31)}},
+		{"call mom today", saturday, Result{Title: "call mom", DueDate: date(2026, 10, 17)}},
+		{"submit report by tomorrow", saturday, Result{Title: "submit report", DueDate: date(2026, 10, 18)}},
+		{"pay rent in 3 days", saturday, Result{Title: "pay rent", DueDate: date(2026, 10, 20)}},
+		{"review in 2 weeks", saturday, Result{Title: "review", DueDate: date(2026, 10, 31)}},
+		{"来週 企画", saturday, Result{Title: "企画", DueDate: date(2026, 10, 24)}},
+		{"plan next week", saturday, Result{Title: "plan", DueDate: date(2026, 10, 24)}},
+
+		// 曜日。土曜日には今週の金曜は過ぎているため翌週の金曜にする
+		{"金曜 ゴミ出し", saturday, Result{Title: "ゴミ出し", DueDate: date(2026, 10, 23)}},
+		{"今週金曜 レビュー", saturday, Result{Title: "レビュー", DueDate: date(2026, 10, 23)}},
+		{"this friday review", saturday, Result{Title: "review", DueDate: date(2026, 10, 23)}},
+		{"来週金曜 会議", saturday, Result{Title: "会議", DueDate: date(2026, 10, 23)}},
+		{"来週の金曜 会議", saturday, Result{Title: "会議", DueDate: date(2026, 10, 23)}},
+		{"next friday lunch", saturday, Result{Title: "lunch", DueDate: date(2026, 10, 23)}},
+		{"土曜日までに 洗車", saturday, Result{Title: "洗車", DueDate: date(2026, 10, 17)}},
+		{"金曜 ゴミ出し", wednesday, Result{Title: "ゴミ出し", DueDate: date(2026, 10, 16)}},
+		{"今週金曜 レビュー", wednesday, Result{Title: "レビュー", DueDate: date(2026, 10, 16)}},
+		{"今週の月曜 振り返り", wednesday, Result{Title: "振り返り", DueDate: date(2026, 10, 19)}},
+		{"this friday review", wednesday, Result{Title: "review", DueDate: date(2026, 10, 16)}},
+		{"来週金曜 会議", wednesday, Result{Title: "会議", DueDate: date(2026, 10, 23)}},
+		{"次の金曜 会議", wednesday, Result{Title: "会議", DueDate: date(2026, 10, 23)}},
+		{"meeting on friday", wednesday, Result{Title: "meeting", DueDate: date(2026, 10, 16)}},
+		{"next friday lunch", wednesday, Result{Title: "lunch", DueDate: date(2026, 10, 23)}},
+
+		// 日付の指定
+		{"10月20日の打ち合わせ", saturday, Result{Title: "打ち合わせ", DueDate: date(2026, 10, 20)}},
+		{"10/20 締め切り", saturday, Result{Title: "締め切り", DueDate: date(2026, 10, 20)}},
+		{"2026-10-20 提出", saturday, Result{Title: "提出", DueDate: date(2026, 10, 20)}},
+		{"提出 due:2026-10-20", saturday, Result{Title: "提出", DueDate: date(2026, 10, 20)}},
+		{"2026年11月3日までに 申請", saturday, Result{Title: "申請", DueDate: date(2026, 11, 3)}},
+		{"明日までの資料", saturday, Result{Title: "資料", DueDate: date(2026, 10, 18)}},
+		// 今日より前の月日は翌年
+		{"10月1日 健康診断", saturday, Result{Title: "健康診断", DueDate: date(2027, 10, 1)}},
+		// 存在しない日付はタイトルに残す
+		{"2026-02-30 メモ", saturday, Result{Title: "2026-02-30 メモ"}},
+
+		// 見積時間
+		{"会議 1時間", saturday, Result{Title: "会議", EstimatedDuration: 60}},
+		{"作業 1.5時間", saturday, Result{Title: "作業", EstimatedDuration: 90}},
+		{"作業 1時間30分", saturday, Result{Title: "作業", EstimatedDuration: 90}},
+		{"write docs 90min", saturday, Result{Title: "write docs", EstimatedDuration: 90}},
+		{"write docs 2 hours", saturday, Result{Title: "write docs", EstimatedDuration: 120}},
+		{"write docs 1h", saturday, Result{Title: "write docs", EstimatedDuration: 60}},
+
+		// プロジェクトとタグ
+		{"資料作成 +仕事 #急ぎ @電話", saturday, Result{Title: "資料作成", Project: "仕事", Tags: []string{"急ぎ", "電話"}}},
+
+		// 全角の英数字と記号
+		{"明日　レポート　！高　９０分", saturday, Result{Title: "レポート", Priority: 3, DueDate: date(2026, 10, 18), EstimatedDuration: 90}},
+
+		// 同じ項目の2つ目はタイトルに残す
+		{"明日 今日の作業 !高 !低", saturday, Result{Title: "今日の作業 !低", Priority: 3, DueDate: date(2026, 10, 18)}},
+	}
+	for _, tt := range tests {
+		got := Parse(tt.input, tt.now)
+		if !sameResult(got, tt.want) {
+			t.Errorf("Parse(%q, %s)\n got %+v\nwant %+v", tt.input, tt.now.Weekday(), got, tt.want)
+		}
+	}
+}
+
+func TestResultTask(t *testing.T) {
+	task := Parse("牛乳を買う #買い物", time.Now()).Task()
+	if task.Title != "牛乳を買う" || task.Priority != DefaultPriority || strings.Join(task.Contexts, ",") != "買い物" {
+		t.Errorf("Task = %+v", task)
+	}
+}
+
+func sameResult(a, b Result) bool {
+	return a.Title == b.Title && a.Priority == b.Priority && a.DueDate.Equal(b.DueDate) &&
+		a.EstimatedDuration == b.EstimatedDuration && a.Project == b.Project &&
+		strings.Join(a.Tags, ",") == strings.Join(b.Tags, ",")
+}
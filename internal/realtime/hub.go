@@ -0,0 +1,183 @@
+// Package realtime プロジェクトごとのボードに接続しているクライアントを管理し、
+// メッセージと閲覧状況（プレゼンス）を配信する
+//
+// 通信方式には依存しない。接続ごとにJoinでClientを作り、Messagesから受け取った内容を送信する
+package realtime
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// clientBuffer クライアントごとに溜めておける未送信のメッセージ数。
+// 溢れた場合は受信が追いつかないクライアントとして切断する
+const clientBuffer = 64
+
+// AllProjects すべてのプロジェクトのメッセージを受け取るルーム
+const AllProjects = ""
+
+// Client ボードに接続している1つの接続
+type Client struct {
+	id       int64
+	project  string
+	presence model.Presence
+	send     chan []byte
+	closed   bool
+}
+
+// ID 接続のID
+func (c *Client) ID() int64 {
+	return c.id
+}
+
+// Project 接続しているプロジェクト
+func (c *Client) Project() string {
+	return c.project
+}
+
+// Messages 送信するメッセージ。Leaveするか、受信が追いつかずに切断されると閉じる
+func (c *Client) Messages() <-chan []byte {
+	return c.send
+}
+
+// Hub プロジェクトごとのルームと接続を管理する
+type Hub struct {
+	mu     sync.Mutex
+	nextID int64
+	rooms  map[string]map[*Client]struct{}
+}
+
+// NewHub 空のHubを作成
+func NewHub() *Hub {
+	return &Hub{rooms: map[string]map[*Client]struct{}{}}
+}
+
+// Join プロジェクトのルームに接続を追加する。projectがAllProjectsの場合はすべてのプロジェクトのメッセージを受け取る
+func (h *Hub) Join(project, user string) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	c := &Client{
+		id:      h.nextID,
+		project: project,
+		presence: model.Presence{
+			ConnectionID: h.nextID,
+			User:         user,
+			State:        model.PresenceIdle,
+			Since:        time.Now(),
+		},
+		send: make(chan []byte, clientBuffer),
+	}
+	room := h.rooms[project]
+	if room == nil {
+		room = map[*Client]struct{}{}
+		h.rooms[project] = room
+	}
+	room[c] = struct{}{}
+	return c
+}
+
+// Leave 接続をルームから外す
+func (h *Hub) Leave(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(c)
+}
+
+// remove 接続をルームから外し、送信用のチャネルを閉じる。h.muを取得した状態で呼ぶ
+func (h *Hub) remove(c *Client) {
+	room := h.rooms[c.project]
+	if _, ok := room[c]; !ok {
+		return
+	}
+	delete(room, c)
+	if len(room) == 0 {
+		delete(h.rooms, c.project)
+	}
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// SetPresence 接続の閲覧状況を更新する。状態が変わらない場合はfalseを返す
+func (h *Hub) SetPresence(c *Client, state model.PresenceState, taskID int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if state == model.PresenceIdle {
+		taskID = 0
+	}
+	if c.presence.State == state && c.presence.TaskID == taskID {
+		return false
+	}
+	c.presence.State = state
+	c.presence.TaskID = taskID
+	c.presence.Since = time.Now()
+	return true
+}
+
+// Presence ルームに接続しているユーザーの閲覧状況を接続順に返す
+func (h *Hub) Presence(project string) []model.Presence {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	presence := []model.Presence{}
+	for c := range h.rooms[project] {
+		presence = append(presence, c.presence)
+	}
+	sort.Slice(presence, func(i, j int) bool { return presence[i].ConnectionID < presence[j].ConnectionID })
+	return presence
+}
+
+// Empty 接続が1つもないかどうか
+func (h *Hub) Empty() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.rooms) == 0
+}
+
+// Send 1つの接続にメッセージを送る
+func (h *Hub) Send(c *Client, v interface{}) error {
+	msg, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deliver(c, msg)
+	return nil
+}
+
+// Broadcast ルームのすべての接続にメッセージを送る
+func (h *Hub) Broadcast(project string, v interface{}) error {
+	msg, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.rooms[project] {
+		h.deliver(c, msg)
+	}
+	return nil
+}
+
+// deliver 送信待ちに追加する。溢れた場合は接続を外す。h.muを取得した状態で呼ぶ
+func (h *Hub) deliver(c *Client, msg []byte) {
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- msg:
+	default:
+		h.remove(c)
+	}
+}
@@ -0,0 +1,124 @@
+package realtime
+
+import (
+	"testing"
+
+	"task-recommender/internal/model"
+)
+
+// drain チャネルが閉じるまで受け取ったメッセージを返す
+func drain(c *Client) []string {
+	var msgs []string
+	for msg := range c.Messages() {
+		msgs = append(msgs, string(msg))
+	}
+	return msgs
+}
+
+func TestHubJoinLeave(t *testing.T) {
+	h := NewHub()
+	if !h.Empty() {
+		t.Fatal("新しいHubが空ではありません")
+	}
+
+	a := h.Join("home", "alice")
+	b := h.Join("home", "bob")
+	other := h.Join("work", "carol")
+	if a.ID() == b.ID() || a.Project() != "home" || other.Project() != "work" {
+		t.Errorf("ids = %d, %d, projects = %s, %s", a.ID(), b.ID(), a.Project(), other.Project())
+	}
+
+	if err := h.Broadcast("home", map[string]string{"type": "ping"}); err != nil {
+		t.Fatal(err)
+	}
+	h.Leave(a)
+	h.Leave(a) // 2回目は何もしない
+	if got := drain(a); len(got) != 1 || got[0] != `{"type":"ping"}` {
+		t.Errorf("a のメッセージ = %q", got)
+	}
+	if len(b.Messages()) != 1 || len(other.Messages()) != 0 {
+		t.Errorf("未送信のメッセージ数 = %d, %d, want 1, 0", len(b.Messages()), len(other.Messages()))
+	}
+
+	// 外した後の送信は無視する
+	if err := h.Send(a, "ignored"); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Leave(b)
+	h.Leave(other)
+	if !h.Empty() {
+		t.Error("すべて外した後も Empty ではありません")
+	}
+}
+
+func TestHubPresence(t *testing.T) {
+	h := NewHub()
+	a := h.Join("home", "alice")
+	b := h.Join("home", "bob")
+	h.Join("work", "carol")
+
+	if !h.SetPresence(b, model.PresenceEditing, 12) {
+		t.Error("状態の変更が false でした")
+	}
+	if h.SetPresence(b, model.PresenceEditing, 12) {
+		t.Error("同じ状態への変更が true でした")
+	}
+	// idle にするとタスクの指定は消える
+	if h.SetPresence(a, model.PresenceIdle, 7) {
+		t.Error("idle のままの変更が true でした")
+	}
+
+	got := h.Presence("home")
+	if len(got) != 2 {
+		t.Fatalf("Presence = %+v, want 2", got)
+	}
+	if got[0].User != "alice" || got[0].State != model.PresenceIdle || got[0].TaskID != 0 {
+		t.Errorf("Presence[0] = %+v", got[0])
+	}
+	if got[1].User != "bob" || got[1].State != model.PresenceEditing || got[1].TaskID != 12 || got[1].ConnectionID != b.ID() {
+		t.Errorf("Presence[1] = %+v", got[1])
+	}
+
+	h.Leave(a)
+	if got := h.Presence("home"); len(got) != 1 || got[0].User != "bob" {
+		t.Errorf("Leave 後の Presence = %+v", got)
+	}
+	if got := h.Presence("none"); got == nil || len(got) != 0 {
+		t.Errorf("接続のないルームの Presence = %#v, want empty slice", got)
+	}
+}
+
+func TestHubDisconnectsSlowConsumer(t *testing.T) {
+	h := NewHub()
+	slow := h.Join("home", "slow")
+	fast := h.Join("home", "fast")
+
+	for i := 0; i < clientBuffer; i++ {
+		if err := h.Broadcast("home", i); err != nil {
+			t.Fatal(err)
+		}
+		<-fast.Messages()
+	}
+	// slow の送信待ちは満杯。次のメッセージで切断する
+	if err := h.Broadcast("home", "overflow"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := drain(slow); len(got) != clientBuffer {
+		t.Errorf("slow が受け取ったメッセージ数 = %d, want %d", len(got), clientBuffer)
+	}
+	if msg := <-fast.Messages(); string(msg) != `"overflow"` {
+		t.Errorf("fast のメッセージ = %s", msg)
+	}
+	if got := h.Presence("home"); len(got) != 1 || got[0].User != "fast" {
+		t.Errorf("Presence = %+v, want only fast", got)
+	}
+
+	// 切断済みの接続を外しても問題ない
+	h.Leave(slow)
+	h.Leave(fast)
+	if !h.Empty() {
+		t.Error("Empty ではありません")
+	}
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"task-recommender/internal/model"
+	pb "task-recommender/internal/rpc/taskrecommender/v1"
+)
+
+// proto/taskrecommender/v1/task.proto のメッセージとモデルの変換
+
+// taskFromCreateRequest CreateTaskRequest から作成するタスクを組み立てる
+func taskFromCreateRequest(req *pb.CreateTaskRequest) model.Task {
+	return model.Task{
+		Title:             req.GetTitle(),
+		Description:       req.GetDescription(),
+		Priority:          int(req.GetPriority()),
+		DueDate:           fromTimestamp(req.GetDueDate()),
+		EstimatedDuration: int(req.GetEstimatedDuration()),
+		Project:           req.GetProject(),
+		Contexts:          req.GetContexts(),
+	}
+}
+
+// filterFromListRequest ListTasksRequest から絞り込みの条件を組み立てる
+func filterFromListRequest(req *pb.ListTasksRequest) model.TaskFilter {
+	return model.TaskFilter{
+		Status:  model.Status(req.GetStatus()),
+		Project: req.GetProject(),
+		Context: req.GetContext(),
+	}
+}
+
+func toTask(t model.Task) *pb.Task {
+	return &pb.Task{
+		Id:                int64(t.ID),
+		ExternalId:        t.ExternalID,
+		Title:             t.Title,
+		Description:       t.Description,
+		Status:            string(t.Status),
+		Priority:          int32(t.Priority),
+		DueDate:           toTimestamp(t.DueDate),
+		EstimatedDuration: int32(t.EstimatedDuration),
+		TrackedDuration:   int32(t.TrackedDuration),
+		Project:           t.Project,
+		Contexts:          t.Contexts,
+		CreatedAt:         toTimestamp(t.CreatedAt),
+		CompletedAt:       toTimestamp(t.CompletedAt),
+		Version:           int32(t.Version),
+		StatusChangedAt:   toTimestamp(t.StatusChangedAt),
+	}
+}
+
+func toRecommendResponse(recs []model.Recommendation) *pb.RecommendResponse {
+	resp := &pb.RecommendResponse{}
+	for _, r := range recs {
+		resp.Recommendations = append(resp.Recommendations, &pb.Recommendation{
+			Task:    toTask(r.Task),
+			Score:   r.Score,
+			Reasons: r.Reasons,
+		})
+	}
+	return resp
+}
+
+// toTaskEvent task が nil の場合はタスクを含めない
+func toTaskEvent(e model.TaskEvent, task *model.Task) *pb.TaskEvent {
+	ev := &pb.TaskEvent{
+		Id:         e.ID,
+		Type:       string(e.Type),
+		TaskId:     int64(e.TaskID),
+		Project:    e.Project,
+		Field:      e.Field,
+		OldValue:   e.OldValue,
+		NewValue:   e.NewValue,
+		Actor:      e.Actor,
+		OccurredAt: toTimestamp(e.OccurredAt),
+	}
+	if task != nil {
+		ev.Task = toTask(*task)
+	}
+	return ev
+}
+
+// toTimestamp ゼロ値の日時は nil にする
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp nil はゼロ値の日時にする
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,239 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"task-recommender/internal/controller"
+	"task-recommender/internal/model"
+	"task-recommender/internal/quickadd"
+	pb "task-recommender/internal/rpc/taskrecommender/v1"
+	"task-recommender/internal/service"
+)
+
+const (
+	// maxMessageBytes 受け付けるリクエストのメッセージの最大サイズ
+	maxMessageBytes = 4 << 20
+	// eventPollInterval WatchTasks で新しいイベントを確認する間隔
+	eventPollInterval = time.Second
+	// eventBatchSize WatchTasks で1回に読み込むイベントの最大件数
+	eventBatchSize = 100
+)
+
+// Server TaskController の操作を proto/taskrecommender/v1/task.proto の TaskService として公開する
+type Server struct {
+	pb.UnimplementedTaskServiceServer
+	controller *controller.TaskController
+}
+
+// NewServer Server を作成する
+func NewServer(controller *controller.TaskController) *Server {
+	return &Server{controller: controller}
+}
+
+// NewGRPCServer srv を登録した gRPC のサーバーを作成する
+func NewGRPCServer(srv *Server) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageBytes),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			resp, err := handler(ctx, req)
+			return resp, statusFromError(err)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return statusFromError(handler(srv, ss))
+		}),
+	)
+	pb.RegisterTaskServiceServer(s, srv)
+	return s
+}
+
+// ListenAndServe 平文の HTTP/2 で gRPC のリクエストを受け付ける
+func ListenAndServe(addr string, srv *Server) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return NewGRPCServer(srv).Serve(lis)
+}
+
+// controllerFor メタデータ x-user の操作ユーザーを設定したコントローラーを返す
+func (s *Server) controllerFor(ctx context.Context) *controller.TaskController {
+	var actor string
+	if users := metadata.ValueFromIncomingContext(ctx, "x-user"); len(users) > 0 {
+		actor = strings.TrimSpace(users[0])
+	}
+	return s.controller.WithActor(actor)
+}
+
+func (s *Server) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.Task, error) {
+	ctrl := s.controllerFor(ctx)
+	task := taskFromCreateRequest(req)
+	if task.Priority == 0 {
+		task.Priority = quickadd.DefaultPriority
+	}
+
+	id, err := ctrl.CreateTask(task)
+	if err != nil {
+		return nil, err
+	}
+	created, err := ctrl.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return toTask(created), nil
+}
+
+func (s *Server) ListTasks(req *pb.ListTasksRequest, stream grpc.ServerStreamingServer[pb.Task]) error {
+	filter := filterFromListRequest(req)
+	if filter.Status != "" && !filter.Status.Valid() {
+		return status.Errorf(codes.InvalidArgument, "不正な状態です: %s", filter.Status)
+	}
+
+	tasks, err := s.controllerFor(stream.Context()).ListTasks()
+	if err != nil {
+		return err
+	}
+	for _, t := range filter.Filter(tasks.([]model.Task)) {
+		if err := stream.Send(toTask(t)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) CompleteTask(ctx context.Context, req *pb.CompleteTaskRequest) (*pb.Task, error) {
+	id := int(req.GetId())
+	if id <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "タスクIDが不正です: %d", req.GetId())
+	}
+	ctrl := s.controllerFor(ctx)
+	if req.GetVersion() != 0 {
+		ctrl = ctrl.IfVersion(int(req.GetVersion()))
+	}
+
+	if err := ctrl.CompleteTask(id); err != nil {
+		return nil, err
+	}
+	task, err := ctrl.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return toTask(task), nil
+}
+
+func (s *Server) Recommend(ctx context.Context, req *pb.RecommendRequest) (*pb.RecommendResponse, error) {
+	recs, err := s.controllerFor(ctx).RecommendTasks(int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return toRecommendResponse(recs), nil
+}
+
+// WatchTasks 新しいイベントを定期的に確認し、呼び出しが終わるまで送り続ける
+func (s *Server) WatchTasks(req *pb.WatchTasksRequest, stream grpc.ServerStreamingServer[pb.TaskEvent]) error {
+	types := map[model.EventType]bool{}
+	for _, name := range req.GetTypes() {
+		t := model.EventType(name)
+		if !t.Valid() {
+			return status.Errorf(codes.InvalidArgument, "不明なイベントの種類です: %s", t)
+		}
+		types[t] = true
+	}
+	project := req.GetProject()
+
+	ctx := stream.Context()
+	ctrl := s.controllerFor(ctx)
+	lastID := req.GetAfterEventId()
+	if req.AfterEventId == nil {
+		var err error
+		if lastID, err = ctrl.LatestEventID(); err != nil {
+			return err
+		}
+	}
+
+	poll := time.NewTicker(eventPollInterval)
+	defer poll.Stop()
+	for {
+		batch, err := ctrl.ListEvents(lastID, eventBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var events []model.TaskEvent
+		var ids []int
+		for _, e := range batch {
+			lastID = e.ID
+			if len(types) > 0 && !types[e.Type] || project != "" && e.Project != project {
+				continue
+			}
+			events = append(events, e)
+			if e.Type != model.EventTaskDeleted {
+				ids = append(ids, e.TaskID)
+			}
+		}
+
+		tasks := map[int]*model.Task{}
+		if len(ids) > 0 {
+			found, err := ctrl.GetTasks(ids)
+			if err != nil {
+				return err
+			}
+			for i := range found {
+				tasks[found[i].ID] = &found[i]
+			}
+		}
+		for _, e := range events {
+			var task *model.Task
+			if e.Type != model.EventTaskDeleted {
+				task = tasks[e.TaskID]
+			}
+			if err := stream.Send(toTaskEvent(e, task)); err != nil {
+				return err
+			}
+		}
+
+		// 読み込みきれなかったイベントがある場合は待たずに続きを読み込む
+		if len(batch) == eventBatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-poll.C:
+		}
+	}
+}
+
+// statusFromError エラーをステータスコード付きのエラーに変換する
+func statusFromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "期限までに処理が終わりませんでした")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "呼び出しがキャンセルされました")
+	case errors.Is(err, service.ErrInvalidTask):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrTaskNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
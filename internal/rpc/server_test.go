@@ -0,0 +1,262 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"task-recommender/internal/controller"
+	pb "task-recommender/internal/rpc/taskrecommender/v1"
+	"task-recommender/internal/service"
+	"task-recommender/pkg/db"
+)
+
+// newTestClient svc を公開するサーバーをメモリ上で起動し、生成したクライアントで接続する
+func newTestClient(t *testing.T, svc *service.TaskService) (pb.TaskServiceClient, *grpc.ClientConn) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(NewServer(controller.NewTaskController(svc)))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewTaskServiceClient(conn), conn
+}
+
+// newTestDB テスト用のデータベースに接続し、テーブルを作り直す。TEST_DATABASE_URL が未設定の場合はスキップする
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL が未設定のためスキップします")
+	}
+	database, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := db.ResetDatabase(database); err != nil {
+		t.Fatal(err)
+	}
+	return database
+}
+
+// wantStatus err のステータスコードとメッセージを確かめる。メッセージはトレーラーの grpc-message で送られる
+func wantStatus(t *testing.T, err error, code codes.Code, message string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("err = %v, want a status error", err)
+	}
+	if st.Code() != code || !strings.Contains(st.Message(), message) {
+		t.Errorf("status = %v %q, want %v containing %q", st.Code(), st.Message(), code, message)
+	}
+}
+
+func TestServerInvalidArguments(t *testing.T) {
+	// データベースに問い合わせる前に返すエラーだけを確かめる
+	client, conn := newTestClient(t, service.NewTaskService(nil))
+	ctx := context.Background()
+
+	t.Run("CreateTask", func(t *testing.T) {
+		_, err := client.CreateTask(ctx, &pb.CreateTaskRequest{Title: ""})
+		wantStatus(t, err, codes.InvalidArgument, "タイトル")
+	})
+	t.Run("CompleteTask", func(t *testing.T) {
+		var trailer metadata.MD
+		_, err := client.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: 0}, grpc.Trailer(&trailer))
+		wantStatus(t, err, codes.InvalidArgument, "タスクIDが不正です: 0")
+		if trailer == nil {
+			t.Error("trailer was not received")
+		}
+	})
+	t.Run("ListTasks", func(t *testing.T) {
+		stream, err := client.ListTasks(ctx, &pb.ListTasksRequest{Status: "unknown"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = stream.Recv()
+		wantStatus(t, err, codes.InvalidArgument, "不正な状態です: unknown")
+		if stream.Trailer() == nil {
+			t.Error("trailer was not received")
+		}
+	})
+	t.Run("WatchTasks", func(t *testing.T) {
+		stream, err := client.WatchTasks(ctx, &pb.WatchTasksRequest{Types: []string{"task.created", "task.moved"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = stream.Recv()
+		wantStatus(t, err, codes.InvalidArgument, "不明なイベントの種類です: task.moved")
+	})
+	t.Run("message too large", func(t *testing.T) {
+		_, err := client.CreateTask(ctx, &pb.CreateTaskRequest{Title: strings.Repeat("x", maxMessageBytes+1)})
+		wantStatus(t, err, codes.ResourceExhausted, "")
+	})
+	t.Run("unknown method", func(t *testing.T) {
+		err := conn.Invoke(ctx, "/taskrecommender.v1.TaskService/DeleteTask", &pb.CompleteTaskRequest{Id: 1}, &pb.Task{})
+		wantStatus(t, err, codes.Unimplemented, "DeleteTask")
+	})
+}
+
+func TestStatusFromError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{service.ErrInvalidTask, codes.InvalidArgument},
+		{service.ErrTaskNotFound, codes.NotFound},
+		{service.ErrInvalidTransition, codes.FailedPrecondition},
+		{service.ErrVersionConflict, codes.Aborted},
+		{status.Error(codes.PermissionDenied, "x"), codes.PermissionDenied},
+		{errors.New("x"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := status.Code(statusFromError(tt.err)); got != tt.code {
+			t.Errorf("statusFromError(%v) = %v, want %v", tt.err, got, tt.code)
+		}
+	}
+	if statusFromError(nil) != nil {
+		t.Error("statusFromError(nil) != nil")
+	}
+}
+
+func TestServerWithDatabase(t *testing.T) {
+	client, _ := newTestClient(t, service.NewTaskService(newTestDB(t)))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user", "alice")
+	due := timestamppb.New(time.Now().Add(48 * time.Hour).Truncate(time.Second))
+
+	// 作成前のイベントから監視する
+	watchCtx, cancelWatch := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelWatch()
+	watch, err := client.WatchTasks(watchCtx, &pb.WatchTasksRequest{AfterEventId: new(int64), Types: []string{"task.created", "task.completed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	low, err := client.CreateTask(ctx, &pb.CreateTaskRequest{Title: "低い", DueDate: due, Contexts: []string{"home"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if low.GetPriority() != 1 || low.GetId() == 0 || !low.GetDueDate().AsTime().Equal(due.AsTime()) || low.GetCompletedAt() != nil {
+		t.Errorf("CreateTask = %v", low)
+	}
+	high, err := client.CreateTask(ctx, &pb.CreateTaskRequest{Title: "高い", Priority: 3, DueDate: due})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ListTasks streams every task", func(t *testing.T) {
+		stream, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for {
+			task, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			titles = append(titles, task.GetTitle())
+		}
+		if strings.Join(titles, ",") != "高い,低い" {
+			t.Errorf("ListTasks = %v", titles)
+		}
+
+		stream, err = client.ListTasks(ctx, &pb.ListTasksRequest{Context: "home"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task, err := stream.Recv(); err != nil || task.GetId() != low.GetId() {
+			t.Fatalf("filtered ListTasks = %v, %v", task, err)
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Errorf("second Recv = %v, want io.EOF", err)
+		}
+	})
+
+	t.Run("CompleteTask", func(t *testing.T) {
+		_, err := client.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: high.GetId(), Version: high.GetVersion() + 1})
+		wantStatus(t, err, codes.Aborted, "")
+
+		done, err := client.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: high.GetId(), Version: high.GetVersion()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done.GetStatus() != "done" || done.GetCompletedAt() == nil {
+			t.Errorf("CompleteTask = %v", done)
+		}
+
+		_, err = client.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: high.GetId() + 1000})
+		wantStatus(t, err, codes.NotFound, "")
+	})
+
+	t.Run("Recommend", func(t *testing.T) {
+		resp, err := client.Recommend(ctx, &pb.RecommendRequest{Limit: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var found bool
+		for _, r := range resp.GetRecommendations() {
+			if r.GetTask().GetId() == high.GetId() {
+				t.Errorf("completed task was recommended: %v", r)
+			}
+			found = found || r.GetTask().GetId() == low.GetId()
+		}
+		if !found {
+			t.Errorf("Recommend = %v, want task %d", resp, low.GetId())
+		}
+	})
+
+	t.Run("WatchTasks", func(t *testing.T) {
+		want := []struct {
+			typ  string
+			task int64
+		}{{"task.created", low.GetId()}, {"task.created", high.GetId()}, {"task.completed", high.GetId()}}
+		for i, w := range want {
+			ev, err := watch.Recv()
+			if err != nil {
+				t.Fatalf("event %d: %v", i, err)
+			}
+			if ev.GetType() != w.typ || ev.GetTaskId() != w.task || ev.GetTask().GetId() != w.task || ev.GetActor() != "alice" {
+				t.Errorf("event %d = %v, want %s of task %d by alice", i, ev, w.typ, w.task)
+			}
+		}
+
+		// 呼び出しを終えるとストリームはキャンセルのステータスで終わる
+		cancelWatch()
+		for {
+			if _, err := watch.Recv(); err != nil {
+				wantStatus(t, err, codes.Canceled, "")
+				break
+			}
+		}
+	})
+}
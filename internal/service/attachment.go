@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"task-recommender/internal/model"
+	"task-recommender/pkg/storage"
+)
+
+// DefaultAttachmentMaxBytes 添付ファイルの最大サイズの既定値 (10MiB)
+const DefaultAttachmentMaxBytes = 10 << 20
+
+// WithBlobStore 添付ファイルの保存先と最大サイズを設定したサービスを返す
+func (s *TaskService) WithBlobStore(store storage.BlobStore, maxBytes int64) *TaskService {
+	if maxBytes <= 0 {
+		maxBytes = DefaultAttachmentMaxBytes
+	}
+	c := *s
+	c.blobs = store
+	c.attachmentMaxBytes = maxBytes
+	return &c
+}
+
+// AddAttachment タスクにファイルを添付する。Content-Typeはファイルの内容から判定する
+func (s *TaskService) AddAttachment(taskID int, filename string, r io.Reader) (model.Attachment, error) {
+	if s.blobs == nil {
+		return model.Attachment{}, ErrBlobStoreNotConfigured
+	}
+	if err := s.ensureTaskExists(taskID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	// 上限を1バイト超えて読めた場合はサイズ超過
+	data, err := io.ReadAll(io.LimitReader(r, s.attachmentMaxBytes+1))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if int64(len(data)) > s.attachmentMaxBytes {
+		return model.Attachment{}, fmt.Errorf("%w: 上限は%dバイトです", ErrAttachmentTooLarge, s.attachmentMaxBytes)
+	}
+
+	key, err := attachmentKey(taskID)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	a := model.Attachment{
+		TaskID:      taskID,
+		Filename:    filepath.Base(filename),
+		ContentType: detectContentType(filename, data),
+		Size:        int64(len(data)),
+		UploadedBy:  s.actor,
+		CreatedAt:   time.Now(),
+		StorageKey:  key,
+	}
+
+	if err := s.blobs.Put(a.StorageKey, bytes.NewReader(data), a.Size, a.ContentType); err != nil {
+		return model.Attachment{}, err
+	}
+
+	err = s.db.QueryRow(
+		`INSERT INTO task_attachments (task_id, filename, content_type, size, storage_key, uploaded_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id`,
+		a.TaskID, a.Filename, a.ContentType, a.Size, a.StorageKey, a.UploadedBy, a.CreatedAt,
+	).Scan(&a.ID)
+	if err != nil {
+		// メタデータを保存できなかった場合は孤立したデータを残さない
+		s.blobs.Delete(a.StorageKey)
+		return model.Attachment{}, err
+	}
+	return a, nil
+}
+
+// ListAttachments タスクの添付ファイル一覧を取得する
+func (s *TaskService) ListAttachments(taskID int) ([]model.Attachment, error) {
+	rows, err := s.db.Query(`
+        SELECT id, task_id, filename, content_type, size, storage_key, uploaded_by, created_at
+        FROM task_attachments
+        WHERE task_id = $1
+        ORDER BY created_at ASC, id ASC
+    `, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []model.Attachment
+	for rows.Next() {
+		var a model.Attachment
+		err := rows.Scan(&a.ID, &a.TaskID, &a.Filename, &a.ContentType, &a.Size, &a.StorageKey, &a.UploadedBy, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// OpenAttachment 添付ファイルのメタデータと内容を取得する。呼び出し側で内容をCloseすること
+func (s *TaskService) OpenAttachment(taskID, attachmentID int) (model.Attachment, io.ReadCloser, error) {
+	if s.blobs == nil {
+		return model.Attachment{}, nil, ErrBlobStoreNotConfigured
+	}
+
+	a, err := s.getAttachment(taskID, attachmentID)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	rc, err := s.blobs.Get(a.StorageKey)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return model.Attachment{}, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+	return a, rc, nil
+}
+
+// DeleteAttachment 添付ファイルを削除する
+func (s *TaskService) DeleteAttachment(taskID, attachmentID int) error {
+	if s.blobs == nil {
+		return ErrBlobStoreNotConfigured
+	}
+
+	a, err := s.getAttachment(taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec("DELETE FROM task_attachments WHERE id = $1", a.ID); err != nil {
+		return err
+	}
+	return s.blobs.Delete(a.StorageKey)
+}
+
+func (s *TaskService) getAttachment(taskID, attachmentID int) (model.Attachment, error) {
+	var a model.Attachment
+	err := s.db.QueryRow(`
+        SELECT id, task_id, filename, content_type, size, storage_key, uploaded_by, created_at
+        FROM task_attachments
+        WHERE id = $1 AND task_id = $2
+    `, attachmentID, taskID).Scan(
+		&a.ID, &a.TaskID, &a.Filename, &a.ContentType, &a.Size, &a.StorageKey, &a.UploadedBy, &a.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Attachment{}, ErrAttachmentNotFound
+	}
+	return a, err
+}
+
+// attachmentKey 添付ファイルのストレージ上のキーを生成する
+func attachmentKey(taskID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// detectContentType 内容からContent-Typeを判定し、判定できない場合は拡張子から推定する
+func detectContentType(filename string, data []byte) string {
+	sniffed := http.DetectContentType(data)
+	if sniffed != "application/octet-stream" {
+		return sniffed
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(filename)); byExt != "" {
+		return byExt
+	}
+	return sniffed
+}
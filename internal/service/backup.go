@@ -0,0 +1,318 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+	"task-recommender/pkg/storage"
+)
+
+// BackupVersion バックアップの形式のバージョン。形式を変えたら上げ、古い形式の復元も受け付ける
+const BackupVersion = 1
+
+// backupTables バックアップするテーブル。復元時に外部キーの参照先から作成できる順に並べる
+var backupTables = []string{
+	"tasks",
+	"time_entries",
+	"task_status_transitions",
+	"task_history",
+	"task_comments",
+	"task_attachments",
+	"checklist_items",
+	"webhooks",
+	"webhook_deliveries",
+	"reminder_log",
+	"digest_log",
+}
+
+// backupArchive バックアップファイルの内容。gzipで圧縮したJSONとして保存する
+type backupArchive struct {
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	Tables    []backupTable `json:"tables"`
+	Blobs     []backupBlob  `json:"blobs,omitempty"`
+}
+
+// backupTable テーブルの全行。値は列の順に並べる
+type backupTable struct {
+	Name    string          `json:"name"`
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// backupBlob 添付ファイルの内容
+type backupBlob struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// Backup すべてのテーブルと添付ファイルの内容をwに書き出す
+//
+// 1つの読み取り専用トランザクションで読み出すため、テーブル間の整合性が保たれる。
+// withBlobsがfalse、または保存先が設定されていない場合は添付ファイルの内容を含めない
+func (s *TaskService) Backup(w io.Writer, withBlobs bool) (model.BackupSummary, error) {
+	archive := backupArchive{Version: BackupVersion, CreatedAt: time.Now()}
+	summary := model.BackupSummary{Version: archive.Version, CreatedAt: archive.CreatedAt}
+
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return summary, err
+	}
+	defer tx.Rollback()
+
+	for _, name := range backupTables {
+		table, err := dumpTable(tx, name)
+		if err != nil {
+			return summary, fmt.Errorf("%s: %w", name, err)
+		}
+		archive.Tables = append(archive.Tables, table)
+		summary.Tables = append(summary.Tables, model.BackupTableSummary{Name: name, Rows: len(table.Rows)})
+	}
+
+	if withBlobs && s.blobs != nil {
+		rows, err := tx.Query("SELECT storage_key, content_type FROM task_attachments ORDER BY id")
+		if err != nil {
+			return summary, err
+		}
+		var blobs []backupBlob
+		for rows.Next() {
+			var b backupBlob
+			if err := rows.Scan(&b.Key, &b.ContentType); err != nil {
+				rows.Close()
+				return summary, err
+			}
+			blobs = append(blobs, b)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return summary, err
+		}
+
+		for _, b := range blobs {
+			b.Data, err = s.readBlob(b.Key)
+			if errors.Is(err, storage.ErrBlobNotFound) {
+				summary.MissingBlobs = append(summary.MissingBlobs, b.Key)
+				continue
+			}
+			if err != nil {
+				return summary, err
+			}
+			archive.Blobs = append(archive.Blobs, b)
+		}
+		summary.Blobs = len(archive.Blobs)
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(archive); err != nil {
+		return summary, err
+	}
+	return summary, gz.Close()
+}
+
+// Restore Backupで書き出した内容を、IDや日時を保ったまま復元する
+//
+// 復元先のいずれかのテーブルに行がある場合は ErrRestoreTargetNotEmpty を返す。forceの場合は既存の行をすべて消してから復元する。
+// 添付ファイルの内容は現在の保存先に書き込むため、バックアップ時と異なる保存先にも復元できる
+func (s *TaskService) Restore(r io.Reader, force bool) (model.BackupSummary, error) {
+	var summary model.BackupSummary
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return summary, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	defer gz.Close()
+
+	var archive backupArchive
+	dec := json.NewDecoder(gz)
+	// BIGSERIALのIDを丸めないよう数値は文字列のまま扱う
+	dec.UseNumber()
+	if err := dec.Decode(&archive); err != nil {
+		return summary, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if archive.Version < 1 || archive.Version > BackupVersion {
+		return summary, fmt.Errorf("%w: バージョン %d", ErrUnsupportedBackup, archive.Version)
+	}
+	summary.Version, summary.CreatedAt = archive.Version, archive.CreatedAt
+
+	if len(archive.Blobs) > 0 && s.blobs == nil {
+		return summary, ErrBlobStoreNotConfigured
+	}
+
+	tables := make(map[string]backupTable, len(archive.Tables))
+	for _, t := range archive.Tables {
+		if !knownBackupTable(t.Name) {
+			return summary, fmt.Errorf("%w: 不明なテーブル %s", ErrInvalidBackup, t.Name)
+		}
+		tables[t.Name] = t
+	}
+
+	err = s.withTx(func(tx *sql.Tx) error {
+		// 確認してから消すまでの間に書き込まれないよう、先にロックする
+		if _, err := tx.Exec("LOCK TABLE " + strings.Join(backupTables, ", ") + " IN ACCESS EXCLUSIVE MODE"); err != nil {
+			return err
+		}
+		if !force {
+			nonEmpty, err := nonEmptyTables(tx)
+			if err != nil {
+				return err
+			}
+			if len(nonEmpty) > 0 {
+				return fmt.Errorf("%w (%s)", ErrRestoreTargetNotEmpty, strings.Join(nonEmpty, ", "))
+			}
+		}
+		// TRUNCATEは行単位のトリガーを起動しないため、追記のみの変更履歴も消せる
+		if _, err := tx.Exec("TRUNCATE " + strings.Join(backupTables, ", ") + " RESTART IDENTITY"); err != nil {
+			return err
+		}
+
+		for _, name := range backupTables {
+			table := tables[name]
+			if err := restoreTable(tx, name, table); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			summary.Tables = append(summary.Tables, model.BackupTableSummary{Name: name, Rows: len(table.Rows)})
+		}
+
+		// 失敗したらトランザクションごと取り消せるよう、コミット前に書き込む
+		for _, b := range archive.Blobs {
+			if err := s.blobs.Put(b.Key, bytes.NewReader(b.Data), int64(len(b.Data)), b.ContentType); err != nil {
+				return err
+			}
+		}
+		summary.Blobs = len(archive.Blobs)
+		return nil
+	})
+	return summary, err
+}
+
+// nonEmptyTables バックアップするテーブルのうち、行があるものを返す
+func nonEmptyTables(tx *sql.Tx) ([]string, error) {
+	var names []string
+	for _, name := range backupTables {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM " + pq.QuoteIdentifier(name) + ")").Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// dumpTable テーブルの全行をIDの順に読み出す
+func dumpTable(tx *sql.Tx, name string) (backupTable, error) {
+	table := backupTable{Name: name, Rows: [][]interface{}{}}
+
+	rows, err := tx.Query("SELECT * FROM " + pq.QuoteIdentifier(name) + " ORDER BY id")
+	if err != nil {
+		return table, err
+	}
+	defer rows.Close()
+
+	table.Columns, err = rows.Columns()
+	if err != nil {
+		return table, err
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(table.Columns))
+		ptrs := make([]interface{}, len(values))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return table, err
+		}
+		// 文字列や配列はバイト列で返るため、JSONでBase64にならないよう文字列にする
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table, rows.Err()
+}
+
+// transientColumns 復元しない列。既定値で復元先の値になる
+//
+// 変更履歴のトランザクションIDは復元元のデータベースのもので、復元先では意味を持たない
+var transientColumns = map[string]bool{"xact_id": true}
+
+// restoreTable 行をそのまま挿入し、IDの採番を最大値の次から再開する
+func restoreTable(tx *sql.Tx, name string, table backupTable) error {
+	if len(table.Rows) > 0 {
+		var columns, placeholders []string
+		var keep []int
+		for i, c := range table.Columns {
+			if transientColumns[c] {
+				continue
+			}
+			keep = append(keep, i)
+			columns = append(columns, pq.QuoteIdentifier(c))
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(columns)))
+		}
+		stmt, err := tx.Prepare(fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES (%s)",
+			pq.QuoteIdentifier(name), strings.Join(columns, ", "), strings.Join(placeholders, ", "),
+		))
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				return fmt.Errorf("%w: 列の数が一致しません", ErrInvalidBackup)
+			}
+			args := make([]interface{}, len(keep))
+			for i, col := range keep {
+				v := row[col]
+				if n, ok := v.(json.Number); ok {
+					v = n.String()
+				}
+				args[i] = v
+			}
+			if _, err := stmt.Exec(args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := tx.Exec(
+		"SELECT setval(pg_get_serial_sequence($1, 'id'), COALESCE((SELECT MAX(id) FROM "+pq.QuoteIdentifier(name)+"), 0) + 1, false)",
+		name,
+	)
+	return err
+}
+
+func knownBackupTable(name string) bool {
+	for _, t := range backupTables {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readBlob 添付ファイルの内容をすべて読み出す
+func (s *TaskService) readBlob(key string) ([]byte, error) {
+	rc, err := s.blobs.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
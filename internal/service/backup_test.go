@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRestoreRequiresForceWhenAnyTableHasRows(t *testing.T) {
+	s := newTestService(t)
+	mustCreateTask(t, s, "バックアップするタスク")
+	var backup bytes.Buffer
+	if _, err := s.Backup(&backup, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// タスクを消しても、Webhookが残っていれば復元しない
+	if _, err := s.db.Exec("TRUNCATE tasks CASCADE"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateWebhook("https://example.com/hooks", "secret", nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.Restore(bytes.NewReader(backup.Bytes()), false)
+	if !errors.Is(err, ErrRestoreTargetNotEmpty) {
+		t.Fatalf("Restore error = %v, want ErrRestoreTargetNotEmpty", err)
+	}
+	hooks, err := s.ListWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 1 {
+		t.Fatalf("webhooks = %d, want the existing webhook kept", len(hooks))
+	}
+
+	if _, err := s.Restore(bytes.NewReader(backup.Bytes()), true); err != nil {
+		t.Fatalf("Restore(force) error = %v", err)
+	}
+	tasks, err := s.ListTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "バックアップするタスク" {
+		t.Errorf("tasks = %+v, want the backed up task", tasks)
+	}
+}
+
+func TestRestoreIntoEmptyDatabase(t *testing.T) {
+	s := newTestService(t)
+	id := mustCreateTask(t, s, "バックアップするタスク")
+	var backup bytes.Buffer
+	if _, err := s.Backup(&backup, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.db.Exec("TRUNCATE " + strings.Join(backupTables, ", ") + " RESTART IDENTITY"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Restore(&backup, false); err != nil {
+		t.Fatalf("Restore error = %v", err)
+	}
+	task, err := s.GetTask(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "バックアップするタスク" {
+		t.Errorf("task = %+v", task)
+	}
+}
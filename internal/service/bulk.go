@@ -0,0 +1,124 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"task-recommender/internal/model"
+)
+
+// MaxBulkOperations 1回の一括操作で受け付ける操作の数の上限
+const MaxBulkOperations = 500
+
+// ExecuteBulk 複数の操作を1つのトランザクションで順に実行し、操作ごとの結果を返す
+//
+// 操作ごとにセーブポイントを置き、失敗した操作だけを取り消す。BulkAtomicでは1件でも失敗すると
+// すべて取り消して ErrBulkFailed を返し、成功していた操作の結果は rolled_back になる
+func (s *TaskService) ExecuteBulk(ops []model.BulkOperation, mode model.BulkMode) (model.BulkReport, error) {
+	if mode == "" {
+		mode = model.BulkAtomic
+	}
+	report := model.BulkReport{Mode: mode, Results: make([]model.BulkResult, 0, len(ops))}
+	if mode != model.BulkAtomic && mode != model.BulkBestEffort {
+		return report, fmt.Errorf("%w: 不明なモード %s", ErrInvalidBulkOperation, mode)
+	}
+	if len(ops) > MaxBulkOperations {
+		return report, fmt.Errorf("%w: %d件まで", ErrTooManyBulkOperations, MaxBulkOperations)
+	}
+
+	err := s.withTx(func(tx *sql.Tx) error {
+		for i, op := range ops {
+			result := model.BulkResult{Index: i, Op: op.Op, TaskID: op.TaskID}
+
+			if _, err := tx.Exec("SAVEPOINT bulk_operation"); err != nil {
+				return err
+			}
+			id, err := s.IfVersion(op.Version).applyBulkOperation(tx, op)
+			if err != nil {
+				if _, rerr := tx.Exec("ROLLBACK TO SAVEPOINT bulk_operation"); rerr != nil {
+					return rerr
+				}
+				result.Status = model.BulkItemError
+				result.Error = err.Error()
+				report.Failed++
+			} else {
+				if _, err := tx.Exec("RELEASE SAVEPOINT bulk_operation"); err != nil {
+					return err
+				}
+				result.Status = model.BulkItemOK
+				result.TaskID = id
+				report.Succeeded++
+			}
+			report.Results = append(report.Results, result)
+		}
+
+		if mode == model.BulkAtomic && report.Failed > 0 {
+			return ErrBulkFailed
+		}
+		return nil
+	})
+
+	if errors.Is(err, ErrBulkFailed) {
+		for i := range report.Results {
+			r := &report.Results[i]
+			if r.Status != model.BulkItemOK {
+				continue
+			}
+			r.Status = model.BulkItemRolledBack
+			if r.Op == model.BulkCreate {
+				r.TaskID = 0
+			}
+		}
+		report.Succeeded = 0
+		return report, err
+	}
+	report.Committed = err == nil
+	return report, err
+}
+
+// applyBulkOperation 一括操作の1件をトランザクション内で実行し、対象のタスクIDを返す
+func (s *TaskService) applyBulkOperation(tx *sql.Tx, op model.BulkOperation) (int, error) {
+	if op.Op != model.BulkCreate && op.TaskID <= 0 {
+		return 0, fmt.Errorf("%w: タスクIDを指定してください", ErrInvalidBulkOperation)
+	}
+
+	switch op.Op {
+	case model.BulkCreate:
+		t := model.Task{Title: op.Title, Priority: op.Priority, EstimatedDuration: op.EstimatedDuration}
+		if problems := t.Validate(); len(problems) > 0 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidBulkOperation, strings.Join(problems, ", "))
+		}
+		if op.Project != "" {
+			t.Description, t.DueDate, t.Project = op.Description, op.DueDate, op.Project
+			return s.insertTask(tx, t)
+		}
+		return s.addTask(tx, op.Title, op.Description, op.Priority, op.DueDate, op.EstimatedDuration)
+	case model.BulkComplete:
+		return op.TaskID, s.transitionStatus(tx, op.TaskID, model.StatusDone, nil)
+	case model.BulkDelete:
+		return op.TaskID, s.deleteTask(tx, op.TaskID)
+	case model.BulkSetPriority:
+		if op.Priority < 1 || op.Priority > 3 {
+			return 0, fmt.Errorf("%w: 優先度は1〜3で指定してください", ErrInvalidBulkOperation)
+		}
+		return op.TaskID, s.setField(tx, op.TaskID, "priority", op.Priority)
+	case model.BulkSetDueDate:
+		return op.TaskID, s.setField(tx, op.TaskID, "due_date", op.DueDate)
+	case model.BulkSetDuration:
+		if op.EstimatedDuration < 0 {
+			return 0, fmt.Errorf("%w: 見積所要時間は0以上で指定してください", ErrInvalidBulkOperation)
+		}
+		return op.TaskID, s.setField(tx, op.TaskID, "estimated_duration", op.EstimatedDuration)
+	case model.BulkSetStatus:
+		if !op.Status.Valid() {
+			return 0, fmt.Errorf("%w: 不正な状態です: %s", ErrInvalidBulkOperation, op.Status)
+		}
+		return op.TaskID, s.transitionStatus(tx, op.TaskID, op.Status, nil)
+	case model.BulkSetProject:
+		return op.TaskID, s.setField(tx, op.TaskID, "project", op.Project)
+	default:
+		return 0, fmt.Errorf("%w: 不明な操作 %s", ErrInvalidBulkOperation, op.Op)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// ListChecklist タスクのチェックリストを表示順に取得する
+func (s *TaskService) ListChecklist(taskID int) ([]model.ChecklistItem, error) {
+	rows, err := s.db.Query(`
+        SELECT id, task_id, position, title, done, completed_at
+        FROM checklist_items
+        WHERE task_id = $1
+        ORDER BY position ASC, id ASC
+    `, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.ChecklistItem
+	for rows.Next() {
+		var item model.ChecklistItem
+		var completedAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.TaskID, &item.Position, &item.Title, &item.Done, &completedAt); err != nil {
+			return nil, err
+		}
+		if completedAt.Valid {
+			item.CompletedAt = completedAt.Time
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AddChecklistItem チェックリストの末尾に項目を追加する
+func (s *TaskService) AddChecklistItem(taskID int, title string) (model.ChecklistItem, error) {
+	if strings.TrimSpace(title) == "" {
+		return model.ChecklistItem{}, ErrEmptyChecklistItem
+	}
+
+	item := model.ChecklistItem{TaskID: taskID, Title: title}
+	err := s.withTx(func(tx *sql.Tx) error {
+		// 同時に追加されても表示順が重複しないようタスクの行をロックする
+		var locked int
+		err := tx.QueryRow("SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", taskID).Scan(&locked)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(
+			"SELECT COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE task_id = $1",
+			taskID,
+		).Scan(&item.Position)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(
+			`INSERT INTO checklist_items (task_id, position, title, done)
+            VALUES ($1, $2, $3, false)
+            RETURNING id`,
+			item.TaskID, item.Position, item.Title,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+		return s.recordHistory(tx, taskID, model.HistoryUpdated, "checklist", nil, item.Title)
+	})
+	return item, err
+}
+
+// ToggleChecklistItem チェックリストの項目の完了状態を切り替える
+func (s *TaskService) ToggleChecklistItem(taskID, itemID int) (model.ChecklistItem, error) {
+	var item model.ChecklistItem
+	err := s.withTx(func(tx *sql.Tx) error {
+		var completedAt sql.NullTime
+		err := tx.QueryRow(
+			`UPDATE checklist_items
+            SET done = NOT done, completed_at = CASE WHEN done THEN NULL ELSE $1::TIMESTAMP END
+            WHERE id = $2 AND task_id = $3
+            RETURNING id, task_id, position, title, done, completed_at`,
+			time.Now(), itemID, taskID,
+		).Scan(&item.ID, &item.TaskID, &item.Position, &item.Title, &item.Done, &completedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChecklistItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if completedAt.Valid {
+			item.CompletedAt = completedAt.Time
+		}
+		// 項目名は長くなりうるため field には含めず、変更前後の値に項目名と完了状態を残す
+		return s.recordHistory(tx, taskID, model.HistoryUpdated, "checklist",
+			map[string]interface{}{"title": item.Title, "done": !item.Done},
+			map[string]interface{}{"title": item.Title, "done": item.Done})
+	})
+	return item, err
+}
+
+// DeleteChecklistItem チェックリストの項目を削除する
+func (s *TaskService) DeleteChecklistItem(taskID, itemID int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		var title string
+		err := tx.QueryRow(
+			"DELETE FROM checklist_items WHERE id = $1 AND task_id = $2 RETURNING title",
+			itemID, taskID,
+		).Scan(&title)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChecklistItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return s.recordHistory(tx, taskID, model.HistoryUpdated, "checklist", title, nil)
+	})
+}
+
+// ReorderChecklist チェックリストの項目を指定したIDの順に並べ替える
+func (s *TaskService) ReorderChecklist(taskID int, itemIDs []int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT id FROM checklist_items WHERE task_id = $1 FOR UPDATE", taskID)
+		if err != nil {
+			return err
+		}
+		current := map[int]bool{}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			current[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(itemIDs) != len(current) {
+			return ErrInvalidChecklistOrder
+		}
+		seen := map[int]bool{}
+		for _, id := range itemIDs {
+			if !current[id] || seen[id] {
+				return ErrInvalidChecklistOrder
+			}
+			seen[id] = true
+		}
+
+		for position, id := range itemIDs {
+			_, err := tx.Exec("UPDATE checklist_items SET position = $1 WHERE id = $2", position, id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
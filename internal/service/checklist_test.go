@@ -0,0 +1,39 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToggleChecklistItemHistory(t *testing.T) {
+	s := newTestService(t)
+	id := mustCreateTask(t, s, "チェックリストのあるタスク")
+
+	// field の上限 (50文字) より長い項目名でも履歴を記録できる
+	title := strings.Repeat("長い項目名", 20)
+	item, err := s.AddChecklistItem(id, title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toggled, err := s.ToggleChecklistItem(id, item.ID)
+	if err != nil {
+		t.Fatalf("ToggleChecklistItem: %v", err)
+	}
+	if !toggled.Done || toggled.CompletedAt.IsZero() {
+		t.Errorf("toggled = %+v, want done", toggled)
+	}
+
+	history, err := s.ListHistory(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := history[len(history)-1]
+	if last.Field != "checklist" {
+		t.Errorf("Field = %q, want checklist", last.Field)
+	}
+	wantOld := `{"done":false,"title":"` + title + `"}`
+	wantNew := `{"done":true,"title":"` + title + `"}`
+	if last.OldValue != wantOld || last.NewValue != wantNew {
+		t.Errorf("値 = %s -> %s, want %s -> %s", last.OldValue, last.NewValue, wantOld, wantNew)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// ListComments タスクのコメントを投稿順に取得する
+func (s *TaskService) ListComments(taskID int) ([]model.Comment, error) {
+	rows, err := s.db.Query(`
+        SELECT id, task_id, author, body, created_at, updated_at
+        FROM task_comments
+        WHERE task_id = $1
+        ORDER BY created_at ASC, id ASC
+    `, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+	for rows.Next() {
+		var c model.Comment
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.TaskID, &c.Author, &c.Body, &c.CreatedAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		if updatedAt.Valid {
+			c.UpdatedAt = updatedAt.Time
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// AddComment タスクにコメントを投稿する。投稿者は操作ユーザー
+func (s *TaskService) AddComment(taskID int, body string) (model.Comment, error) {
+	if strings.TrimSpace(body) == "" {
+		return model.Comment{}, ErrEmptyComment
+	}
+	if err := s.ensureTaskExists(taskID); err != nil {
+		return model.Comment{}, err
+	}
+
+	c := model.Comment{TaskID: taskID, Author: s.actor, Body: body, CreatedAt: time.Now()}
+	err := s.db.QueryRow(
+		`INSERT INTO task_comments (task_id, author, body, created_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id`,
+		c.TaskID, c.Author, c.Body, c.CreatedAt,
+	).Scan(&c.ID)
+	return c, err
+}
+
+// UpdateComment コメントを編集する。編集できるのは投稿者のみ
+func (s *TaskService) UpdateComment(taskID, commentID int, body string) (model.Comment, error) {
+	if strings.TrimSpace(body) == "" {
+		return model.Comment{}, ErrEmptyComment
+	}
+
+	var c model.Comment
+	err := s.withTx(func(tx *sql.Tx) error {
+		if err := s.checkCommentAuthor(tx, taskID, commentID); err != nil {
+			return err
+		}
+
+		c = model.Comment{ID: commentID, TaskID: taskID, Body: body, UpdatedAt: time.Now()}
+		return tx.QueryRow(
+			`UPDATE task_comments SET body = $1, updated_at = $2
+            WHERE id = $3
+            RETURNING author, created_at`,
+			c.Body, c.UpdatedAt, c.ID,
+		).Scan(&c.Author, &c.CreatedAt)
+	})
+	return c, err
+}
+
+// DeleteComment コメントを削除する。削除できるのは投稿者のみ
+func (s *TaskService) DeleteComment(taskID, commentID int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		if err := s.checkCommentAuthor(tx, taskID, commentID); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM task_comments WHERE id = $1", commentID)
+		return err
+	})
+}
+
+// checkCommentAuthor コメントが存在し、操作ユーザーが投稿者であることを確認する
+func (s *TaskService) checkCommentAuthor(tx *sql.Tx, taskID, commentID int) error {
+	var author string
+	err := tx.QueryRow(
+		"SELECT author FROM task_comments WHERE id = $1 AND task_id = $2 FOR UPDATE",
+		commentID, taskID,
+	).Scan(&author)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if author != s.actor {
+		return ErrNotCommentAuthor
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-recommender/internal/digest"
+	"task-recommender/internal/model"
+	"task-recommender/internal/notify"
+)
+
+// BuildDigest ユーザーの毎朝のまとめを作成する。dayはまとめの対象日の0時で、前日の範囲もそのタイムゾーンで決める
+func (s *TaskService) BuildDigest(user string, day time.Time) (model.Digest, error) {
+	d := model.Digest{User: user, Date: day}
+
+	recommendations, err := s.RecommendTasks(DefaultRecommendationLimit)
+	if err != nil {
+		return d, err
+	}
+	d.Recommendations = recommendations
+
+	// 対象日より前が期限のタスク。対象日が期限のタスクはその日のうちに終えればよいため含めない
+	overdue, err := s.queryTasks(
+		"deleted_at IS NULL AND status NOT IN ('done', 'cancelled') AND due_date < $2",
+		"due_date ASC, id ASC",
+		day.In(time.Local),
+	)
+	if err != nil {
+		return d, err
+	}
+	for _, t := range overdue {
+		if !t.DueDate.IsZero() {
+			d.Overdue = append(d.Overdue, t)
+		}
+	}
+
+	// 日時はタイムゾーンなしで保存しているため、サーバーのタイムゾーンにそろえて比べる
+	start, end := day.AddDate(0, 0, -1).In(time.Local), day.In(time.Local)
+	d.Completed, err = s.queryTasks(
+		`deleted_at IS NULL AND status = 'done' AND id IN (
+            SELECT task_id FROM task_history
+            WHERE action = 'status_changed' AND new_value = 'done' AND actor = $2 AND created_at >= $3 AND created_at < $4
+        )`,
+		"completed_at ASC, id ASC",
+		user, start, end,
+	)
+	return d, err
+}
+
+// errDigestEmpty まとめに載せる内容がないため送らなかったことを表す内部エラー
+var errDigestEmpty = errors.New("digest is empty")
+
+// SendDigests 受信者ごとに対象日のまとめを1度だけメールで送り、送った件数を返す
+//
+// 送る前に送信記録を追加して確保するため、複数のサーバーから呼んでも重ねて送らない。
+// 送信に失敗した場合は記録を消し、次の呼び出しで再び送る。
+// 載せる内容がない場合は送らず、同じ日のうちに内容ができれば送れるよう記録も消す
+func (s *TaskService) SendDigests(mailer notify.Mailer, recipients []model.DigestRecipient, day time.Time) (int, error) {
+	sent := 0
+	var errs []error
+	for _, r := range recipients {
+		id, claimed, err := s.claimDigest(r.User, day)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.sendDigest(mailer, r, day); err != nil {
+			if !errors.Is(err, errDigestEmpty) {
+				errs = append(errs, fmt.Errorf("%s: %w", r.User, err))
+			}
+			if _, err := s.db.Exec("DELETE FROM digest_log WHERE id = $1", id); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// sendDigest まとめを作成してメールで送る。載せる内容がない場合は errDigestEmpty を返す
+func (s *TaskService) sendDigest(mailer notify.Mailer, r model.DigestRecipient, day time.Time) error {
+	d, err := s.BuildDigest(r.User, day)
+	if err != nil {
+		return err
+	}
+	if d.Empty() {
+		return errDigestEmpty
+	}
+	text, html, err := digest.Render(d)
+	if err != nil {
+		return err
+	}
+	return mailer.Send([]string{r.Email}, digest.Subject(d), text, html)
+}
+
+// claimDigest まとめの送信記録を追加する。既に送っている場合はfalseを返す
+func (s *TaskService) claimDigest(user string, day time.Time) (int, bool, error) {
+	var id int
+	err := s.db.QueryRow(
+		`INSERT INTO digest_log (username, digest_date, sent_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING
+        RETURNING id`,
+		user, day.Format("2006-01-02"), time.Now(),
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// recordingMailer 送ったメールを記録する
+type recordingMailer struct {
+	sent []string
+}
+
+func (m *recordingMailer) Send(to []string, subject, text, html string) error {
+	m.sent = append(m.sent, to...)
+	return nil
+}
+
+func TestSendDigestsSkipsEmptyDigestWithoutClaiming(t *testing.T) {
+	s := newTestService(t)
+	mailer := &recordingMailer{}
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	recipients := []model.DigestRecipient{{User: "tester", Email: "tester@example.com"}}
+
+	sent, err := s.SendDigests(mailer, recipients, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 0 || len(mailer.sent) != 0 {
+		t.Fatalf("sent = %d, mails = %v, want none for an empty digest", sent, mailer.sent)
+	}
+	var claims int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM digest_log").Scan(&claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims != 0 {
+		t.Fatalf("digest_log rows = %d, want the claim released", claims)
+	}
+
+	// 同じ日のうちに内容ができれば送り、その後は重ねて送らない
+	mustCreateTask(t, s, "おすすめのタスク")
+	for i, want := range []int{1, 0} {
+		sent, err := s.SendDigests(mailer, recipients, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent != want {
+			t.Errorf("call %d: sent = %d, want %d", i+1, sent, want)
+		}
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0] != "tester@example.com" {
+		t.Errorf("mails = %v, want one to tester@example.com", mailer.sent)
+	}
+}
+
+func TestBuildDigestOverdueBeforeDay(t *testing.T) {
+	s := newTestService(t)
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	for _, task := range []model.Task{
+		{Title: "昨日が期限", DueDate: day.AddDate(0, 0, -1)},
+		{Title: "昨日の夜が期限", DueDate: day.Add(-time.Minute)},
+		{Title: "今日が期限", DueDate: day},
+		{Title: "今日の0時1分が期限", DueDate: day.Add(time.Minute)},
+		{Title: "期限なし"},
+	} {
+		task.Priority = 1
+		if _, err := s.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := s.BuildDigest("tester", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, task := range d.Overdue {
+		titles = append(titles, task.Title)
+	}
+	if len(titles) != 2 || titles[0] != "昨日が期限" || titles[1] != "昨日の夜が期限" {
+		t.Errorf("Overdue = %v, want the two tasks due before %s", titles, day.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"task-recommender/internal/model"
+)
+
+// ListEvents afterIDのイベントより後に記録されたタスクのイベントを古い順にlimit件まで取得する
+//
+// イベントは変更履歴そのもので、イベントIDは変更履歴のIDになる。
+// IDの採番順とコミット順は一致しないため、イベントは記録したトランザクションのIDの順に並べ、
+// 実行中のどのトランザクションよりも前に始まったトランザクションの分だけを返す。
+// これにより後からコミットされた変更が既に読んだ位置より前に現れることはなく、
+// 切断したクライアントは最後に受け取ったIDから取りこぼしなく再開できる。
+// afterIDが0または見つからない場合は最初から返す
+func (s *TaskService) ListEvents(afterID int64, limit int) ([]model.TaskEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.Query(`
+        SELECT h.id, h.task_id, COALESCE(t.project, ''), h.action, COALESCE(h.field, ''), COALESCE(h.old_value, ''), COALESCE(h.new_value, ''), h.actor, h.created_at
+        FROM task_history h
+        LEFT JOIN tasks t ON t.id = h.task_id
+        WHERE h.xact_id < pg_snapshot_xmin(pg_current_snapshot())
+            AND (h.xact_id, h.id) > (COALESCE((SELECT xact_id FROM task_history WHERE id = $1), '0'::xid8), $1)
+            AND h.action <> $2
+        ORDER BY h.xact_id, h.id
+        LIMIT $3
+    `, afterID, model.HistoryPurged, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.TaskEvent{}
+	for rows.Next() {
+		var e model.TaskEvent
+		var action model.HistoryAction
+		err := rows.Scan(&e.ID, &e.TaskID, &e.Project, &action, &e.Field, &e.OldValue, &e.NewValue, &e.Actor, &e.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		e.Type = model.EventTypeForHistory(action, e.NewValue)
+		if e.Type == "" {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// LatestEventID ListEvents で読める最後のイベントのID。イベントがない場合は0を返す
+//
+// 実行中のトランザクションの変更はこのIDより後として ListEvents で読めるため、呼び出し以降の変更を取りこぼさない
+func (s *TaskService) LatestEventID() (int64, error) {
+	var id int64
+	err := s.db.QueryRow(`
+        SELECT COALESCE((
+            SELECT id FROM task_history
+            WHERE xact_id < pg_snapshot_xmin(pg_current_snapshot())
+            ORDER BY xact_id DESC, id DESC
+            LIMIT 1
+        ), 0)
+    `).Scan(&id)
+	return id, err
+}
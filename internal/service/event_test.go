@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"task-recommender/internal/model"
+)
+
+// TestListEventsWaitsForEarlierTransactions 先に採番したトランザクションが後からコミットしても、イベントを取りこぼさない
+func TestListEventsWaitsForEarlierTransactions(t *testing.T) {
+	s := newTestService(t)
+	taskID := mustCreateTask(t, s, "イベントのタスク")
+	start, err := s.LatestEventID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Rollback()
+	if err := s.recordHistory(first, taskID, model.HistoryUpdated, "title", "a", "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := s.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Rollback()
+	if err := s.recordHistory(second, taskID, model.HistoryUpdated, "title", "first", "second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 先に始まったトランザクションが実行中の間は、後のトランザクションの変更も返さない
+	events, err := s.ListEvents(start, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("events while the first transaction is open = %+v, want none", events)
+	}
+	if latest, err := s.LatestEventID(); err != nil || latest != start {
+		t.Fatalf("LatestEventID = %d, %v, want %d", latest, err, start)
+	}
+
+	if err := first.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	events, err = s.ListEvents(start, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].NewValue != "first" || events[1].NewValue != "second" {
+		t.Fatalf("events = %+v, want first then second", events)
+	}
+
+	// 最後に受け取ったIDから再開すると、それより後のイベントだけを返す
+	rest, err := s.ListEvents(events[0].ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].ID != events[1].ID {
+		t.Fatalf("events after %d = %+v, want only %d", events[0].ID, rest, events[1].ID)
+	}
+	if rest, err := s.ListEvents(events[1].ID, 10); err != nil || len(rest) != 0 {
+		t.Fatalf("events after the last = %+v, %v, want none", rest, err)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+)
+
+// ListHistory タスクの変更履歴を古い順に取得する
+func (s *TaskService) ListHistory(id int) ([]model.HistoryEntry, error) {
+	return s.queryHistory("task_id = $1", id)
+}
+
+// ListHistoryForTasks 複数のタスクの変更履歴をまとめて古い順に取得する
+func (s *TaskService) ListHistoryForTasks(ids []int) ([]model.HistoryEntry, error) {
+	return s.queryHistory("task_id = ANY($1)", pq.Array(ids))
+}
+
+func (s *TaskService) queryHistory(where string, args ...interface{}) ([]model.HistoryEntry, error) {
+	rows, err := s.db.Query(`
+        SELECT id, task_id, action, COALESCE(field, ''), COALESCE(old_value, ''), COALESCE(new_value, ''), actor, created_at
+        FROM task_history
+        WHERE `+where+`
+        ORDER BY created_at ASC, id ASC
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.HistoryEntry
+	for rows.Next() {
+		var e model.HistoryEntry
+		err := rows.Scan(&e.ID, &e.TaskID, &e.Action, &e.Field, &e.OldValue, &e.NewValue, &e.Actor, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// recordHistory 変更履歴を1件追記する。値は文字列に変換して保存する
+//
+// 変更がイベントに当たる場合は、購読しているWebhookへの送信も同じトランザクションで登録する
+func (s *TaskService) recordHistory(tx *sql.Tx, id int, action model.HistoryAction, field string, oldValue, newValue interface{}) error {
+	now := time.Now()
+	newString := historyValue(newValue)
+
+	var historyID int64
+	err := tx.QueryRow(
+		`INSERT INTO task_history (task_id, action, field, old_value, new_value, actor, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id`,
+		id, action, nullString(field), historyValue(oldValue), newString, s.actor, now,
+	).Scan(&historyID)
+	if err != nil {
+		return err
+	}
+
+	eventType := model.EventTypeForHistory(action, newString.String)
+	if eventType == "" {
+		return nil
+	}
+	return s.enqueueWebhookDeliveries(tx, historyID, eventType, now)
+}
+
+// historyValue 履歴に保存する値を文字列に変換する。nilはNULLとして保存する
+func historyValue(v interface{}) sql.NullString {
+	switch v := v.(type) {
+	case nil:
+		return sql.NullString{}
+	case sql.NullTime:
+		if !v.Valid {
+			return sql.NullString{}
+		}
+		return historyValue(v.Time)
+	case sql.NullString:
+		return v
+	case time.Time:
+		return sql.NullString{String: v.Format(time.RFC3339), Valid: true}
+	case []byte:
+		return sql.NullString{String: string(v), Valid: true}
+	case map[string]interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return sql.NullString{}
+		}
+		return sql.NullString{String: string(b), Valid: true}
+	default:
+		return sql.NullString{String: fmt.Sprint(v), Valid: true}
+	}
+}
+
+// nullString 空文字列をNULLとして扱うヘルパー関数
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
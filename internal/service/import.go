@@ -0,0 +1,248 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+)
+
+// errDryRun ドライランでトランザクションをロールバックするための内部エラー
+var errDryRun = errors.New("dry run")
+
+// ImportTasks インポートした行を外部ID（なければタスクのID）で突き合わせて作成または更新する
+//
+// すべての行を1つのトランザクションで処理し、1行でもエラーがあれば何も保存せず、エラーのない行は skip として返す。
+// dryRunの場合は検証と処理内容の判定のみ行い、保存しない
+func (s *TaskService) ImportTasks(records []model.ImportRecord, dryRun bool) (model.ImportReport, error) {
+	report := model.ImportReport{DryRun: dryRun, Results: make([]model.ImportResult, 0, len(records))}
+
+	err := s.withTx(func(tx *sql.Tx) error {
+		for _, rec := range records {
+			result := model.ImportResult{Line: rec.Line, ExternalID: rec.Task.ExternalID, Title: rec.Task.Title}
+
+			problems := append(append([]string{}, rec.Errors...), rec.Task.Validate()...)
+			if len(problems) > 0 {
+				result.Action = model.ImportError
+				result.Errors = problems
+				report.Failed++
+				report.Results = append(report.Results, result)
+				continue
+			}
+
+			id, created, err := s.upsertTask(tx, rec)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.Action = model.ImportCreate
+				report.Created++
+				if !dryRun {
+					result.TaskID = id
+				}
+			} else {
+				result.Action = model.ImportUpdate
+				result.TaskID = id
+				report.Updated++
+			}
+			report.Results = append(report.Results, result)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		if report.Failed > 0 {
+			return ErrImportInvalid
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return report, nil
+	}
+	if errors.Is(err, ErrImportInvalid) {
+		report.Created, report.Updated = 0, 0
+		for i := range report.Results {
+			if report.Results[i].Action != model.ImportError {
+				report.Results[i].Action = model.ImportSkip
+				report.Results[i].TaskID = 0
+			}
+		}
+		return report, err
+	}
+	return report, err
+}
+
+// upsertTask 外部IDが一致するタスクがあれば更新し、なければ作成する
+//
+// 外部IDが空の場合は、エクスポートしたファイルを取り込み直せるようタスクのIDで突き合わせる。どちらも空なら常に作成する
+func (s *TaskService) upsertTask(tx *sql.Tx, rec model.ImportRecord) (int, bool, error) {
+	t := rec.Task
+	var id int
+	var err error
+	switch {
+	case t.ExternalID != "":
+		err = tx.QueryRow("SELECT id FROM tasks WHERE external_id = $1 FOR UPDATE", t.ExternalID).Scan(&id)
+	case t.ID > 0:
+		err = tx.QueryRow("SELECT id FROM tasks WHERE id = $1 FOR UPDATE", t.ID).Scan(&id)
+	default:
+		err = sql.ErrNoRows
+	}
+	if err == nil {
+		return id, false, s.overwriteTask(tx, id, rec)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, err
+	}
+
+	id, err = s.insertTask(tx, t)
+	return id, true, err
+}
+
+// insertTask 状態や日時を含めてタスクを作成する
+func (s *TaskService) insertTask(tx *sql.Tx, t model.Task) (int, error) {
+	now := time.Now()
+	if t.Status == "" {
+		t.Status = model.StatusTodo
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	var completedAt sql.NullTime
+	if t.Status == model.StatusDone {
+		completedAt = sql.NullTime{Time: t.CompletedAt, Valid: true}
+		if t.CompletedAt.IsZero() {
+			completedAt.Time = now
+		}
+	}
+
+	var id int
+	err := tx.QueryRow(
+		`INSERT INTO tasks
+        (external_id, title, description, done, status, priority, due_date, estimated_duration, project, contexts, created_at, completed_at, status_changed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING id`,
+		nullString(t.ExternalID), t.Title, t.Description, t.Status == model.StatusDone, t.Status,
+		t.Priority, t.DueDate, t.EstimatedDuration, t.Project, pq.Array(contexts(t.Contexts)), t.CreatedAt, completedAt, now,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.recordTransition(tx, id, "", t.Status, now); err != nil {
+		return 0, err
+	}
+	return id, s.recordHistory(tx, id, model.HistoryCreated, "", nil, map[string]interface{}{
+		"external_id":        t.ExternalID,
+		"title":              t.Title,
+		"description":        t.Description,
+		"status":             t.Status,
+		"priority":           t.Priority,
+		"due_date":           t.DueDate,
+		"estimated_duration": t.EstimatedDuration,
+		"project":            t.Project,
+		"contexts":           t.Contexts,
+	})
+}
+
+// overwriteTask 既存のタスクを取り込んだ内容で上書きし、変わった項目を履歴に記録する
+//
+// 外部の内容を正とするため、状態は遷移の制約によらず直接設定する。ゴミ箱にある場合は元に戻す。
+// ファイルに含まれていない項目は既存の値を残す
+func (s *TaskService) overwriteTask(tx *sql.Tx, id int, rec model.ImportRecord) error {
+	t := rec.Task
+	var old model.Task
+	var dueDate sql.NullTime
+	err := tx.QueryRow(
+		`SELECT title, description, status, priority, due_date, estimated_duration, project, contexts
+        FROM tasks WHERE id = $1`,
+		id,
+	).Scan(&old.Title, &old.Description, &old.Status, &old.Priority, &dueDate, &old.EstimatedDuration, &old.Project, pq.Array(&old.Contexts))
+	if err != nil {
+		return err
+	}
+	if dueDate.Valid {
+		old.DueDate = dueDate.Time
+	}
+	if t.Status == "" {
+		t.Status = old.Status
+	}
+	if !rec.Has("title") {
+		t.Title = old.Title
+	}
+	if !rec.Has("description") {
+		t.Description = old.Description
+	}
+	if !rec.Has("priority") {
+		t.Priority = old.Priority
+	}
+	if !rec.Has("due_date") {
+		t.DueDate = old.DueDate
+	}
+	if !rec.Has("estimated_duration") {
+		t.EstimatedDuration = old.EstimatedDuration
+	}
+	if !rec.Has("project") {
+		t.Project = old.Project
+	}
+	if !rec.Has("contexts") {
+		t.Contexts = old.Contexts
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(
+		`UPDATE tasks SET title = $1, description = $2, priority = $3, due_date = $4, estimated_duration = $5,
+            status = $6, done = $7,
+            completed_at = CASE WHEN $7 THEN COALESCE(completed_at, $8) ELSE NULL END,
+            status_changed_at = CASE WHEN status <> $6 THEN $8 ELSE status_changed_at END,
+            project = $9, contexts = $10,
+            deleted_at = NULL
+        WHERE id = $11`,
+		t.Title, t.Description, t.Priority, t.DueDate, t.EstimatedDuration,
+		t.Status, t.Status == model.StatusDone, now, t.Project, pq.Array(contexts(t.Contexts)), id,
+	)
+	if err != nil {
+		return err
+	}
+
+	changes := []struct {
+		field    string
+		old, new interface{}
+		changed  bool
+	}{
+		{"title", old.Title, t.Title, old.Title != t.Title},
+		{"description", old.Description, t.Description, old.Description != t.Description},
+		{"priority", old.Priority, t.Priority, old.Priority != t.Priority},
+		{"due_date", old.DueDate, t.DueDate, !old.DueDate.Equal(t.DueDate)},
+		{"estimated_duration", old.EstimatedDuration, t.EstimatedDuration, old.EstimatedDuration != t.EstimatedDuration},
+		{"project", old.Project, t.Project, old.Project != t.Project},
+		{"contexts", old.Contexts, t.Contexts, strings.Join(old.Contexts, " ") != strings.Join(t.Contexts, " ")},
+	}
+	for _, c := range changes {
+		if !c.changed {
+			continue
+		}
+		if err := s.recordHistory(tx, id, model.HistoryUpdated, c.field, c.old, c.new); err != nil {
+			return err
+		}
+	}
+
+	if old.Status != t.Status {
+		if err := s.recordTransition(tx, id, old.Status, t.Status, now); err != nil {
+			return err
+		}
+		return s.recordHistory(tx, id, model.HistoryStatusChanged, "status", old.Status, t.Status)
+	}
+	return nil
+}
+
+// contexts NOT NULL の列に保存できるよう、nilを空の配列にする
+func contexts(c []string) []string {
+	if c == nil {
+		return []string{}
+	}
+	return c
+}
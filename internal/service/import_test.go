@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"task-recommender/internal/csvio"
+	"task-recommender/internal/model"
+)
+
+func TestImportTasksInvalidRowSkipsOthers(t *testing.T) {
+	s := newTestService(t)
+	existing := mustCreateTask(t, s, "既存のタスク")
+
+	report, err := s.ImportTasks([]model.ImportRecord{
+		{Line: 2, Task: model.Task{Title: "新しいタスク", Priority: 1}},
+		{Line: 3, Task: model.Task{ID: existing, Title: "更新したタスク", Priority: 1}},
+		{Line: 4, Task: model.Task{Title: "", Priority: 1}},
+	}, false)
+	if !errors.Is(err, ErrImportInvalid) {
+		t.Fatalf("err = %v, want ErrImportInvalid", err)
+	}
+	if report.Created != 0 || report.Updated != 0 || report.Failed != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	for _, r := range report.Results[:2] {
+		if r.Action != model.ImportSkip || r.TaskID != 0 {
+			t.Errorf("%d行目: action=%s task_id=%d, want skip without task_id", r.Line, r.Action, r.TaskID)
+		}
+	}
+	if r := report.Results[2]; r.Action != model.ImportError {
+		t.Errorf("4行目: action = %s, want error", r.Action)
+	}
+
+	tasks, err := s.ListTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "既存のタスク" {
+		t.Errorf("tasks = %+v, want only the unchanged existing task", tasks)
+	}
+}
+
+func TestImportTasksMatchesByID(t *testing.T) {
+	s := newTestService(t)
+	id := mustCreateTask(t, s, "エクスポートしたタスク")
+
+	report, err := s.ImportTasks([]model.ImportRecord{
+		{Line: 2, Task: model.Task{ID: id, Title: "編集したタスク", Priority: 3}},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Updated != 1 || report.Created != 0 || report.Results[0].TaskID != id {
+		t.Fatalf("report = %+v, want task %d updated", report, id)
+	}
+
+	task, err := s.GetTask(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "編集したタスク" || task.Priority != 3 {
+		t.Errorf("task = %+v", task)
+	}
+}
+
+func TestImportTasksPartialCSVKeepsMissingColumns(t *testing.T) {
+	s := newTestService(t)
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	report, err := s.ImportTasks([]model.ImportRecord{{Line: 1, Task: model.Task{
+		Title:             "既存のタスク",
+		Description:       "説明",
+		Priority:          3,
+		DueDate:           due,
+		EstimatedDuration: 90,
+		Project:           "home",
+		Contexts:          []string{"phone"},
+	}}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := report.Results[0].TaskID
+
+	// タイトルの列しかないCSVで取り込み直す。優先度は列があっても空欄なら変えない
+	csv := "id,title,priority\n" + strconv.Itoa(id) + ",タイトルだけ変更,\n"
+	records, err := csvio.Read(strings.NewReader(csv), csvio.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ImportTasks(records, false); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := s.GetTask(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "タイトルだけ変更" {
+		t.Errorf("Title = %q", task.Title)
+	}
+	if task.Description != "説明" || task.Priority != 3 || !task.DueDate.Equal(due) || task.EstimatedDuration != 90 ||
+		task.Project != "home" || strings.Join(task.Contexts, ",") != "phone" {
+		t.Errorf("CSVにない項目が変わりました: %+v", task)
+	}
+
+	history, err := s.ListHistory(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range history[1:] {
+		if h.Field != "title" {
+			t.Errorf("CSVにない項目 %s の変更が記録されました", h.Field)
+		}
+	}
+}
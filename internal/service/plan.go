@@ -0,0 +1,87 @@
+package service
+
+import (
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+const (
+	// DefaultPlanDays 作業予定を立てる日数の既定値
+	DefaultPlanDays = 7
+	// MaxPlanDays 作業予定を立てる日数の上限
+	MaxPlanDays = 90
+
+	// 作業時間帯（ローカル時刻）
+	workdayStartHour = 9
+	workdayEndHour   = 18
+)
+
+// PlanWorkBlocks おすすめ度の高い順に、残りの見積所要時間を平日の作業時間帯へ割り当てる
+//
+// fromから days 日分を対象とし、収まらないタスクは途中まで、または予定に含めない
+func (s *TaskService) PlanWorkBlocks(from time.Time, days int) ([]model.WorkBlock, error) {
+	if days <= 0 {
+		days = DefaultPlanDays
+	}
+	if days > MaxPlanDays {
+		days = MaxPlanDays
+	}
+
+	tasks, err := s.queryTasks(
+		"deleted_at IS NULL AND status IN ('todo', 'in_progress')",
+		"priority DESC, due_date ASC",
+	)
+	if err != nil {
+		return nil, err
+	}
+	slots := workSlots(from, days)
+	var blocks []model.WorkBlock
+	for _, r := range rankTasks(tasks, from) {
+		remaining := time.Duration(r.RemainingEffort) * time.Minute
+		for remaining > 0 && len(slots) > 0 {
+			slot := &slots[0]
+			end := slot.start.Add(remaining)
+			if end.After(slot.end) {
+				end = slot.end
+			}
+			blocks = append(blocks, model.WorkBlock{
+				TaskID: r.Task.ID,
+				Title:  r.Task.Title,
+				Start:  slot.start,
+				End:    end,
+			})
+			remaining -= end.Sub(slot.start)
+			slot.start = end
+			if !slot.start.Before(slot.end) {
+				slots = slots[1:]
+			}
+		}
+	}
+	return blocks, nil
+}
+
+type workSlot struct {
+	start, end time.Time
+}
+
+// workSlots fromから days 日分の平日の作業時間帯。当日は from 以降のみ
+func workSlots(from time.Time, days int) []workSlot {
+	var slots []workSlot
+	y, m, d := from.Date()
+	for i := 0; i < days; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, from.Location())
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			continue
+		}
+		start := day.Add(workdayStartHour * time.Hour)
+		end := day.Add(workdayEndHour * time.Hour)
+		if start.Before(from) {
+			start = from.Truncate(time.Minute)
+		}
+		if start.Before(end) {
+			slots = append(slots, workSlot{start: start, end: end})
+		}
+	}
+	return slots
+}
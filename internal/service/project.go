@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+)
+
+// ListProjects タスクのあるプロジェクトを名前順に取得する
+func (s *TaskService) ListProjects() ([]model.ProjectSummary, error) {
+	rows, err := s.db.Query(`
+        SELECT project, COUNT(*), COUNT(*) FILTER (WHERE status NOT IN ($1, $2))
+        FROM tasks
+        WHERE deleted_at IS NULL AND project <> ''
+        GROUP BY project
+        ORDER BY project
+    `, model.StatusDone, model.StatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []model.ProjectSummary{}
+	for rows.Next() {
+		var p model.ProjectSummary
+		if err := rows.Scan(&p.Name, &p.TaskCount, &p.OpenTaskCount); err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+// ListTags タスクに付いているタグ（状況）を名前順に取得する
+func (s *TaskService) ListTags() ([]model.TagSummary, error) {
+	rows, err := s.db.Query(`
+        SELECT tag, COUNT(*)
+        FROM tasks, UNNEST(contexts) AS tag
+        WHERE deleted_at IS NULL
+        GROUP BY tag
+        ORDER BY tag
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.TagSummary{}
+	for rows.Next() {
+		var t model.TagSummary
+		if err := rows.Scan(&t.Name, &t.TaskCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// GetTasks IDを指定してタスクをまとめて取得する。ゴミ箱にあるタスクと見つからないIDは含めない
+func (s *TaskService) GetTasks(ids []int) ([]model.Task, error) {
+	return s.queryTasks("id = ANY($2) AND deleted_at IS NULL", "id", pq.Array(ids))
+}
+
+// ListTasksInProjects いずれかのプロジェクトに属するタスクをまとめて取得する
+func (s *TaskService) ListTasksInProjects(projects []string) ([]model.Task, error) {
+	return s.queryTasks("project = ANY($2) AND deleted_at IS NULL", "priority DESC, due_date ASC", pq.Array(projects))
+}
+
+// ListTasksWithTags いずれかのタグが付いたタスクをまとめて取得する
+func (s *TaskService) ListTasksWithTags(tags []string) ([]model.Task, error) {
+	return s.queryTasks("contexts && $2::text[] AND deleted_at IS NULL", "priority DESC, due_date ASC", pq.Array(tags))
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// DefaultRecommendationLimit おすすめするタスク数の既定値
+const DefaultRecommendationLimit = 5
+
+// RecommendTasks 未完了のタスクをおすすめ度の高い順にlimit件まで返す
+func (s *TaskService) RecommendTasks(limit int) ([]model.Recommendation, error) {
+	if limit <= 0 {
+		limit = DefaultRecommendationLimit
+	}
+
+	tasks, err := s.queryTasks(
+		"deleted_at IS NULL AND status IN ('todo', 'in_progress')",
+		"priority DESC, due_date ASC",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := rankTasks(tasks, time.Now())
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// rankTasks タスクのおすすめ度を計算し、高い順に並べる
+func rankTasks(tasks []model.Task, now time.Time) []model.Recommendation {
+	recommendations := make([]model.Recommendation, 0, len(tasks))
+	for _, t := range tasks {
+		recommendations = append(recommendations, recommend(t, now))
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	return recommendations
+}
+
+// RemainingEffort 残りの見積所要時間（分）。見積時間にチェックリストの未完了の割合を掛ける
+func RemainingEffort(t model.Task) int {
+	return int(math.Ceil(float64(t.EstimatedDuration) * t.Checklist.Remaining()))
+}
+
+// recommend タスクのおすすめ度を計算する
+//
+// 優先度、期限までの近さ、着手済みかどうか、残りの作業量（少ないほど片付けやすい）を足し合わせる
+func recommend(t model.Task, now time.Time) model.Recommendation {
+	r := model.Recommendation{Task: t, RemainingEffort: RemainingEffort(t)}
+
+	r.Score += float64(t.Priority) * 10
+	if t.Priority >= 3 {
+		r.Reasons = append(r.Reasons, "優先度が高い")
+	}
+
+	if !t.DueDate.IsZero() {
+		days := t.DueDate.Sub(now).Hours() / 24
+		switch {
+		case days < 0:
+			r.Score += 30
+			r.Reasons = append(r.Reasons, "期限切れ")
+		case days < 1:
+			r.Score += 20
+			r.Reasons = append(r.Reasons, "期限が24時間以内")
+		case days < 3:
+			r.Score += 10
+			r.Reasons = append(r.Reasons, "期限が3日以内")
+		case days < 7:
+			r.Score += 5
+			r.Reasons = append(r.Reasons, "期限が1週間以内")
+		}
+	}
+
+	if t.Status == model.StatusInProgress {
+		r.Score += 5
+		r.Reasons = append(r.Reasons, "着手済み")
+	}
+
+	if t.EstimatedDuration > 0 {
+		r.Score += 10 / (1 + float64(r.RemainingEffort)/30)
+		if t.Checklist.Total > 0 && t.Checklist.Done > 0 {
+			r.Reasons = append(r.Reasons, "チェックリストが"+t.Checklist.String()+"完了")
+		}
+	}
+
+	r.Score = math.Round(r.Score*10) / 10
+	return r
+}
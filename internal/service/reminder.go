@@ -0,0 +1,119 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"task-recommender/internal/model"
+	"task-recommender/internal/notify"
+)
+
+// DefaultReminderLeads 期限のどれだけ前に知らせるかの既定値
+var DefaultReminderLeads = []time.Duration{24 * time.Hour, time.Hour}
+
+// DueReminders 期限が近い、または過ぎた未完了のタスクのリマインダーを期限の近い順に返す
+//
+// 期限が近いタスクは、leadsのうち当てはまる最も短いものだけを返す。
+// 例えば24時間前と1時間前を指定した場合、期限まで5時間のタスクは24時間前のリマインダーになる。
+// 日付だけの期限はその日の終わりを期限として扱う
+func (s *TaskService) DueReminders(leads []time.Duration, now time.Time) ([]model.Reminder, error) {
+	var maxLead time.Duration
+	for _, lead := range leads {
+		maxLead = max(maxLead, lead)
+	}
+
+	// 日付だけの期限は due_date より後の時刻を期限とするため、due_date での絞り込みで取りこぼすことはない
+	tasks, err := s.queryTasks(
+		"deleted_at IS NULL AND status NOT IN ('done', 'cancelled') AND due_date <= $2",
+		"due_date ASC, id ASC",
+		now.Add(maxLead),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return selectReminders(tasks, leads, now), nil
+}
+
+// selectReminders タスクごとに、期限切れか、当てはまる最も短いleadのリマインダーを選ぶ
+func selectReminders(tasks []model.Task, leads []time.Duration, now time.Time) []model.Reminder {
+	sorted := append([]time.Duration(nil), leads...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var reminders []model.Reminder
+	for _, t := range tasks {
+		if t.DueDate.IsZero() {
+			continue
+		}
+		due := t.DueAt()
+		if !due.After(now) {
+			reminders = append(reminders, model.Reminder{Kind: model.ReminderOverdue, Task: t})
+			continue
+		}
+		for _, lead := range sorted {
+			if !due.Add(-lead).After(now) {
+				reminders = append(reminders, model.Reminder{Kind: model.ReminderDueSoon, Lead: lead, Task: t})
+				break
+			}
+		}
+	}
+	sort.SliceStable(reminders, func(i, j int) bool { return reminders[i].Task.DueAt().Before(reminders[j].Task.DueAt()) })
+	return reminders
+}
+
+// SendReminders リマインダーを通知先ごとに1度だけ送り、送った件数を返す
+//
+// 送る前に送信記録を追加して確保するため、複数のサーバーから呼んでも重ねて送らない。
+// 送信に失敗した場合は記録を消し、次の呼び出しで再び送る
+func (s *TaskService) SendReminders(notifiers []notify.Notifier, leads []time.Duration, now time.Time) (int, error) {
+	reminders, err := s.DueReminders(leads, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, r := range reminders {
+		msg := notify.ReminderMessage(r, now)
+		for _, n := range notifiers {
+			id, claimed, err := s.claimReminder(r, n.Name(), now)
+			if err != nil {
+				return sent, err
+			}
+			if !claimed {
+				continue
+			}
+
+			if err := n.Notify(msg); err != nil {
+				errs = append(errs, fmt.Errorf("%s: タスクID=%d: %w", n.Name(), r.Task.ID, err))
+				if _, err := s.db.Exec("DELETE FROM reminder_log WHERE id = $1", id); err != nil {
+					return sent, err
+				}
+				continue
+			}
+			sent++
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// claimReminder リマインダーの送信記録を追加する。既に送っている場合はfalseを返す
+func (s *TaskService) claimReminder(r model.Reminder, channel string, now time.Time) (int, bool, error) {
+	var id int
+	err := s.db.QueryRow(
+		`INSERT INTO reminder_log (task_id, kind, lead_minutes, due_date, channel, sent_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT DO NOTHING
+        RETURNING id`,
+		r.Task.ID, r.Kind, int(r.Lead.Minutes()), r.Task.DueDate, channel, now,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+	"task-recommender/internal/notify"
+)
+
+func TestSelectReminders(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.Local)
+	}
+	leads := []time.Duration{time.Hour, 24 * time.Hour}
+
+	tests := []struct {
+		name string
+		due  time.Time
+		kind model.ReminderKind
+		lead time.Duration
+		none bool
+	}{
+		{name: "期限なし", none: true},
+		{name: "24時間より先", due: at(20, 10, 1), none: true},
+		{name: "ちょうど24時間前", due: at(20, 10, 0), kind: model.ReminderDueSoon, lead: 24 * time.Hour},
+		{name: "期限まで5時間", due: at(19, 15, 0), kind: model.ReminderDueSoon, lead: 24 * time.Hour},
+		{name: "期限まで30分", due: at(19, 10, 30), kind: model.ReminderDueSoon, lead: time.Hour},
+		{name: "ちょうど期限", due: at(19, 10, 0), kind: model.ReminderOverdue},
+		{name: "期限切れ", due: at(18, 9, 0), kind: model.ReminderOverdue},
+		// 日付だけの期限はその日の終わりまでを期限とする
+		{name: "今日が期限", due: at(19, 0, 0), kind: model.ReminderDueSoon, lead: 24 * time.Hour},
+		{name: "明日が期限", due: at(20, 0, 0), none: true},
+		{name: "昨日が期限", due: at(18, 0, 0), kind: model.ReminderOverdue},
+	}
+	for i, tt := range tests {
+		task := model.Task{ID: i + 1, Title: tt.name, DueDate: tt.due}
+		got := selectReminders([]model.Task{task}, leads, now)
+		if tt.none {
+			if len(got) != 0 {
+				t.Errorf("%s: reminders = %+v, want none", tt.name, got)
+			}
+			continue
+		}
+		if len(got) != 1 || got[0].Kind != tt.kind || got[0].Lead != tt.lead {
+			t.Errorf("%s: reminders = %+v, want %s lead=%s", tt.name, got, tt.kind, tt.lead)
+		}
+	}
+
+	// 今日の23時に期限がある場合は1時間前から、日付だけの期限は翌日0時の1時間前から
+	late := time.Date(2026, 10, 19, 23, 30, 0, 0, time.Local)
+	got := selectReminders([]model.Task{
+		{ID: 1, DueDate: at(19, 0, 0)},
+		{ID: 2, DueDate: at(19, 23, 0)},
+	}, leads, late)
+	if len(got) != 2 || got[0].Task.ID != 2 || got[0].Kind != model.ReminderOverdue ||
+		got[1].Task.ID != 1 || got[1].Kind != model.ReminderDueSoon || got[1].Lead != time.Hour {
+		t.Errorf("reminders = %+v, want task 2 overdue then task 1 due within an hour", got)
+	}
+}
+
+// recordingNotifier 送った通知を記録する。fail の間は送信に失敗する
+type recordingNotifier struct {
+	name string
+	fail bool
+	sent []notify.Message
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Notify(m notify.Message) error {
+	if n.fail {
+		return errors.New("送信できません")
+	}
+	n.sent = append(n.sent, m)
+	return nil
+}
+
+func TestSendRemindersOncePerLeadAndChannel(t *testing.T) {
+	s := newTestService(t)
+	now := time.Now()
+	id, err := s.CreateTask(model.Task{Title: "期限が近いタスク", Priority: 1, DueDate: now.Add(5 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leads := []time.Duration{time.Hour, 24 * time.Hour}
+	chat := &recordingNotifier{name: "chat"}
+	mail := &recordingNotifier{name: "mail", fail: true}
+	notifiers := []notify.Notifier{chat, mail}
+
+	// 失敗した送り先だけ次の呼び出しで送り直す
+	sent, err := s.SendReminders(notifiers, leads, now)
+	if err == nil || sent != 1 {
+		t.Fatalf("sent = %d, err = %v, want 1 and the mail error", sent, err)
+	}
+	mail.fail = false
+	for i, want := range []int{1, 0} {
+		sent, err := s.SendReminders(notifiers, leads, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent != want {
+			t.Errorf("call %d: sent = %d, want %d", i+2, sent, want)
+		}
+	}
+
+	// 短いleadに入ると改めて送る
+	sent, err = s.SendReminders(notifiers, leads, now.Add(4*time.Hour+30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 2 || len(chat.sent) != 2 || len(mail.sent) != 2 {
+		t.Errorf("sent = %d, chat = %d, mail = %d, want 2 each", sent, len(chat.sent), len(mail.sent))
+	}
+
+	// 期限を変えると別のリマインダーになる
+	if err := s.UpdateDueDate(id, now.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := s.SendReminders(notifiers, leads, now); err != nil || sent != 2 {
+		t.Errorf("期限変更後: sent = %d, err = %v, want 2", sent, err)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// TransitionStatus タスクの状態を遷移させる。許可されていない遷移はErrInvalidTransitionを返す
+func (s *TaskService) TransitionStatus(id int, to model.Status) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.transitionStatus(tx, id, to, nil)
+	})
+}
+
+// ReopenTask 完了または中止したタスクを未着手に戻す。完了日時はクリアされる
+func (s *TaskService) ReopenTask(id int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.transitionStatus(tx, id, model.StatusTodo, func(from model.Status) error {
+			if !from.Closed() {
+				return fmt.Errorf("%w: %s", ErrTaskNotClosed, from)
+			}
+			return nil
+		})
+	})
+}
+
+// transitionStatus トランザクション内で、遷移前の状態をcheckで検証した上で状態を遷移させる
+func (s *TaskService) transitionStatus(tx *sql.Tx, id int, to model.Status, check func(from model.Status) error) error {
+	if err := s.checkVersion(tx, id); err != nil {
+		return err
+	}
+	var from model.Status
+	err := tx.QueryRow("SELECT status FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if check != nil {
+		if err := check(from); err != nil {
+			return err
+		}
+	}
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	now := time.Now()
+	var completedAt sql.NullTime
+	if to == model.StatusDone {
+		completedAt = sql.NullTime{Time: now, Valid: true}
+	}
+
+	_, err = tx.Exec(
+		`UPDATE tasks SET status = $1, done = $2, completed_at = $3, status_changed_at = $4
+        WHERE id = $5`,
+		to, to == model.StatusDone, completedAt, now, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.recordTransition(tx, id, from, to, now); err != nil {
+		return err
+	}
+	return s.recordHistory(tx, id, model.HistoryStatusChanged, "status", from, to)
+}
+
+// ListStatusTransitions タスクの状態遷移の履歴を取得する
+func (s *TaskService) ListStatusTransitions(id int) ([]model.StatusTransition, error) {
+	rows, err := s.db.Query(`
+        SELECT id, task_id, COALESCE(from_status, ''), to_status, actor, transitioned_at
+        FROM task_status_transitions
+        WHERE task_id = $1
+        ORDER BY transitioned_at ASC, id ASC
+    `, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []model.StatusTransition
+	for rows.Next() {
+		var t model.StatusTransition
+		if err := rows.Scan(&t.ID, &t.TaskID, &t.From, &t.To, &t.Actor, &t.TransitionedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// recordTransition 状態遷移を記録する。作成時はfromを空にする
+func (s *TaskService) recordTransition(tx *sql.Tx, id int, from, to model.Status, at time.Time) error {
+	var fromStatus sql.NullString
+	if from != "" {
+		fromStatus = sql.NullString{String: string(from), Valid: true}
+	}
+	_, err := tx.Exec(
+		`INSERT INTO task_status_transitions (task_id, from_status, to_status, actor, transitioned_at)
+        VALUES ($1, $2, $3, $4, $5)`,
+		id, fromStatus, to, s.actor, at,
+	)
+	return err
+}
@@ -2,37 +2,181 @@ package service
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"task-recommender/internal/model"
+	"task-recommender/pkg/storage"
 )
 
+var (
+	ErrTaskNotFound        = errors.New("タスクが見つかりません")
+	ErrTimerAlreadyRunning = errors.New("既に計測中のタイマーがあります")
+	ErrTimerNotRunning     = errors.New("計測中のタイマーがありません")
+	ErrInvalidTransition   = errors.New("この状態には遷移できません")
+	ErrTaskNotClosed       = errors.New("完了または中止していないタスクは再開できません")
+	ErrTaskNotInTrash      = errors.New("ゴミ箱にタスクが見つかりません")
+	ErrCommentNotFound     = errors.New("コメントが見つかりません")
+	ErrNotCommentAuthor    = errors.New("コメントを編集・削除できるのは投稿者のみです")
+	ErrEmptyComment        = errors.New("コメント本文を入力してください")
+
+	ErrChecklistItemNotFound = errors.New("チェックリストの項目が見つかりません")
+	ErrEmptyChecklistItem    = errors.New("チェックリストの項目を入力してください")
+	ErrInvalidChecklistOrder = errors.New("並び順にはタスクのすべての項目を1回ずつ指定してください")
+
+	ErrImportInvalid = errors.New("インポートする内容にエラーがあります")
+
+	ErrInvalidBackup         = errors.New("バックアップファイルを読み込めません")
+	ErrUnsupportedBackup     = errors.New("対応していないバックアップの形式です")
+	ErrRestoreTargetNotEmpty = errors.New("復元先にデータがあります。上書きする場合は強制オプションを指定してください")
+
+	ErrInvalidTask     = errors.New("タスクの内容が不正です")
+	ErrVersionConflict = errors.New("タスクが他のユーザーによって更新されています")
+
+	ErrWebhookNotFound = errors.New("Webhookが見つかりません")
+	ErrInvalidWebhook  = errors.New("Webhookの設定が不正です")
+
+	ErrInvalidBulkOperation  = errors.New("一括操作の内容が不正です")
+	ErrTooManyBulkOperations = errors.New("一括操作の件数が上限を超えています")
+	ErrBulkFailed            = errors.New("失敗した操作があるため、すべての操作を取り消しました")
+
+	ErrAttachmentNotFound     = errors.New("添付ファイルが見つかりません")
+	ErrAttachmentTooLarge     = errors.New("添付ファイルのサイズが上限を超えています")
+	ErrBlobStoreNotConfigured = errors.New("添付ファイルの保存先が設定されていません")
+)
+
+// DefaultActor 操作ユーザーが指定されていない場合のユーザー名
+const DefaultActor = "anonymous"
+
 type TaskService struct {
-	db *sql.DB
+	db    *sql.DB
+	actor string
+	// expectedVersions 更新を許すタスクのバージョン。空の場合は検証しない
+	expectedVersions []int
+
+	blobs              storage.BlobStore
+	attachmentMaxBytes int64
 }
 
 func NewTaskService(db *sql.DB) *TaskService {
-	return &TaskService{db: db}
+	return &TaskService{db: db, actor: DefaultActor}
+}
+
+// WithActor 操作ユーザーを設定したサービスを返す
+func (s *TaskService) WithActor(actor string) *TaskService {
+	if actor == "" {
+		actor = DefaultActor
+	}
+	c := *s
+	c.actor = actor
+	return &c
+}
+
+// IfVersion タスクのバージョンがversionsのいずれかと一致する場合のみ更新するサービスを返す。0は無視し、指定がなければ検証しない
+func (s *TaskService) IfVersion(versions ...int) *TaskService {
+	c := *s
+	c.expectedVersions = nil
+	for _, v := range versions {
+		if v != 0 {
+			c.expectedVersions = append(c.expectedVersions, v)
+		}
+	}
+	return &c
+}
+
+// Actor 操作ユーザー名
+func (s *TaskService) Actor() string {
+	return s.actor
 }
 
 func (s *TaskService) AddTask(title, description string, priority int, dueDate time.Time, estimatedDuration int) (int, error) {
 	var id int
-	err := s.db.QueryRow(
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		id, err = s.addTask(tx, title, description, priority, dueDate, estimatedDuration)
+		return err
+	})
+	return id, err
+}
+
+// CreateTask プロジェクトや状況を含めてタスクを作成する
+func (s *TaskService) CreateTask(t model.Task) (int, error) {
+	if problems := t.Validate(); len(problems) > 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidTask, strings.Join(problems, ", "))
+	}
+	var id int
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		id, err = s.insertTask(tx, t)
+		return err
+	})
+	return id, err
+}
+
+// addTask トランザクション内でタスクを作成する
+func (s *TaskService) addTask(tx *sql.Tx, title, description string, priority int, dueDate time.Time, estimatedDuration int) (int, error) {
+	var id int
+	now := time.Now()
+	err := tx.QueryRow(
 		`INSERT INTO tasks 
-        (title, description, done, priority, due_date, estimated_duration, created_at) 
-        VALUES ($1, $2, false, $3, $4, $5, $6) 
+        (title, description, done, status, priority, due_date, estimated_duration, created_at, status_changed_at) 
+        VALUES ($1, $2, false, $3, $4, $5, $6, $7, $7) 
         RETURNING id`,
-		title, description, priority, dueDate, estimatedDuration, time.Now(),
+		title, description, model.StatusTodo, priority, dueDate, estimatedDuration, now,
 	).Scan(&id)
-	return id, err
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.recordTransition(tx, id, "", model.StatusTodo, now); err != nil {
+		return 0, err
+	}
+	return id, s.recordHistory(tx, id, model.HistoryCreated, "", nil, map[string]interface{}{
+		"title":              title,
+		"description":        description,
+		"priority":           priority,
+		"due_date":           dueDate,
+		"estimated_duration": estimatedDuration,
+	})
+}
+
+// GetTask ゴミ箱にないタスクを1件取得する
+func (s *TaskService) GetTask(id int) (model.Task, error) {
+	tasks, err := s.queryTasks("id = $2 AND deleted_at IS NULL", "id", id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if len(tasks) == 0 {
+		return model.Task{}, ErrTaskNotFound
+	}
+	return tasks[0], nil
 }
 
 func (s *TaskService) ListTasks() ([]model.Task, error) {
+	return s.queryTasks("deleted_at IS NULL", "priority DESC, due_date ASC")
+}
+
+// queryTasks 条件に一致するタスクを取得する。$1は現在日時で予約済みのため、追加の引数は$2から
+func (s *TaskService) queryTasks(where, orderBy string, args ...interface{}) ([]model.Task, error) {
 	rows, err := s.db.Query(`
-        SELECT id, title, description, done, priority, due_date, estimated_duration, created_at, completed_at 
+        SELECT id, COALESCE(external_id, ''), title, description, status, status_changed_at, priority, due_date, estimated_duration,
+            project, contexts, created_at, completed_at, deleted_at, version,
+            COALESCE((
+                SELECT FLOOR(SUM(EXTRACT(EPOCH FROM (COALESCE(e.stopped_at, $1) - e.started_at))) / 60)
+                FROM time_entries e
+                WHERE e.task_id = tasks.id
+            ), 0)::INT AS tracked_duration,
+            (SELECT COUNT(*) FROM checklist_items c WHERE c.task_id = tasks.id AND c.done) AS checklist_done,
+            (SELECT COUNT(*) FROM checklist_items c WHERE c.task_id = tasks.id) AS checklist_total
         FROM tasks 
-        ORDER BY priority DESC, due_date ASC
-    `)
+        WHERE `+where+`
+        ORDER BY `+orderBy,
+		append([]interface{}{time.Now()}, args...)...,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -43,16 +187,20 @@ func (s *TaskService) ListTasks() ([]model.Task, error) {
 		var t model.Task
 		var completedAt sql.NullTime
 		var dueDate sql.NullTime
+		var deletedAt sql.NullTime
 
 		err := rows.Scan(
-			&t.ID, &t.Title, &t.Description, &t.Done,
+			&t.ID, &t.ExternalID, &t.Title, &t.Description, &t.Status, &t.StatusChangedAt,
 			&t.Priority, &dueDate, &t.EstimatedDuration,
-			&t.CreatedAt, &completedAt,
+			&t.Project, pq.Array(&t.Contexts),
+			&t.CreatedAt, &completedAt, &deletedAt, &t.Version, &t.TrackedDuration,
+			&t.Checklist.Done, &t.Checklist.Total,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		t.Done = t.Status == model.StatusDone
 		if completedAt.Valid {
 			t.CompletedAt = completedAt.Time
 		}
@@ -61,44 +209,138 @@ func (s *TaskService) ListTasks() ([]model.Task, error) {
 			t.DueDate = dueDate.Time
 		}
 
+		if deletedAt.Valid {
+			t.DeletedAt = deletedAt.Time
+		}
+
 		tasks = append(tasks, t)
 	}
-	return tasks, nil
+	return tasks, rows.Err()
 }
 
 func (s *TaskService) CompleteTask(id int) error {
-	_, err := s.db.Exec(
-		"UPDATE tasks SET done = true, completed_at = $1 WHERE id = $2",
-		time.Now(), id,
-	)
-	return err
+	return s.TransitionStatus(id, model.StatusDone)
 }
 
+// DeleteTask タスクをゴミ箱に移動する。完全な削除はPurgeTrashで行う
 func (s *TaskService) DeleteTask(id int) error {
-	_, err := s.db.Exec("DELETE FROM tasks WHERE id = $1", id)
-	return err
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.deleteTask(tx, id)
+	})
 }
 
-func (s *TaskService) UpdatePriority(id, priority int) error {
-	_, err := s.db.Exec(
-		"UPDATE tasks SET priority = $1 WHERE id = $2",
-		priority, id,
+// deleteTask トランザクション内でタスクをゴミ箱に移動する
+func (s *TaskService) deleteTask(tx *sql.Tx, id int) error {
+	if err := s.checkVersion(tx, id); err != nil {
+		return err
+	}
+	now := time.Now()
+	res, err := tx.Exec(
+		"UPDATE tasks SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL",
+		now, id,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res, ErrTaskNotFound); err != nil {
+		return err
+	}
+	return s.recordHistory(tx, id, model.HistoryDeleted, "deleted_at", nil, now)
+}
+
+func (s *TaskService) UpdatePriority(id, priority int) error {
+	return s.updateField(id, "priority", priority)
 }
 
 func (s *TaskService) UpdateDueDate(id int, dueDate time.Time) error {
-	_, err := s.db.Exec(
-		"UPDATE tasks SET due_date = $1 WHERE id = $2",
-		dueDate, id,
-	)
-	return err
+	return s.updateField(id, "due_date", dueDate)
 }
 
 func (s *TaskService) UpdateEstimatedDuration(id, duration int) error {
-	_, err := s.db.Exec(
-		"UPDATE tasks SET estimated_duration = $1 WHERE id = $2",
-		duration, id,
-	)
-	return err
+	return s.updateField(id, "estimated_duration", duration)
+}
+
+// SetExternalID 外部IDを設定する。同期の突き合わせに使う
+func (s *TaskService) SetExternalID(id int, externalID string) error {
+	return s.updateField(id, "external_id", nullString(externalID))
+}
+
+// updateField タスクの1項目を更新し、変更前後の値を履歴に記録する
+func (s *TaskService) updateField(id int, column string, value interface{}) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.setField(tx, id, column, value)
+	})
+}
+
+// setField トランザクション内でタスクの1項目を更新し、変更前後の値を履歴に記録する
+func (s *TaskService) setField(tx *sql.Tx, id int, column string, value interface{}) error {
+	if err := s.checkVersion(tx, id); err != nil {
+		return err
+	}
+	var old interface{}
+	err := tx.QueryRow(
+		"SELECT "+column+" FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+		id,
+	).Scan(&old)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE tasks SET "+column+" = $1 WHERE id = $2", value, id)
+	if err != nil {
+		return err
+	}
+	return s.recordHistory(tx, id, model.HistoryUpdated, column, old, value)
+}
+
+// checkVersion IfVersionで指定したバージョンと現在のバージョンを比べ、いずれとも異なればErrVersionConflictを返す
+//
+// 行をロックするため、同じトランザクション内の更新までに他から書き換えられることはない
+func (s *TaskService) checkVersion(tx *sql.Tx, id int) error {
+	if len(s.expectedVersions) == 0 {
+		return nil
+	}
+	var version int
+	err := tx.QueryRow("SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+	for _, v := range s.expectedVersions {
+		if v == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 指定したバージョン %v, 現在のバージョン %d", ErrVersionConflict, s.expectedVersions, version)
+}
+
+// withTx トランザクション内でfnを実行し、エラーがなければコミットする
+func (s *TaskService) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// requireAffected 更新件数が0の場合にerrを返すヘルパー関数
+func requireAffected(res sql.Result, err error) error {
+	n, rerr := res.RowsAffected()
+	if rerr != nil {
+		return rerr
+	}
+	if n == 0 {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+)
+
+// StartTimer タスクの時間計測を開始する。ユーザーごとに計測中のタイマーは1つまで
+func (s *TaskService) StartTimer(taskID int) (model.TimeEntry, error) {
+	if err := s.ensureTaskExists(taskID); err != nil {
+		return model.TimeEntry{}, err
+	}
+
+	var running int
+	err := s.db.QueryRow(
+		"SELECT id FROM time_entries WHERE user_name = $1 AND stopped_at IS NULL",
+		s.actor,
+	).Scan(&running)
+	if err == nil {
+		return model.TimeEntry{}, ErrTimerAlreadyRunning
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.TimeEntry{}, err
+	}
+
+	e := model.TimeEntry{TaskID: taskID, User: s.actor, StartedAt: time.Now()}
+	err = s.db.QueryRow(
+		`INSERT INTO time_entries (task_id, user_name, started_at)
+        VALUES ($1, $2, $3)
+        RETURNING id`,
+		e.TaskID, e.User, e.StartedAt,
+	).Scan(&e.ID)
+	if err != nil {
+		// 同時に開始された場合は部分ユニークインデックスで弾かれる
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.TimeEntry{}, ErrTimerAlreadyRunning
+		}
+		return model.TimeEntry{}, err
+	}
+	return e, nil
+}
+
+// StopTimer タスクの計測中のタイマーを停止する
+func (s *TaskService) StopTimer(taskID int) (model.TimeEntry, error) {
+	e := model.TimeEntry{TaskID: taskID, User: s.actor, StoppedAt: time.Now()}
+	err := s.db.QueryRow(
+		`UPDATE time_entries SET stopped_at = $1
+        WHERE task_id = $2 AND user_name = $3 AND stopped_at IS NULL
+        RETURNING id, started_at`,
+		e.StoppedAt, taskID, s.actor,
+	).Scan(&e.ID, &e.StartedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.TimeEntry{}, ErrTimerNotRunning
+	}
+	return e, err
+}
+
+// ListTimeEntries タスクの時間記録一覧を取得する
+func (s *TaskService) ListTimeEntries(taskID int) ([]model.TimeEntry, error) {
+	rows, err := s.db.Query(`
+        SELECT id, task_id, user_name, started_at, stopped_at
+        FROM time_entries
+        WHERE task_id = $1
+        ORDER BY started_at ASC
+    `, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.TimeEntry
+	for rows.Next() {
+		var e model.TimeEntry
+		var stoppedAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.User, &e.StartedAt, &stoppedAt); err != nil {
+			return nil, err
+		}
+		if stoppedAt.Valid {
+			e.StoppedAt = stoppedAt.Time
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// ensureTaskExists タスクが存在しなければErrTaskNotFoundを返す
+func (s *TaskService) ensureTaskExists(id int) error {
+	var exists bool
+	err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTaskNotFound
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+)
+
+// ListTrash ゴミ箱のタスクを削除日時の新しい順に取得する
+func (s *TaskService) ListTrash() ([]model.Task, error) {
+	return s.queryTasks("deleted_at IS NOT NULL", "deleted_at DESC")
+}
+
+// RestoreTask ゴミ箱のタスクを元に戻す
+func (s *TaskService) RestoreTask(id int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		var deletedAt time.Time
+		err := tx.QueryRow(
+			"SELECT deleted_at FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE",
+			id,
+		).Scan(&deletedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTaskNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("UPDATE tasks SET deleted_at = NULL WHERE id = $1", id)
+		if err != nil {
+			return err
+		}
+		return s.recordHistory(tx, id, model.HistoryRestored, "deleted_at", deletedAt, nil)
+	})
+}
+
+// PurgeTrash 指定日時より前にゴミ箱に移動したタスクを完全に削除し、削除件数を返す
+//
+// 添付ファイルの行はタスクと一緒に削除されるため、ストレージ上のデータはコミット後に削除する
+func (s *TaskService) PurgeTrash(before time.Time) (int64, error) {
+	var purged int64
+	var keys []string
+	err := s.withTx(func(tx *sql.Tx) error {
+		var ids []int64
+		err := tx.QueryRow(
+			"SELECT COALESCE(array_agg(id), '{}') FROM (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < $1 FOR UPDATE) t",
+			before,
+		).Scan(pq.Array(&ids))
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.QueryRow(
+			"SELECT COALESCE(array_agg(storage_key), '{}') FROM task_attachments WHERE task_id = ANY($1)",
+			pq.Array(ids),
+		).Scan(pq.Array(&keys))
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query("DELETE FROM tasks WHERE id = ANY($1) RETURNING id, title", pq.Array(ids))
+		if err != nil {
+			return err
+		}
+
+		type purgedTask struct {
+			id    int
+			title string
+		}
+		var tasks []purgedTask
+		for rows.Next() {
+			var t purgedTask
+			if err := rows.Scan(&t.id, &t.title); err != nil {
+				rows.Close()
+				return err
+			}
+			tasks = append(tasks, t)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, t := range tasks {
+			if err := s.recordHistory(tx, t.id, model.HistoryPurged, "", t.title, nil); err != nil {
+				return err
+			}
+		}
+		purged = int64(len(tasks))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, s.deleteBlobs(keys)
+}
+
+// deleteBlobs ストレージ上のデータを削除する。失敗したキーがあっても残りは削除を続ける
+func (s *TaskService) deleteBlobs(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if s.blobs == nil {
+		return ErrBlobStoreNotConfigured
+	}
+	var errs []error
+	for _, key := range keys {
+		if err := s.blobs.Delete(key); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"task-recommender/pkg/storage"
+)
+
+func TestPurgeTrashDeletesAttachmentBlobs(t *testing.T) {
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestService(t).WithBlobStore(blobs, 0)
+
+	purgedID := mustCreateTask(t, s, "削除するタスク")
+	keptID := mustCreateTask(t, s, "残すタスク")
+	purged, err := s.AddAttachment(purgedID, "memo.txt", strings.NewReader("purged"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := s.AddAttachment(keptID, "memo.txt", strings.NewReader("kept"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteTask(purgedID); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.PurgeTrash(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("purged = %d, want 1", n)
+	}
+
+	if _, err := blobs.Get(purged.StorageKey); !errors.Is(err, storage.ErrBlobNotFound) {
+		t.Errorf("Get(purged) error = %v, want ErrBlobNotFound", err)
+	}
+	rc, err := blobs.Get(kept.StorageKey)
+	if err != nil {
+		t.Fatalf("Get(kept) error = %v", err)
+	}
+	rc.Close()
+}
+
+func TestPurgeTrashKeepsRecentlyDeletedTasks(t *testing.T) {
+	s := newTestService(t)
+	id := mustCreateTask(t, s, "最近削除したタスク")
+	if err := s.DeleteTask(id); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.PurgeTrash(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("purged = %d, want 0", n)
+	}
+	trash, err := s.ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 1 || trash[0].ID != id {
+		t.Fatalf("trash = %+v, want task %d", trash, id)
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/lib/pq"
+
+	"task-recommender/internal/model"
+	"task-recommender/internal/webhook"
+)
+
+const (
+	// MaxWebhookAttempts 送信を諦めるまでの試行回数
+	MaxWebhookAttempts = 8
+
+	// 再試行の間隔。失敗するたびに倍にし、上限で打ち止めにする
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = time.Hour
+
+	// webhookLease 送信中の配信を他のプロセスが重ねて送らないよう、次の試行日時を先送りする時間
+	webhookLease = 5 * time.Minute
+)
+
+// CreateWebhook Webhookを登録する。secretが空の場合は生成し、登録結果にのみ含めて返す
+func (s *TaskService) CreateWebhook(rawURL, secret string, events []model.EventType) (model.Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return model.Webhook{}, fmt.Errorf("%w: URLはhttpまたはhttpsで指定してください", ErrInvalidWebhook)
+	}
+	eventNames := make([]string, 0, len(events))
+	for _, e := range events {
+		if !e.Valid() {
+			return model.Webhook{}, fmt.Errorf("%w: 不明なイベント %s", ErrInvalidWebhook, e)
+		}
+		eventNames = append(eventNames, string(e))
+	}
+	if secret == "" {
+		b := make([]byte, 24)
+		if _, err := rand.Read(b); err != nil {
+			return model.Webhook{}, err
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	w := model.Webhook{URL: rawURL, Secret: secret, Events: events, CreatedBy: s.actor, CreatedAt: time.Now()}
+	if w.Events == nil {
+		w.Events = []model.EventType{}
+	}
+	err = s.db.QueryRow(
+		`INSERT INTO webhooks (url, secret, events, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id`,
+		w.URL, w.Secret, pq.Array(eventNames), w.CreatedBy, w.CreatedAt,
+	).Scan(&w.ID)
+	return w, err
+}
+
+// ListWebhooks 登録されているWebhookを取得する。秘密鍵は含めない
+func (s *TaskService) ListWebhooks() ([]model.Webhook, error) {
+	rows, err := s.db.Query("SELECT id, url, events, created_by, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []model.Webhook{}
+	for rows.Next() {
+		var w model.Webhook
+		var events []string
+		if err := rows.Scan(&w.ID, &w.URL, pq.Array(&events), &w.CreatedBy, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		w.Events = make([]model.EventType, len(events))
+		for i, e := range events {
+			w.Events[i] = model.EventType(e)
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook Webhookの登録を解除する。未送信の配信も破棄する
+func (s *TaskService) DeleteWebhook(id int) error {
+	res, err := s.db.Exec("DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, ErrWebhookNotFound)
+}
+
+// ListWebhookDeliveries Webhookの送信記録を新しい順にlimit件まで取得する
+func (s *TaskService) ListWebhookDeliveries(webhookID, limit int) ([]model.WebhookDelivery, error) {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1)", webhookID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrWebhookNotFound
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.Query(`
+        SELECT d.id, d.webhook_id, d.event_type, d.status, d.attempts, d.next_attempt_at,
+            COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''), d.delivered_at,
+            h.id, h.task_id, COALESCE(t.project, ''), COALESCE(h.field, ''), COALESCE(h.old_value, ''), COALESCE(h.new_value, ''), h.actor, h.created_at
+        FROM webhook_deliveries d
+        JOIN task_history h ON h.id = d.history_id
+        LEFT JOIN tasks t ON t.id = h.task_id
+        WHERE d.webhook_id = $1
+        ORDER BY d.id DESC
+        LIMIT $2
+    `, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		var d model.WebhookDelivery
+		var deliveredAt sql.NullTime
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.Event.Type, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastStatusCode, &d.LastError, &deliveredAt,
+			&d.Event.ID, &d.Event.TaskID, &d.Event.Project, &d.Event.Field, &d.Event.OldValue, &d.Event.NewValue, &d.Event.Actor, &d.Event.OccurredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = deliveredAt.Time
+		}
+		if d.Status != model.DeliveryPending {
+			d.NextAttemptAt = time.Time{}
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// DeliverWebhooks 送信日時を過ぎた配信を最大limit件送信し、成功した件数を返す
+//
+// 失敗した配信は試行回数に応じて間隔を空けて再試行し、MaxWebhookAttempts回で諦める。
+// 複数のプロセスから呼んでも同じ配信を重ねて送らないよう、送信前に配信を確保する
+func (s *TaskService) DeliverWebhooks(sender webhook.Sender, limit int) (int, error) {
+	type claimed struct {
+		delivery model.WebhookDelivery
+		url      string
+		secret   string
+	}
+
+	var batch []claimed
+	err := s.withTx(func(tx *sql.Tx) error {
+		now := time.Now()
+		rows, err := tx.Query(`
+            SELECT d.id, d.webhook_id, d.event_type, d.attempts, w.url, w.secret,
+                h.id, h.task_id, COALESCE(t.project, ''), COALESCE(h.field, ''), COALESCE(h.old_value, ''), COALESCE(h.new_value, ''), h.actor, h.created_at
+            FROM webhook_deliveries d
+            JOIN webhooks w ON w.id = d.webhook_id
+            JOIN task_history h ON h.id = d.history_id
+            LEFT JOIN tasks t ON t.id = h.task_id
+            WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+            ORDER BY d.next_attempt_at, d.id
+            LIMIT $2
+            FOR UPDATE OF d SKIP LOCKED
+        `, now, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int64
+		for rows.Next() {
+			var c claimed
+			d := &c.delivery
+			err := rows.Scan(
+				&d.ID, &d.WebhookID, &d.Event.Type, &d.Attempts, &c.url, &c.secret,
+				&d.Event.ID, &d.Event.TaskID, &d.Event.Project, &d.Event.Field, &d.Event.OldValue, &d.Event.NewValue, &d.Event.Actor, &d.Event.OccurredAt,
+			)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, c)
+			ids = append(ids, d.ID)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		_, err = tx.Exec(
+			"UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = ANY($2)",
+			now.Add(webhookLease), pq.Array(ids),
+		)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, c := range batch {
+		d := c.delivery
+		body, err := json.Marshal(d.Event)
+		if err != nil {
+			return delivered, err
+		}
+
+		statusCode, sendErr := sender.Send(c.url, c.secret, webhook.Message{
+			DeliveryID: d.ID,
+			Event:      string(d.Event.Type),
+			Body:       body,
+		})
+		if err := s.recordDeliveryAttempt(d, statusCode, sendErr); err != nil {
+			return delivered, err
+		}
+		if sendErr == nil {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// recordDeliveryAttempt 送信の結果を記録し、失敗した場合は次の試行日時を決める
+func (s *TaskService) recordDeliveryAttempt(d model.WebhookDelivery, statusCode int, sendErr error) error {
+	now := time.Now()
+	attempts := d.Attempts + 1
+
+	var code sql.NullInt64
+	if statusCode != 0 {
+		code = sql.NullInt64{Int64: int64(statusCode), Valid: true}
+	}
+
+	if sendErr == nil {
+		_, err := s.db.Exec(
+			`UPDATE webhook_deliveries
+            SET status = $1, attempts = $2, last_status_code = $3, last_error = NULL, delivered_at = $4
+            WHERE id = $5`,
+			model.DeliverySucceeded, attempts, code, now, d.ID,
+		)
+		return err
+	}
+
+	status := model.DeliveryPending
+	if attempts >= MaxWebhookAttempts {
+		status = model.DeliveryFailed
+	}
+	_, err := s.db.Exec(
+		`UPDATE webhook_deliveries
+        SET status = $1, attempts = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5
+        WHERE id = $6`,
+		status, attempts, code, sendErr.Error(), now.Add(webhook.Backoff(attempts, webhookRetryBase, webhookRetryMax)), d.ID,
+	)
+	return err
+}
+
+// enqueueWebhookDeliveries イベントを購読しているWebhookへの配信を登録する
+func (s *TaskService) enqueueWebhookDeliveries(tx *sql.Tx, historyID int64, eventType model.EventType, now time.Time) error {
+	_, err := tx.Exec(
+		`INSERT INTO webhook_deliveries (webhook_id, history_id, event_type, status, next_attempt_at, created_at)
+        SELECT id, $1, $2::text, $3, $4, $4 FROM webhooks
+        WHERE cardinality(events) = 0 OR $2::text = ANY(events)`,
+		historyID, eventType, model.DeliveryPending, now,
+	)
+	return err
+}
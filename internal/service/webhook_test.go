@@ -0,0 +1,151 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+	"task-recommender/internal/webhook"
+)
+
+func TestDeliverWebhooksSignsPayload(t *testing.T) {
+	s := newTestService(t)
+	const secret = "s3cr3t"
+
+	var mu sync.Mutex
+	var events []model.TaskEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header, body, time.Minute, time.Now()); err != nil {
+			t.Errorf("Verify: %v", err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var e model.TaskEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Errorf("body: %v", err)
+		}
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	hook, err := s.CreateWebhook(srv.URL, secret, []model.EventType{model.EventTaskCreated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := mustCreateTask(t, s, "通知するタスク")
+
+	n, err := s.DeliverWebhooks(webhook.NewClient(time.Second), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("delivered = %d, want 1", n)
+	}
+	if len(events) != 1 || events[0].Type != model.EventTaskCreated || events[0].TaskID != id {
+		t.Errorf("received = %+v", events)
+	}
+
+	deliveries, err := s.ListWebhookDeliveries(hook.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != model.DeliverySucceeded || deliveries[0].Attempts != 1 {
+		t.Errorf("deliveries = %+v", deliveries)
+	}
+}
+
+func TestDeliverWebhooksBacksOffOnFailure(t *testing.T) {
+	s := newTestService(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	hook, err := s.CreateWebhook(srv.URL, "secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustCreateTask(t, s, "失敗し続けるタスク")
+	sender := webhook.NewClient(time.Second)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		before := time.Now()
+		n, err := s.DeliverWebhooks(sender, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after := time.Now()
+		if n != 0 {
+			t.Fatalf("attempt %d: delivered = %d, want 0", attempt, n)
+		}
+
+		deliveries, err := s.ListWebhookDeliveries(hook.ID, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deliveries) != 1 {
+			t.Fatalf("deliveries = %d, want 1", len(deliveries))
+		}
+		d := deliveries[0]
+		if d.Status != model.DeliveryPending || d.Attempts != attempt || d.LastStatusCode != http.StatusInternalServerError {
+			t.Fatalf("attempt %d: delivery = %+v", attempt, d)
+		}
+		wait := webhook.Backoff(attempt, webhookRetryBase, webhookRetryMax)
+		earliest, latest := wallClock(before.Add(wait)).Truncate(time.Microsecond), wallClock(after.Add(wait))
+		if d.NextAttemptAt.Before(earliest) || d.NextAttemptAt.After(latest) {
+			t.Errorf("attempt %d: next_attempt_at = %v, want %v after the attempt", attempt, d.NextAttemptAt, wait)
+		}
+
+		// 待ち時間の間は送信しない
+		if n, err := s.DeliverWebhooks(sender, 10); err != nil || n != 0 {
+			t.Fatalf("early retry: delivered = %d, err = %v", n, err)
+		}
+		if again, _ := s.ListWebhookDeliveries(hook.ID, 10); again[0].Attempts != attempt {
+			t.Fatalf("attempt %d: retried before next_attempt_at", attempt)
+		}
+		if _, err := s.db.Exec("UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2", time.Now(), d.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDeliverWebhooksGivesUpAfterMaxAttempts(t *testing.T) {
+	s := newTestService(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	hook, err := s.CreateWebhook(srv.URL, "secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustCreateTask(t, s, "失敗し続けるタスク")
+	if _, err := s.db.Exec("UPDATE webhook_deliveries SET attempts = $1", MaxWebhookAttempts-1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DeliverWebhooks(webhook.NewClient(time.Second), 10); err != nil {
+		t.Fatal(err)
+	}
+	deliveries, err := s.ListWebhookDeliveries(hook.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := deliveries[0]; d.Status != model.DeliveryFailed || d.Attempts != MaxWebhookAttempts {
+		t.Errorf("delivery = %+v, want failed after %d attempts", d, MaxWebhookAttempts)
+	}
+}
+
+// wallClock TIMESTAMP 列から読み出した日時と比べられるよう、ローカル時刻の表記のままUTCとして扱う
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
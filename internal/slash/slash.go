@@ -0,0 +1,164 @@
+// Package slash Slack互換のスラッシュコマンドの署名検証とコマンドの解釈を行う
+//
+// 署名は次のとおり計算する（https://api.slack.com/authentication/verifying-requests-from-slack）
+//
+//	X-Slack-Request-Timestamp  送信日時のUNIX時刻（秒）
+//	X-Slack-Signature          "v0=" + HMAC-SHA256(署名用の秘密鍵, "v0:" + タイムスタンプ + ":" + 本文) の16進表記
+package slash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderTimestamp = "X-Slack-Request-Timestamp"
+	HeaderSignature = "X-Slack-Signature"
+
+	// MaxClockSkew 送信日時と受信日時のずれの許容範囲。これより古いリクエストは再送攻撃とみなす
+	MaxClockSkew = 5 * time.Minute
+)
+
+// ErrInvalidSignature 署名が一致しない、またはタイムスタンプが古すぎる
+var ErrInvalidSignature = errors.New("slash: invalid signature")
+
+// Sign 本文に署名する
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify リクエストのヘッダーと本文の署名を検証する
+func Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(HeaderTimestamp)
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Action サブコマンドの種類
+type Action string
+
+const (
+	ActionAdd  Action = "add"
+	ActionNext Action = "next"
+	ActionDone Action = "done"
+	ActionHelp Action = "help"
+)
+
+// Command 解釈したコマンド
+type Command struct {
+	Action Action
+	// add: タスクのタイトル、優先度 (p1〜p3)、期限日 (due:YYYY-MM-DD)
+	Title    string
+	Priority int
+	DueDate  time.Time
+	// next: 表示する件数
+	Limit int
+	// done: 完了にするタスクのID
+	TaskID int
+}
+
+// Parse コマンドの引数を解釈する。空の場合はhelpになる
+//
+//	add 牛乳を買う p3 due:2026-10-20
+//	next [件数]
+//	done 12
+func Parse(text string) (Command, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return Command{Action: ActionHelp}, nil
+	}
+
+	cmd := Command{Action: Action(strings.ToLower(fields[0]))}
+	args := fields[1:]
+	switch cmd.Action {
+	case ActionAdd:
+		cmd.Priority = 2
+		var title []string
+		for _, arg := range args {
+			switch {
+			case len(arg) == 2 && (arg[0] == 'p' || arg[0] == 'P') && arg[1] >= '1' && arg[1] <= '3':
+				cmd.Priority = int(arg[1] - '0')
+			case strings.HasPrefix(arg, "due:"):
+				dueDate, err := time.Parse("2006-01-02", strings.TrimPrefix(arg, "due:"))
+				if err != nil {
+					return cmd, fmt.Errorf("期限日の形式が不正です。due:YYYY-MM-DD の形式で指定してください")
+				}
+				cmd.DueDate = dueDate
+			default:
+				title = append(title, arg)
+			}
+		}
+		cmd.Title = strings.Join(title, " ")
+		if cmd.Title == "" {
+			return cmd, fmt.Errorf("タイトルを指定してください")
+		}
+	case ActionNext:
+		if len(args) > 0 {
+			limit, err := strconv.Atoi(args[0])
+			if err != nil || limit < 1 {
+				return cmd, fmt.Errorf("件数は1以上の数値で指定してください")
+			}
+			cmd.Limit = limit
+		}
+	case ActionDone:
+		if len(args) != 1 {
+			return cmd, fmt.Errorf("完了にするタスクのIDを1つ指定してください")
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || id < 1 {
+			return cmd, fmt.Errorf("タスクIDが不正です: %s", args[0])
+		}
+		cmd.TaskID = id
+	case ActionHelp:
+	default:
+		return cmd, fmt.Errorf("不明なコマンドです: %s", fields[0])
+	}
+	return cmd, nil
+}
+
+// Response スラッシュコマンドへの応答
+type Response struct {
+	// ResponseType "ephemeral"（実行した人にだけ表示）または "in_channel"（チャンネル全体に表示）
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// Ephemeral 実行した人にだけ表示する応答
+func Ephemeral(format string, args ...interface{}) Response {
+	return Response{ResponseType: "ephemeral", Text: fmt.Sprintf(format, args...)}
+}
+
+// InChannel チャンネル全体に表示する応答
+func InChannel(format string, args ...interface{}) Response {
+	return Response{ResponseType: "in_channel", Text: fmt.Sprintf(format, args...)}
+}
+
+// Help 使い方
+func Help(command string) Response {
+	if command == "" {
+		command = "/task"
+	}
+	return Ephemeral("使い方:\n"+
+		"`%[1]s add 牛乳を買う p3 due:2026-10-20` タスクを追加（優先度 p1〜p3、期限日 due:YYYY-MM-DD は省略可）\n"+
+		"`%[1]s next [件数]` おすすめのタスクを表示\n"+
+		"`%[1]s done 12` タスクを完了にする", command)
+}
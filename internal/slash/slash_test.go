@@ -0,0 +1,120 @@
+package slash
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Slackのドキュメント (Verifying requests from Slack) の例
+const (
+	docsSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	docsTimestamp = "1531420618"
+	docsBody      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	docsSignature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+)
+
+func signedHeader(timestamp, signature string) http.Header {
+	h := http.Header{}
+	h.Set(HeaderTimestamp, timestamp)
+	h.Set(HeaderSignature, signature)
+	return h
+}
+
+func TestSignKnownAnswer(t *testing.T) {
+	if got := Sign(docsSecret, docsTimestamp, []byte(docsBody)); got != docsSignature {
+		t.Errorf("Sign = %s, want %s", got, docsSignature)
+	}
+	now := time.Unix(1531420618, 0).Add(time.Minute)
+	if err := Verify(docsSecret, signedHeader(docsTimestamp, docsSignature), []byte(docsBody), now); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "secret"
+	body := []byte("command=%2Ftask&text=next")
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{"正しい署名", signedHeader(ts(0), Sign(secret, ts(0), body)), body, true},
+		{"許容範囲内の古さ", signedHeader(ts(-MaxClockSkew), Sign(secret, ts(-MaxClockSkew), body)), body, true},
+		{"本文の改ざん", signedHeader(ts(0), Sign(secret, ts(0), body)), []byte("command=%2Ftask&text=done+1"), false},
+		{"別の秘密鍵", signedHeader(ts(0), Sign("other", ts(0), body)), body, false},
+		{"署名とタイムスタンプの食い違い", signedHeader(ts(-time.Second), Sign(secret, ts(0), body)), body, false},
+		{"古いタイムスタンプ", signedHeader(ts(-MaxClockSkew-time.Second), Sign(secret, ts(-MaxClockSkew-time.Second), body)), body, false},
+		{"未来のタイムスタンプ", signedHeader(ts(MaxClockSkew+time.Second), Sign(secret, ts(MaxClockSkew+time.Second), body)), body, false},
+		{"数値でないタイムスタンプ", signedHeader("abc", Sign(secret, "abc", body)), body, false},
+		{"ヘッダーなし", http.Header{}, body, false},
+	}
+	for _, tt := range tests {
+		err := Verify(secret, tt.header, tt.body, now)
+		if tt.ok && err != nil {
+			t.Errorf("%s: Verify = %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: Verify = %v, want ErrInvalidSignature", tt.name, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text string
+		want Command
+	}{
+		{"", Command{Action: ActionHelp}},
+		{"  ", Command{Action: ActionHelp}},
+		{"help", Command{Action: ActionHelp}},
+		{"add 牛乳を買う", Command{Action: ActionAdd, Title: "牛乳を買う", Priority: 2}},
+		{"add 牛乳を買う p3 due:2026-10-20", Command{Action: ActionAdd, Title: "牛乳を買う", Priority: 3, DueDate: due}},
+		{"ADD P1 週報 を書く", Command{Action: ActionAdd, Title: "週報 を書く", Priority: 1}},
+		// p4 や p10 は優先度ではなくタイトルの一部
+		{"add p4 p10 を調べる", Command{Action: ActionAdd, Title: "p4 p10 を調べる", Priority: 2}},
+		{"next", Command{Action: ActionNext}},
+		{"next 5", Command{Action: ActionNext, Limit: 5}},
+		{"done 12", Command{Action: ActionDone, TaskID: 12}},
+		{"done #12", Command{Action: ActionDone, TaskID: 12}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.text)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.text, err)
+			continue
+		}
+		if got.Action != tt.want.Action || got.Title != tt.want.Title || got.Priority != tt.want.Priority ||
+			!got.DueDate.Equal(tt.want.DueDate) || got.Limit != tt.want.Limit || got.TaskID != tt.want.TaskID {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"add",
+		"add p1",
+		"add 牛乳 due:2026-13-01",
+		"add 牛乳 due:10/20",
+		"add 牛乳 due:",
+		"next 0",
+		"next abc",
+		"done",
+		"done 1 2",
+		"done #",
+		"done -3",
+		"remove 1",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q) がエラーになりません", text)
+		}
+	}
+}
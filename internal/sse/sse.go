@@ -0,0 +1,127 @@
+// Package sse Server-Sent Events (text/event-stream) の書き出しと読み込みを行う
+//
+// 仕様: https://html.spec.whatwg.org/multipage/server-sent-events.html
+package sse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType イベントストリームのContent-Type
+const ContentType = "text/event-stream"
+
+// Event ストリーム上の1件のイベント
+type Event struct {
+	// ID 再接続時に Last-Event-ID として送り返される値
+	ID string
+	// Type イベント名。空の場合は "message"
+	Type string
+	// Data イベントの本文。改行を含む場合は複数のdata行に分けて送る
+	Data string
+}
+
+// Write イベントを1件書き出す
+func Write(w io.Writer, ev Event) error {
+	var b strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", ev.ID)
+	}
+	if ev.Type != "" {
+		fmt.Fprintf(&b, "event: %s\n", ev.Type)
+	}
+	for _, line := range strings.Split(ev.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteRetry 切断時にクライアントが再接続するまでの待ち時間を指定する
+func WriteRetry(w io.Writer, d time.Duration) error {
+	_, err := fmt.Fprintf(w, "retry: %d\n\n", d.Milliseconds())
+	return err
+}
+
+// WriteComment コメント行を書き出す。接続を維持するためのハートビートに使う
+func WriteComment(w io.Writer, text string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", text)
+	return err
+}
+
+// Reader イベントストリームを読み込む
+type Reader struct {
+	scanner *bufio.Scanner
+	lastID  string
+	retry   time.Duration
+}
+
+// NewReader rからイベントを読み込むReaderを作成
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &Reader{scanner: scanner}
+}
+
+// LastEventID 最後に受け取ったイベントID。再接続時に Last-Event-ID として送る
+func (r *Reader) LastEventID() string {
+	return r.lastID
+}
+
+// Retry サーバーが指定した再接続までの待ち時間。指定がない場合は0
+func (r *Reader) Retry() time.Duration {
+	return r.retry
+}
+
+// Next 次のイベントを読み込む。ストリームが終わった場合はio.EOFを返す
+func (r *Reader) Next() (Event, error) {
+	var ev Event
+	var data []string
+	hasData := false
+
+	for r.scanner.Scan() {
+		line := strings.TrimSuffix(r.scanner.Text(), "\r")
+		if line == "" {
+			if !hasData {
+				// コメントやretryだけのブロックはイベントにしない
+				ev = Event{}
+				continue
+			}
+			ev.ID = r.lastID
+			ev.Data = strings.Join(data, "\n")
+			return ev, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				r.lastID = value
+			}
+		case "event":
+			ev.Type = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
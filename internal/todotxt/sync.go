@@ -0,0 +1,172 @@
+package todotxt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// 競合したときに採用する側
+const (
+	PreferDB   = "db"
+	PreferFile = "file"
+)
+
+// Merge 前回の同期時点 (base) を基準にファイル (local) とデータベース (remote) の変更を突き合わせた結果
+type Merge struct {
+	// 同期後のファイルの内容（ファイルの行順を保ち、データベースで追加されたタスクを末尾に加える）
+	Lines []model.Task
+	// データベースに作成・更新するタスク
+	Push []model.Task
+	// ゴミ箱に移動するタスク
+	Trash  []model.Task
+	Report model.SyncReport
+}
+
+// ThreeWayMerge 外部IDをキーにして行単位で3方向マージする
+//
+// 片側だけが前回から変わった行はその変更を反映し、両側が異なる内容に変わった行は prefer の側を採用する。
+// すべてのタスクに外部IDが必要で、ない場合は呼び出し側で NewID を割り当てておく。
+// now はファイルで新しく追加されたタスクの作成日に使う
+func ThreeWayMerge(base, local, remote []model.Task, prefer string, now time.Time) Merge {
+	baseByID := index(base)
+	remoteByID := index(remote)
+	localByID := index(local)
+
+	var m Merge
+	for _, l := range local {
+		b, inBase := baseByID[l.ExternalID]
+		r, inRemote := remoteByID[l.ExternalID]
+
+		switch {
+		case !inRemote && !inBase:
+			// ファイルで追加された
+			if l.CreatedAt.IsZero() {
+				l.CreatedAt = today(now)
+			}
+			m.push(l)
+		case !inRemote:
+			// データベースで削除された。ファイルでも変わっていなければ取り除く
+			if Format(l) == Format(b) {
+				m.Report.Removed++
+				continue
+			}
+			m.conflict(l, model.Task{}, PreferFile)
+			m.push(l)
+		default:
+			m.merge(l, r, b, inBase, prefer)
+		}
+	}
+
+	for _, r := range remote {
+		if _, ok := localByID[r.ExternalID]; ok {
+			continue
+		}
+		b, inBase := baseByID[r.ExternalID]
+		switch {
+		case !inBase:
+			// データベースで追加された
+			m.Lines = append(m.Lines, r)
+			m.Report.Pulled++
+		case Format(r) == Format(b):
+			// ファイルで削除された
+			m.Trash = append(m.Trash, r)
+			m.Report.Trashed++
+		default:
+			// ファイルで削除されたが、データベースで変更されていた
+			m.conflict(model.Task{}, r, PreferDB)
+			m.Lines = append(m.Lines, r)
+			m.Report.Pulled++
+		}
+	}
+	return m
+}
+
+// merge 両側にある行を突き合わせる
+func (m *Merge) merge(l, r, b model.Task, inBase bool, prefer string) {
+	lf, rf := Format(l), Format(r)
+	switch {
+	case lf == rf:
+		m.Lines = append(m.Lines, r)
+	case inBase && lf == Format(b):
+		m.Lines = append(m.Lines, r)
+		m.Report.Pulled++
+	case inBase && rf == Format(b):
+		m.push(Overlay(r, l))
+	default:
+		m.conflict(l, r, prefer)
+		if prefer == PreferFile {
+			m.push(Overlay(r, l))
+		} else {
+			m.Lines = append(m.Lines, r)
+			m.Report.Pulled++
+		}
+	}
+}
+
+func (m *Merge) push(t model.Task) {
+	m.Lines = append(m.Lines, t)
+	m.Push = append(m.Push, t)
+	m.Report.Pushed++
+}
+
+func (m *Merge) conflict(l, r model.Task, resolved string) {
+	c := model.SyncConflict{ExternalID: l.ExternalID, Resolved: resolved}
+	if l.ExternalID != "" {
+		c.Local = Format(l)
+	}
+	if r.ExternalID != "" {
+		c.ExternalID = r.ExternalID
+		c.Remote = Format(r)
+	}
+	m.Report.Conflicts = append(m.Report.Conflicts, c)
+}
+
+// Overlay データベースのタスクにtodo.txtで表せる項目を上書きする
+//
+// 説明や見積時間などtodo.txtにない項目はデータベースの値を残す。
+// 状態の指定がない未完了の行は、完了・中止済みなら todo に戻し、それ以外はデータベースの状態を保つ
+func Overlay(dst, src model.Task) model.Task {
+	dst.Title = src.Title
+	dst.Priority = src.Priority
+	dst.Project = src.Project
+	dst.Contexts = src.Contexts
+	if src.DueDate.IsZero() || src.DueDate.Format(dateLayout) != dst.DueDate.Format(dateLayout) {
+		dst.DueDate = src.DueDate
+	}
+	switch {
+	case src.Status != "":
+		dst.Status = src.Status
+	case dst.Status.Closed():
+		dst.Status = model.StatusTodo
+	}
+	if !src.CompletedAt.IsZero() {
+		dst.CompletedAt = src.CompletedAt
+	}
+	if !src.CreatedAt.IsZero() && src.CreatedAt.Format(dateLayout) != dst.CreatedAt.Format(dateLayout) {
+		dst.CreatedAt = src.CreatedAt
+	}
+	return dst
+}
+
+// NewID 同期の突き合わせに使う外部IDを生成する
+func NewID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func index(tasks []model.Task) map[string]model.Task {
+	m := make(map[string]model.Task, len(tasks))
+	for _, t := range tasks {
+		m[t.ExternalID] = t
+	}
+	return m
+}
+
+func today(now time.Time) time.Time {
+	y, mo, d := now.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+}
@@ -0,0 +1,199 @@
+package todotxt
+
+import (
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+// mustParse todo.txtの行からタスクを作る。データベース側のタスクは id と説明を付けて使う
+func mustParse(t *testing.T, line string) model.Task {
+	t.Helper()
+	task, problems := Parse(line)
+	if len(problems) > 0 {
+		t.Fatalf("Parse(%q) problems = %v", line, problems)
+	}
+	return task
+}
+
+func remoteTask(t *testing.T, id int, line string) model.Task {
+	task := mustParse(t, line)
+	task.ID = id
+	task.Description = "データベースだけにある説明"
+	task.EstimatedDuration = 30
+	return task
+}
+
+func lines(tasks []model.Task) []string {
+	out := make([]string, len(tasks))
+	for i, task := range tasks {
+		out[i] = Format(task)
+	}
+	return out
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var syncNow = time.Date(2026, 10, 19, 9, 30, 0, 0, time.Local)
+
+func TestThreeWayMergeOneSideChanged(t *testing.T) {
+	base := []model.Task{
+		mustParse(t, "(B) ファイルで変更 id:a"),
+		mustParse(t, "(B) データベースで変更 id:b"),
+		mustParse(t, "(B) 変更なし id:c"),
+	}
+	local := []model.Task{
+		mustParse(t, "(A) ファイルで変更 +home id:a"),
+		mustParse(t, "(B) データベースで変更 id:b"),
+		mustParse(t, "(B) 変更なし id:c"),
+		mustParse(t, "(C) ファイルで追加 id:d"),
+	}
+	remote := []model.Task{
+		remoteTask(t, 1, "(B) ファイルで変更 id:a"),
+		remoteTask(t, 2, "(B) データベースで変更 due:2026-10-31 id:b"),
+		remoteTask(t, 3, "(B) 変更なし id:c"),
+		remoteTask(t, 5, "(C) データベースで追加 id:e"),
+	}
+
+	m := ThreeWayMerge(base, local, remote, PreferDB, syncNow)
+
+	want := []string{
+		"(A) ファイルで変更 +home id:a",
+		"(B) データベースで変更 due:2026-10-31 id:b",
+		"(B) 変更なし id:c",
+		"(C) 2026-10-19 ファイルで追加 id:d",
+		"(C) データベースで追加 id:e",
+	}
+	if got := lines(m.Lines); !equalLines(got, want) {
+		t.Errorf("Lines =\n%q\nwant\n%q", got, want)
+	}
+	if len(m.Push) != 2 || m.Push[0].ID != 1 || m.Push[1].ID != 0 {
+		t.Fatalf("Push = %+v, want task 1 and the new task", m.Push)
+	}
+	// todo.txtにない項目はデータベースの値を残す
+	if m.Push[0].Description != "データベースだけにある説明" || m.Push[0].EstimatedDuration != 30 {
+		t.Errorf("Push[0] = %+v, want database fields kept", m.Push[0])
+	}
+	if r := m.Report; r.Pushed != 2 || r.Pulled != 2 || r.Trashed != 0 || r.Removed != 0 || len(r.Conflicts) != 0 {
+		t.Errorf("Report = %+v", r)
+	}
+}
+
+func TestThreeWayMergeBothSidesChanged(t *testing.T) {
+	base := []model.Task{mustParse(t, "(B) 会議の準備 id:a")}
+	local := []model.Task{mustParse(t, "(A) 会議の準備 id:a")}
+	remote := []model.Task{remoteTask(t, 1, "(B) 会議の準備 due:2026-10-20 id:a")}
+
+	t.Run("db", func(t *testing.T) {
+		m := ThreeWayMerge(base, local, remote, PreferDB, syncNow)
+		if got := lines(m.Lines); !equalLines(got, []string{"(B) 会議の準備 due:2026-10-20 id:a"}) {
+			t.Errorf("Lines = %q", got)
+		}
+		if len(m.Push) != 0 {
+			t.Errorf("Push = %+v, want none", m.Push)
+		}
+		if len(m.Report.Conflicts) != 1 || m.Report.Conflicts[0].Resolved != PreferDB {
+			t.Errorf("Conflicts = %+v", m.Report.Conflicts)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		m := ThreeWayMerge(base, local, remote, PreferFile, syncNow)
+		// 行全体をファイルの内容で上書きし、期限も消える
+		if got := lines(m.Lines); !equalLines(got, []string{"(A) 会議の準備 id:a"}) {
+			t.Errorf("Lines = %q", got)
+		}
+		if len(m.Push) != 1 || m.Push[0].ID != 1 || m.Push[0].Priority != 3 || !m.Push[0].DueDate.IsZero() {
+			t.Errorf("Push = %+v", m.Push)
+		}
+		c := m.Report.Conflicts
+		if len(c) != 1 || c[0].Resolved != PreferFile || c[0].Local != "(A) 会議の準備 id:a" ||
+			c[0].Remote != "(B) 会議の準備 due:2026-10-20 id:a" {
+			t.Errorf("Conflicts = %+v", c)
+		}
+	})
+}
+
+func TestThreeWayMergeSameChangeIsNotConflict(t *testing.T) {
+	base := []model.Task{mustParse(t, "(B) 同じ変更 id:a")}
+	local := []model.Task{mustParse(t, "(A) 同じ変更 id:a")}
+	remote := []model.Task{remoteTask(t, 1, "(A) 同じ変更 id:a")}
+
+	m := ThreeWayMerge(base, local, remote, PreferDB, syncNow)
+	if len(m.Push) != 0 || len(m.Report.Conflicts) != 0 || m.Report.Pulled != 0 {
+		t.Errorf("Merge = %+v, want no changes", m)
+	}
+}
+
+func TestThreeWayMergeDeleteVersusEdit(t *testing.T) {
+	base := []model.Task{
+		mustParse(t, "(B) ファイルで削除 id:a"),
+		mustParse(t, "(B) ファイルで削除しデータベースで変更 id:b"),
+		mustParse(t, "(B) データベースで削除 id:c"),
+		mustParse(t, "(B) データベースで削除しファイルで変更 id:d"),
+	}
+	local := []model.Task{
+		mustParse(t, "(B) データベースで削除 id:c"),
+		mustParse(t, "(A) データベースで削除しファイルで変更 id:d"),
+	}
+	remote := []model.Task{
+		remoteTask(t, 1, "(B) ファイルで削除 id:a"),
+		remoteTask(t, 2, "(A) ファイルで削除しデータベースで変更 id:b"),
+	}
+
+	m := ThreeWayMerge(base, local, remote, PreferDB, syncNow)
+
+	// 変更された側を残し、変更されていない側の削除だけを反映する
+	want := []string{
+		"(A) データベースで削除しファイルで変更 id:d",
+		"(A) ファイルで削除しデータベースで変更 id:b",
+	}
+	if got := lines(m.Lines); !equalLines(got, want) {
+		t.Errorf("Lines =\n%q\nwant\n%q", got, want)
+	}
+	if len(m.Trash) != 1 || m.Trash[0].ID != 1 {
+		t.Errorf("Trash = %+v, want task 1", m.Trash)
+	}
+	if len(m.Push) != 1 || m.Push[0].ExternalID != "d" {
+		t.Errorf("Push = %+v, want d recreated", m.Push)
+	}
+
+	r := m.Report
+	if r.Trashed != 1 || r.Removed != 1 || r.Pushed != 1 || r.Pulled != 1 {
+		t.Errorf("Report = %+v", r)
+	}
+	if len(r.Conflicts) != 2 {
+		t.Fatalf("Conflicts = %+v, want 2", r.Conflicts)
+	}
+	if c := r.Conflicts[0]; c.ExternalID != "d" || c.Resolved != PreferFile || c.Remote != "" {
+		t.Errorf("Conflicts[0] = %+v", c)
+	}
+	if c := r.Conflicts[1]; c.ExternalID != "b" || c.Resolved != PreferDB || c.Local != "" {
+		t.Errorf("Conflicts[1] = %+v", c)
+	}
+}
+
+func TestOverlayReopensClosedTask(t *testing.T) {
+	dst := remoteTask(t, 1, "x 2026-10-05 終わったタスク pri:B id:a")
+	got := Overlay(dst, mustParse(t, "(B) 終わったタスク id:a"))
+	if got.Status != model.StatusTodo {
+		t.Errorf("Status = %s, want todo", got.Status)
+	}
+
+	dst = remoteTask(t, 1, "(B) 進行中のタスク status:in_progress id:a")
+	got = Overlay(dst, mustParse(t, "(A) 進行中のタスク id:a"))
+	if got.Status != model.StatusInProgress || got.Priority != 3 {
+		t.Errorf("Overlay = %+v, want in_progress kept", got)
+	}
+}
@@ -0,0 +1,207 @@
+// Package todotxt タスクをtodo.txt形式で読み書きする
+//
+// 1行が1タスクで、完了 (x)、優先度 ((A)〜(C))、完了日・作成日、+プロジェクト（最初の1つ）、@コンテキスト、
+// key:value 形式の追加項目 (due, id, pri, status) を扱う。id は外部IDとして使う
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// importFields todo.txtで表せる項目。説明や見積時間は取り込み先の値を残す
+var importFields = []string{"title", "priority", "due_date", "project", "contexts"}
+
+var priorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+// Parse todo.txtの1行をタスクに変換する。解析できなかった項目は問題点の一覧として返す
+//
+// 完了でも status: の指定もない行の状態は空のままにする（作成時は todo になる）
+func Parse(line string) (model.Task, []string) {
+	t := model.Task{Priority: 1}
+	var problems []string
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		t.Status = model.StatusDone
+		fields = fields[1:]
+		if d, ok := parseDate(fields); ok {
+			t.CompletedAt = d
+			fields = fields[1:]
+		}
+	} else if len(fields) > 0 && priorityPattern.MatchString(fields[0]) {
+		t.Priority = fromLetter(fields[0][1])
+		fields = fields[1:]
+	}
+	if d, ok := parseDate(fields); ok {
+		t.CreatedAt = d
+		fields = fields[1:]
+	}
+
+	var title []string
+	for _, f := range fields {
+		switch {
+		case len(f) > 1 && f[0] == '+':
+			// タスクのプロジェクトは1つのため、2つ目以降は無視する
+			if t.Project == "" {
+				t.Project = f[1:]
+			}
+			continue
+		case len(f) > 1 && f[0] == '@':
+			t.Contexts = append(t.Contexts, f[1:])
+			continue
+		}
+
+		key, value, ok := strings.Cut(f, ":")
+		if !ok || value == "" {
+			title = append(title, f)
+			continue
+		}
+		switch key {
+		case "due":
+			d, err := time.ParseInLocation(dateLayout, value, time.Local)
+			if err != nil {
+				problems = append(problems, "期限日を解析できません: "+value)
+				continue
+			}
+			t.DueDate = d
+		case "id":
+			t.ExternalID = value
+		case "pri":
+			if len(value) != 1 || value[0] < 'A' || value[0] > 'Z' {
+				problems = append(problems, "優先度を解析できません: "+value)
+				continue
+			}
+			t.Priority = fromLetter(value[0])
+		case "status":
+			s, err := model.ParseStatus(value)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			t.Status = s
+		default:
+			// URLなど、既知の項目以外はタイトルの一部として扱う
+			title = append(title, f)
+		}
+	}
+	t.Title = strings.Join(title, " ")
+	return t, problems
+}
+
+// Format タスクをtodo.txtの1行に変換する
+//
+// 完了・中止したタスクは x で始め、優先度は pri: で残す。todo と done 以外の状態は status: で表す
+func Format(t model.Task) string {
+	var parts []string
+	closed := t.Status == model.StatusDone || t.Status == model.StatusCancelled
+	if closed {
+		parts = append(parts, "x")
+		if !t.CompletedAt.IsZero() {
+			parts = append(parts, t.CompletedAt.Format(dateLayout))
+		}
+	} else {
+		parts = append(parts, "("+string(toLetter(t.Priority))+")")
+	}
+	// 完了日がない完了タスクに作成日を書くと完了日と区別できない
+	if !t.CreatedAt.IsZero() && (!closed || !t.CompletedAt.IsZero()) {
+		parts = append(parts, t.CreatedAt.Format(dateLayout))
+	}
+
+	parts = append(parts, t.Title)
+	if t.Project != "" {
+		parts = append(parts, "+"+noSpace(t.Project))
+	}
+	for _, c := range t.Contexts {
+		parts = append(parts, "@"+noSpace(c))
+	}
+	if !t.DueDate.IsZero() {
+		parts = append(parts, "due:"+t.DueDate.Format(dateLayout))
+	}
+	if closed {
+		parts = append(parts, "pri:"+string(toLetter(t.Priority)))
+	}
+	if t.Status != "" && t.Status != model.StatusTodo && t.Status != model.StatusDone {
+		parts = append(parts, "status:"+string(t.Status))
+	}
+	if t.ExternalID != "" {
+		parts = append(parts, "id:"+noSpace(t.ExternalID))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Read todo.txtを読み込み、インポートする行の一覧を返す。空行は読み飛ばす
+func Read(r io.Reader) ([]model.ImportRecord, error) {
+	sc := bufio.NewScanner(r)
+	var records []model.ImportRecord
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+		t, problems := Parse(line)
+		records = append(records, model.ImportRecord{Line: n, Task: t, Errors: problems, Fields: importFields})
+	}
+	return records, sc.Err()
+}
+
+// Write タスクをtodo.txt形式で書き出す
+func Write(w io.Writer, tasks []model.Task) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range tasks {
+		if _, err := fmt.Fprintln(bw, Format(t)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// parseDate 先頭の項目が日付なら解析する
+func parseDate(fields []string) (time.Time, bool) {
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(dateLayout, fields[0], time.Local)
+	return d, err == nil
+}
+
+// fromLetter 優先度の文字を変換する。A=高, B=中, C以降=低
+func fromLetter(c byte) int {
+	switch c {
+	case 'A':
+		return 3
+	case 'B':
+		return 2
+	default:
+		return 1
+	}
+}
+
+func toLetter(priority int) byte {
+	switch {
+	case priority >= 3:
+		return 'A'
+	case priority == 2:
+		return 'B'
+	default:
+		return 'C'
+	}
+}
+
+// noSpace 1語として書き出せるよう空白を _ に置き換える
+func noSpace(s string) string {
+	return strings.Join(strings.Fields(s), "_")
+}
@@ -0,0 +1,129 @@
+package todotxt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"task-recommender/internal/model"
+)
+
+func date(s string) time.Time {
+	d, err := time.ParseInLocation(dateLayout, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want model.Task
+	}{
+		{
+			line: "牛乳を買う",
+			want: model.Task{Title: "牛乳を買う", Priority: 1},
+		},
+		{
+			line: "(A) 2026-10-01 電話する +home @phone @外出 due:2026-10-20 id:abc",
+			want: model.Task{
+				Title: "電話する", Priority: 3, CreatedAt: date("2026-10-01"), DueDate: date("2026-10-20"),
+				Project: "home", Contexts: []string{"phone", "外出"}, ExternalID: "abc",
+			},
+		},
+		{
+			line: "x 2026-10-05 2026-10-01 報告書 +work pri:B",
+			want: model.Task{
+				Title: "報告書", Priority: 2, Status: model.StatusDone,
+				CompletedAt: date("2026-10-05"), CreatedAt: date("2026-10-01"), Project: "work",
+			},
+		},
+		{
+			line: "(C) 返事待ち status:blocked",
+			want: model.Task{Title: "返事待ち", Priority: 1, Status: model.StatusBlocked},
+		},
+		{
+			// プロジェクトは最初の1つだけを使い、タイトルには含めない
+			line: "(B) 設計 +alpha レビュー +beta",
+			want: model.Task{Title: "設計 レビュー", Priority: 2, Project: "alpha"},
+		},
+		{
+			// 既知の項目以外の key:value はタイトルに残す
+			line: "(B) https://example.com/a を読む note:後で",
+			want: model.Task{Title: "https://example.com/a を読む note:後で", Priority: 2},
+		},
+	}
+	for _, tt := range tests {
+		got, problems := Parse(tt.line)
+		if len(problems) > 0 {
+			t.Errorf("Parse(%q) problems = %v", tt.line, problems)
+		}
+		if !sameTask(got, tt.want) {
+			t.Errorf("Parse(%q)\n got %+v\nwant %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseProblems(t *testing.T) {
+	for _, line := range []string{
+		"期限が不正 due:2026-13-01",
+		"優先度が不正 pri:1",
+		"状態が不正 status:unknown",
+	} {
+		if _, problems := Parse(line); len(problems) != 1 {
+			t.Errorf("Parse(%q) problems = %v, want 1 problem", line, problems)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, line := range []string{
+		"(A) 2026-10-01 電話する +home @phone due:2026-10-20 id:abc",
+		"(C) 返事待ち status:blocked",
+		"x 2026-10-05 2026-10-01 報告書 +work pri:B id:r1",
+		"x 2026-10-05 中止した会議 pri:C status:cancelled",
+		"x 完了日のないタスク pri:A",
+	} {
+		task, problems := Parse(line)
+		if len(problems) > 0 {
+			t.Fatalf("Parse(%q) problems = %v", line, problems)
+		}
+		if got := Format(task); got != line {
+			t.Errorf("Format(Parse(%q)) = %q", line, got)
+		}
+	}
+}
+
+func TestFormatReplacesSpaces(t *testing.T) {
+	got := Format(model.Task{Title: "買い物", Priority: 2, Project: "my home", Contexts: []string{"on the way"}})
+	if want := "(B) 買い物 +my_home @on_the_way"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	records, err := Read(strings.NewReader("\ufeff(A) 1行目\n\n(B) 3行目 due:xx\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+	if records[0].Line != 1 || records[0].Task.Title != "1行目" || len(records[0].Errors) != 0 {
+		t.Errorf("records[0] = %+v", records[0])
+	}
+	if records[1].Line != 3 || len(records[1].Errors) != 1 {
+		t.Errorf("records[1] = %+v", records[1])
+	}
+	if records[0].Has("description") || !records[0].Has("project") {
+		t.Errorf("Fields = %v", records[0].Fields)
+	}
+}
+
+func sameTask(a, b model.Task) bool {
+	return a.Title == b.Title && a.Priority == b.Priority && a.Status == b.Status &&
+		a.Project == b.Project && strings.Join(a.Contexts, " ") == strings.Join(b.Contexts, " ") &&
+		a.ExternalID == b.ExternalID && a.DueDate.Equal(b.DueDate) &&
+		a.CreatedAt.Equal(b.CreatedAt) && a.CompletedAt.Equal(b.CompletedAt)
+}
@@ -2,66 +2,114 @@ package view
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"task-recommender/internal/digest"
 	"task-recommender/internal/model"
 )
 
 func PrintTaskList(tasks []model.Task) {
-	if len(tasks) == 0 {
-		fmt.Println("タスクがありません")
-		return
-	}
-
-	fmt.Println("ID | 優先度 | タイトル | 説明 | 期限 | 見積時間(分) | 状態 | 作成日 | 完了日")
-	fmt.Println("---------------------------------------------------------------------------------")
-	for _, t := range tasks {
-		status := "未完了"
-		completedAt := ""
-		if t.Done {
-			status = "完了"
-			completedAt = t.CompletedAt.Format("2006-01-02 15:04:05")
-		}
-
-		dueDate := ""
-		if !t.DueDate.IsZero() {
-			dueDate = t.DueDate.Format("2006-01-02")
-		}
+	tableRenderer{}.RenderTasks(os.Stdout, tasks)
+}
 
-		// 優先度を文字列に変換
-		priorityStr := "低"
-		if t.Priority == 2 {
-			priorityStr = "中"
-		} else if t.Priority >= 3 {
-			priorityStr = "高"
-		}
+// RenderTaskList 指定した形式でタスク一覧を標準出力に書き出す
+func RenderTaskList(format string, tasks []model.Task) error {
+	r, err := RendererFor(format)
+	if err != nil {
+		return err
+	}
+	return r.RenderTasks(os.Stdout, tasks)
+}
 
-		fmt.Printf("%d | %s | %s | %s | %s | %d | %s | %s | %s\n",
-			t.ID, priorityStr, t.Title, t.Description, dueDate, t.EstimatedDuration,
-			status, t.CreatedAt.Format("2006-01-02 15:04:05"), completedAt)
+func PrintTask(t model.Task) {
+	dueDate := "-"
+	if !t.DueDate.IsZero() {
+		dueDate = t.DueDate.Format("2006-01-02")
 	}
+	fmt.Printf("ID:           %d (バージョン %d)\n", t.ID, t.Version)
+	fmt.Printf("タイトル:     %s\n", t.Title)
+	if t.Description != "" {
+		fmt.Printf("説明:         %s\n", t.Description)
+	}
+	if t.Project != "" {
+		fmt.Printf("プロジェクト: %s\n", t.Project)
+	}
+	fmt.Printf("状態:         %s\n", statusLabel(t.Status))
+	fmt.Printf("優先度:       %s\n", t.PriorityLabel())
+	fmt.Printf("期限:         %s\n", dueDate)
+	fmt.Printf("見積/実績:    %d分 / %d分\n", t.EstimatedDuration, t.TrackedDuration)
+	fmt.Printf("チェックリスト: %s\n", t.Checklist)
+}
+
+// PrintVersionConflict 他のユーザーが先に更新していたタスクの現在の内容を表示する
+func PrintVersionConflict(current model.Task) {
+	fmt.Printf("競合: タスクID=%d は他のユーザーによって更新されています（現在のバージョン %d）\n", current.ID, current.Version)
+	fmt.Println("現在の内容:")
+	PrintTask(current)
+	fmt.Printf("内容を確認のうえ --if-version %d を指定して再実行してください\n", current.Version)
 }
 
 func PrintTaskAdded(id int, title string) {
 	fmt.Printf("タスク追加: ID=%d, タイトル=%s\n", id, title)
 }
 
+// PrintTaskQuickAdded 自然文から追加したタスクと、読み取った項目を表示する
+func PrintTaskQuickAdded(id int, t model.Task) {
+	PrintTaskAdded(id, t.Title)
+	details := []string{"優先度=" + t.PriorityLabel()}
+	if !t.DueDate.IsZero() {
+		details = append(details, "期限="+t.DueDate.Format("2006-01-02"))
+	}
+	if t.EstimatedDuration > 0 {
+		details = append(details, fmt.Sprintf("見積=%d分", t.EstimatedDuration))
+	}
+	if t.Project != "" {
+		details = append(details, "プロジェクト="+t.Project)
+	}
+	if len(t.Contexts) > 0 {
+		details = append(details, "状況="+strings.Join(t.Contexts, ","))
+	}
+	fmt.Printf("  %s\n", strings.Join(details, ", "))
+}
+
 func PrintTaskCompleted(id int) {
 	fmt.Printf("タスク完了: ID=%d\n", id)
 }
 
+func PrintTaskReopened(id int) {
+	fmt.Printf("タスク再開: ID=%d\n", id)
+}
+
 func PrintTaskDeleted(id int) {
-	fmt.Printf("タスク削除: ID=%d\n", id)
+	fmt.Printf("タスク削除: ID=%d (ゴミ箱に移動しました)\n", id)
 }
 
-func PrintPriorityUpdated(id int, priority int) {
-	priorityStr := "低"
-	if priority == 2 {
-		priorityStr = "中"
-	} else if priority >= 3 {
-		priorityStr = "高"
+func PrintTrashList(tasks []model.Task) {
+	if len(tasks) == 0 {
+		fmt.Println("ゴミ箱は空です")
+		return
+	}
+
+	fmt.Println("ID | タイトル | 状態 | 削除日")
+	fmt.Println("--------------------------------------------------")
+	for _, t := range tasks {
+		fmt.Printf("%d | %s | %s | %s\n",
+			t.ID, t.Title, statusLabel(t.Status), t.DeletedAt.Format("2006-01-02 15:04:05"))
 	}
-	fmt.Printf("優先度更新: ID=%d, 優先度=%s\n", id, priorityStr)
+}
+
+func PrintTaskRestored(id int) {
+	fmt.Printf("タスク復元: ID=%d\n", id)
+}
+
+func PrintTrashPurged(count int64) {
+	fmt.Printf("ゴミ箱を整理: %d件のタスクを完全に削除しました\n", count)
+}
+
+func PrintPriorityUpdated(id int, priority int) {
+	fmt.Printf("優先度更新: ID=%d, 優先度=%s\n", id, model.PriorityLabel(priority))
 }
 
 func PrintDueDateUpdated(id int, dueDate time.Time) {
@@ -72,6 +120,284 @@ func PrintDurationUpdated(id int, duration int) {
 	fmt.Printf("見積時間更新: ID=%d, 見積時間=%d分\n", id, duration)
 }
 
+func PrintTimerStarted(entry model.TimeEntry) {
+	fmt.Printf("計測開始: タスクID=%d, ユーザー=%s, 開始=%s\n",
+		entry.TaskID, entry.User, entry.StartedAt.Format("2006-01-02 15:04:05"))
+}
+
+func PrintTimerStopped(entry model.TimeEntry) {
+	fmt.Printf("計測停止: タスクID=%d, ユーザー=%s, 計測時間=%d分\n",
+		entry.TaskID, entry.User, int(entry.Duration(entry.StoppedAt).Minutes()))
+}
+
+func PrintTimeEntries(entries []model.TimeEntry) {
+	if len(entries) == 0 {
+		fmt.Println("時間記録がありません")
+		return
+	}
+
+	now := time.Now()
+	var total time.Duration
+	fmt.Println("ID | ユーザー | 開始 | 終了 | 時間(分)")
+	fmt.Println("--------------------------------------------------")
+	for _, e := range entries {
+		stoppedAt := "計測中"
+		if !e.Running() {
+			stoppedAt = e.StoppedAt.Format("2006-01-02 15:04:05")
+		}
+		d := e.Duration(now)
+		total += d
+		fmt.Printf("%d | %s | %s | %s | %d\n",
+			e.ID, e.User, e.StartedAt.Format("2006-01-02 15:04:05"), stoppedAt, int(d.Minutes()))
+	}
+	fmt.Printf("合計: %d分\n", int(total.Minutes()))
+}
+
+func PrintStatusUpdated(id int, status model.Status) {
+	fmt.Printf("状態更新: ID=%d, 状態=%s\n", id, statusLabel(status))
+}
+
+func PrintStatusTransitions(transitions []model.StatusTransition) {
+	if len(transitions) == 0 {
+		fmt.Println("状態遷移の履歴がありません")
+		return
+	}
+
+	fmt.Println("日時 | 遷移前 | 遷移後 | ユーザー")
+	fmt.Println("--------------------------------------------------")
+	for _, t := range transitions {
+		from := "-"
+		if t.From != "" {
+			from = statusLabel(t.From)
+		}
+		fmt.Printf("%s | %s | %s | %s\n",
+			t.TransitionedAt.Format("2006-01-02 15:04:05"), from, statusLabel(t.To), t.Actor)
+	}
+}
+
+func PrintHistory(entries []model.HistoryEntry) {
+	if len(entries) == 0 {
+		fmt.Println("変更履歴がありません")
+		return
+	}
+
+	fmt.Println("日時 | ユーザー | 操作 | 項目 | 変更前 | 変更後")
+	fmt.Println("--------------------------------------------------------------------")
+	for _, e := range entries {
+		fmt.Printf("%s | %s | %s | %s | %s | %s\n",
+			e.CreatedAt.Format("2006-01-02 15:04:05"), e.Actor, historyActionLabel(e.Action),
+			e.Field, e.OldValue, e.NewValue)
+	}
+}
+
+func PrintComments(comments []model.Comment) {
+	if len(comments) == 0 {
+		fmt.Println("コメントがありません")
+		return
+	}
+
+	for _, c := range comments {
+		edited := ""
+		if !c.UpdatedAt.IsZero() {
+			edited = " (編集済み)"
+		}
+		fmt.Printf("#%d %s %s%s\n%s\n\n",
+			c.ID, c.Author, c.CreatedAt.Format("2006-01-02 15:04:05"), edited, c.Body)
+	}
+}
+
+func PrintCommentAdded(c model.Comment) {
+	fmt.Printf("コメント投稿: タスクID=%d, コメントID=%d\n", c.TaskID, c.ID)
+}
+
+func PrintChecklist(items []model.ChecklistItem) {
+	if len(items) == 0 {
+		fmt.Println("チェックリストがありません")
+		return
+	}
+
+	done := 0
+	for _, item := range items {
+		mark := "[ ]"
+		if item.Done {
+			mark = "[x]"
+			done++
+		}
+		fmt.Printf("%s %d. %s (ID=%d)\n", mark, item.Position+1, item.Title, item.ID)
+	}
+	fmt.Printf("進捗: %d/%d\n", done, len(items))
+}
+
+func PrintChecklistItemAdded(item model.ChecklistItem) {
+	fmt.Printf("チェックリスト追加: タスクID=%d, 項目ID=%d, 内容=%s\n", item.TaskID, item.ID, item.Title)
+}
+
+func PrintChecklistItemToggled(item model.ChecklistItem) {
+	status := "未完了"
+	if item.Done {
+		status = "完了"
+	}
+	fmt.Printf("チェックリスト更新: 項目ID=%d, 内容=%s, 状態=%s\n", item.ID, item.Title, status)
+}
+
+func PrintRecommendations(recommendations []model.Recommendation) {
+	if len(recommendations) == 0 {
+		fmt.Println("おすすめのタスクはありません")
+		return
+	}
+
+	for i, r := range recommendations {
+		dueDate := "-"
+		if !r.Task.DueDate.IsZero() {
+			dueDate = r.Task.DueDate.Format("2006-01-02")
+		}
+		fmt.Printf("%d. [ID=%d] %s (スコア=%.1f, 期限=%s, 残り約%d分)\n",
+			i+1, r.Task.ID, r.Task.Title, r.Score, dueDate, r.RemainingEffort)
+		if len(r.Reasons) > 0 {
+			fmt.Printf("   理由: %s\n", strings.Join(r.Reasons, ", "))
+		}
+	}
+}
+
+func PrintImportReport(report model.ImportReport) {
+	for _, r := range report.Results {
+		switch r.Action {
+		case model.ImportError:
+			fmt.Printf("%d行目: エラー %s (%s)\n", r.Line, r.Title, strings.Join(r.Errors, ", "))
+		case model.ImportCreate:
+			fmt.Printf("%d行目: 作成 %s\n", r.Line, r.Title)
+		case model.ImportUpdate:
+			fmt.Printf("%d行目: 更新 ID=%d %s\n", r.Line, r.TaskID, r.Title)
+		case model.ImportSkip:
+			fmt.Printf("%d行目: 未保存 %s\n", r.Line, r.Title)
+		}
+	}
+
+	prefix := "インポート"
+	if report.DryRun {
+		prefix = "インポート（ドライラン）"
+	}
+	fmt.Printf("%s: 作成=%d, 更新=%d, エラー=%d\n", prefix, report.Created, report.Updated, report.Failed)
+}
+
+func PrintSyncReport(report model.SyncReport) {
+	for _, c := range report.Conflicts {
+		side := "データベース"
+		if c.Resolved == "file" {
+			side = "ファイル"
+		}
+		fmt.Printf("競合 id:%s（%sの内容を採用）\n", c.ExternalID, side)
+		if c.Local != "" {
+			fmt.Printf("  ファイル:     %s\n", c.Local)
+		} else {
+			fmt.Println("  ファイル:     （削除）")
+		}
+		if c.Remote != "" {
+			fmt.Printf("  データベース: %s\n", c.Remote)
+		} else {
+			fmt.Println("  データベース: （削除）")
+		}
+	}
+
+	prefix := "同期"
+	if report.DryRun {
+		prefix = "同期（ドライラン）"
+	}
+	fmt.Printf("%s: ファイルへ反映=%d, データベースへ反映=%d, ゴミ箱へ移動=%d, ファイルから削除=%d, 競合=%d\n",
+		prefix, report.Pulled, report.Pushed, report.Trashed, report.Removed, len(report.Conflicts))
+}
+
+func PrintBackupSummary(title string, summary model.BackupSummary) {
+	fmt.Printf("%s (形式バージョン=%d, 作成日時=%s)\n", title, summary.Version, summary.CreatedAt.Format("2006-01-02 15:04:05"))
+	for _, t := range summary.Tables {
+		fmt.Printf("  %s: %d件\n", t.Name, t.Rows)
+	}
+	fmt.Printf("  添付ファイルの内容: %d件\n", summary.Blobs)
+	for _, key := range summary.MissingBlobs {
+		fmt.Printf("  警告: 添付ファイルの内容が見つかりません: %s\n", key)
+	}
+}
+
+func PrintTaskEvent(e model.TaskEvent) {
+	detail := ""
+	if e.Field != "" {
+		detail = fmt.Sprintf(", %s: %s → %s", e.Field, e.OldValue, e.NewValue)
+	}
+	fmt.Printf("%s [%s] タスクID=%d, ユーザー=%s%s\n",
+		e.OccurredAt.Local().Format("2006-01-02 15:04:05"), eventTypeLabel(e.Type), e.TaskID, e.Actor, detail)
+}
+
+// eventTypeLabel イベントの種類を表示用の文字列に変換
+func eventTypeLabel(t model.EventType) string {
+	switch t {
+	case model.EventTaskCreated:
+		return "作成"
+	case model.EventTaskUpdated:
+		return "更新"
+	case model.EventTaskCompleted:
+		return "完了"
+	case model.EventTaskDeleted:
+		return "削除"
+	default:
+		return string(t)
+	}
+}
+
+func PrintDigest(d model.Digest, html bool) error {
+	text, htmlBody, err := digest.Render(d)
+	if err != nil {
+		return err
+	}
+	if html {
+		fmt.Print(htmlBody)
+	} else {
+		fmt.Print(text)
+	}
+	return nil
+}
+
+func PrintDigestSent(user, to string) {
+	fmt.Printf("まとめを送信: ユーザー=%s, 宛先=%s\n", user, to)
+}
+
+// historyActionLabel 操作種別を表示用の文字列に変換
+func historyActionLabel(action model.HistoryAction) string {
+	switch action {
+	case model.HistoryCreated:
+		return "作成"
+	case model.HistoryUpdated:
+		return "更新"
+	case model.HistoryStatusChanged:
+		return "状態変更"
+	case model.HistoryDeleted:
+		return "削除"
+	case model.HistoryRestored:
+		return "復元"
+	case model.HistoryPurged:
+		return "完全削除"
+	default:
+		return string(action)
+	}
+}
+
+// statusLabel 状態を表示用の文字列に変換
+func statusLabel(status model.Status) string {
+	switch status {
+	case model.StatusTodo:
+		return "未着手"
+	case model.StatusInProgress:
+		return "進行中"
+	case model.StatusBlocked:
+		return "保留"
+	case model.StatusDone:
+		return "完了"
+	case model.StatusCancelled:
+		return "中止"
+	default:
+		return string(status)
+	}
+}
+
 func PrintError(err error) {
 	fmt.Printf("エラー: %v\n", err)
 }
@@ -0,0 +1,314 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/width"
+	"gopkg.in/yaml.v3"
+
+	"task-recommender/internal/model"
+)
+
+// Renderer タスク一覧を特定の形式で書き出す
+type Renderer interface {
+	// ContentType HTTPレスポンスのContent-Type
+	ContentType() string
+	RenderTasks(w io.Writer, tasks []model.Task) error
+}
+
+// DefaultFormat CLIで形式を指定しない場合の出力形式
+const DefaultFormat = "table"
+
+var renderers = map[string]Renderer{
+	"table":    tableRenderer{},
+	"markdown": markdownRenderer{},
+	"html":     htmlRenderer{},
+	"json":     jsonRenderer{},
+	"yaml":     yamlRenderer{},
+}
+
+// mediaTypes Acceptヘッダーのメディアタイプと出力形式の対応
+var mediaTypes = map[string]string{
+	"text/plain":         "table",
+	"text/markdown":      "markdown",
+	"text/x-markdown":    "markdown",
+	"text/html":          "html",
+	"application/json":   "json",
+	"application/yaml":   "yaml",
+	"application/x-yaml": "yaml",
+	"text/yaml":          "yaml",
+}
+
+// RegisterRenderer 出力形式を追加する。同じ名前の形式は置き換える
+func RegisterRenderer(format string, r Renderer, mediaType ...string) {
+	renderers[format] = r
+	for _, t := range mediaType {
+		mediaTypes[t] = format
+	}
+}
+
+// Formats 利用できる出力形式の一覧
+func Formats() []string {
+	formats := make([]string, 0, len(renderers))
+	for f := range renderers {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// RendererFor 出力形式の名前からRendererを返す
+func RendererFor(format string) (Renderer, error) {
+	r, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("未対応の形式です: %s（%s）", format, strings.Join(Formats(), ", "))
+	}
+	return r, nil
+}
+
+// NegotiateRenderer Acceptヘッダーから品質値 (q) の最も高い形式のRendererを返す
+//
+// ヘッダーがない、または */* を含む場合は fallback の形式を使う。対応する形式がなければ false を返す
+func NegotiateRenderer(accept, fallback string) (Renderer, bool) {
+	if strings.TrimSpace(accept) == "" {
+		r, err := RendererFor(fallback)
+		return r, err == nil
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil {
+				continue
+			}
+		}
+
+		format, ok := mediaTypes[mediaType]
+		if mediaType == "*/*" || mediaType == "application/*" || mediaType == "text/*" {
+			format, ok = fallback, true
+		}
+		if ok && q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	if best == "" {
+		return nil, false
+	}
+	r, err := RendererFor(best)
+	return r, err == nil
+}
+
+// taskColumns 表形式の出力の列名
+var taskColumns = []string{"ID", "優先度", "タイトル", "プロジェクト", "説明", "期限", "見積時間(分)", "実績時間(分)", "チェックリスト", "状態", "作成日", "完了日"}
+
+// taskRow 表形式の出力の1行分の値
+func taskRow(t model.Task) []string {
+	completedAt := ""
+	if t.Done {
+		completedAt = t.CompletedAt.Format("2006-01-02 15:04:05")
+	}
+	dueDate := ""
+	if !t.DueDate.IsZero() {
+		dueDate = t.DueDate.Format("2006-01-02")
+	}
+	return []string{
+		strconv.Itoa(t.ID), t.PriorityLabel(), t.Title, t.Project, t.Description, dueDate,
+		strconv.Itoa(t.EstimatedDuration), strconv.Itoa(t.TrackedDuration), t.Checklist.String(),
+		statusLabel(t.Status), t.CreatedAt.Format("2006-01-02 15:04:05"), completedAt,
+	}
+}
+
+// tableRenderer 全角文字の表示幅を考慮して列をそろえた表
+type tableRenderer struct{}
+
+func (tableRenderer) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (tableRenderer) RenderTasks(w io.Writer, tasks []model.Task) error {
+	if len(tasks) == 0 {
+		_, err := fmt.Fprintln(w, "タスクがありません")
+		return err
+	}
+
+	rows := [][]string{taskColumns}
+	for _, t := range tasks {
+		row := taskRow(t)
+		// 改行を含む値で行が崩れないよう1行にまとめる
+		for i, cell := range row {
+			row[i] = strings.Join(strings.Fields(cell), " ")
+		}
+		rows = append(rows, row)
+	}
+	widths := make([]int, len(taskColumns))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := displayWidth(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	for i, row := range rows {
+		var b strings.Builder
+		for j, cell := range row {
+			if j > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(cell)
+			if j < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[j]-displayWidth(cell)))
+			}
+		}
+		if _, err := fmt.Fprintln(w, b.String()); err != nil {
+			return err
+		}
+		if i == 0 {
+			var rule []string
+			for _, n := range widths {
+				rule = append(rule, strings.Repeat("-", n))
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(rule, "  ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// displayWidth 端末での表示幅。全角・広い文字は2、それ以外は1として数える
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// markdownRenderer Markdownの表
+type markdownRenderer struct{}
+
+func (markdownRenderer) ContentType() string { return "text/markdown; charset=utf-8" }
+
+func (markdownRenderer) RenderTasks(w io.Writer, tasks []model.Task) error {
+	line := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(c)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
+	}
+
+	var b strings.Builder
+	b.WriteString(line(taskColumns) + "\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(taskColumns)) + "\n")
+	for _, t := range tasks {
+		b.WriteString(line(taskRow(t)) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("tasks").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>タスク一覧</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>タスク一覧</h1>
+<table>
+<thead><tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+// htmlRenderer 表を含むHTML文書
+type htmlRenderer struct{}
+
+func (htmlRenderer) ContentType() string { return "text/html; charset=utf-8" }
+
+func (htmlRenderer) RenderTasks(w io.Writer, tasks []model.Task) error {
+	rows := make([][]string, 0, len(tasks))
+	for _, t := range tasks {
+		rows = append(rows, taskRow(t))
+	}
+	return htmlTemplate.Execute(w, struct {
+		Columns []string
+		Rows    [][]string
+	}{taskColumns, rows})
+}
+
+// jsonRenderer APIと同じJSON
+type jsonRenderer struct{}
+
+func (jsonRenderer) ContentType() string { return "application/json" }
+
+func (jsonRenderer) RenderTasks(w io.Writer, tasks []model.Task) error {
+	if tasks == nil {
+		tasks = []model.Task{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tasks)
+}
+
+// yamlRenderer JSONと同じ項目名・順序のYAML
+type yamlRenderer struct{}
+
+func (yamlRenderer) ContentType() string { return "application/yaml" }
+
+func (yamlRenderer) RenderTasks(w io.Writer, tasks []model.Task) error {
+	if tasks == nil {
+		tasks = []model.Task{}
+	}
+	b, err := json.Marshal(tasks)
+	if err != nil {
+		return err
+	}
+
+	// JSONはYAMLとして読めるため、ノードとして読み込んで項目の順序を保つ
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle JSON由来のフロー形式と引用符を外す。値の型は保たれる
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
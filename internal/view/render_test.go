@@ -0,0 +1,153 @@
+package view
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"task-recommender/internal/model"
+)
+
+func TestNegotiateRenderer(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", "application/json", true},
+		{"text/html", "text/html; charset=utf-8", true},
+		{"application/json;q=0.5, text/markdown;q=0.9", "text/markdown; charset=utf-8", true},
+		{"text/markdown;q=0.2, application/yaml", "application/yaml", true},
+		{"text/html;q=0.8, */*;q=0.1", "text/html; charset=utf-8", true},
+		{"*/*", "application/json", true},
+		{"text/*;q=0.3, text/plain;q=0.4", "text/plain; charset=utf-8", true},
+		// 同じ品質値なら先に書かれた形式
+		{"text/html, text/markdown", "text/html; charset=utf-8", true},
+		// q=0 は受け付けないという意味
+		{"text/html;q=0", "", false},
+		{"text/html;q=0, application/json;q=0.1", "application/json", true},
+		// 解析できない項目は無視する
+		{"text/html;q=abc, application/yaml;q=0.5", "application/yaml", true},
+		{"image/png", "", false},
+	}
+	for _, tt := range tests {
+		r, ok := NegotiateRenderer(tt.accept, "json")
+		if ok != tt.ok {
+			t.Errorf("NegotiateRenderer(%q) ok = %v, want %v", tt.accept, ok, tt.ok)
+			continue
+		}
+		if ok && r.ContentType() != tt.want {
+			t.Errorf("NegotiateRenderer(%q) = %s, want %s", tt.accept, r.ContentType(), tt.want)
+		}
+	}
+}
+
+func TestRendererFor(t *testing.T) {
+	for _, f := range Formats() {
+		if _, err := RendererFor(f); err != nil {
+			t.Errorf("RendererFor(%q): %v", f, err)
+		}
+	}
+	if _, err := RendererFor("csv"); err == nil {
+		t.Error("未対応の形式でエラーになりません")
+	}
+}
+
+func testTasks() []model.Task {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.Local)
+	return []model.Task{
+		{ID: 1, Title: "牛乳を買う", Project: "家", Priority: 3, Status: model.StatusTodo, CreatedAt: created},
+		{ID: 12, Title: "report", Description: "1行目\n2行目", Priority: 1, Status: model.StatusDone, Done: true, CreatedAt: created, CompletedAt: created},
+		{ID: 3, Title: "123", Priority: 2, Status: model.StatusTodo, CreatedAt: created},
+		{ID: 4, Title: "true", Description: "null", Priority: 2, Status: model.StatusTodo, CreatedAt: created},
+	}
+}
+
+func TestTableRendererAlignsWideCharacters(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (tableRenderer{}).RenderTasks(&buf, testTasks()[:2]); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("lines = %q", lines)
+	}
+
+	// 区切り線から各列の表示上の開始位置を求め、全角・半角の値がその位置から始まることを確かめる
+	var starts []int
+	pos := 0
+	for _, dashes := range strings.Split(lines[1], "  ") {
+		starts = append(starts, pos)
+		pos += len(dashes) + 2
+	}
+	for _, c := range []struct {
+		line  int
+		cell  string
+		start int
+	}{
+		{0, "プロジェクト", starts[3]},
+		{2, "家", starts[3]},
+		{0, "説明", starts[4]},
+		{3, "1行目 2行目", starts[4]}, // 改行を含む値は1行にまとめる
+		{2, "高", starts[1]},
+		{3, "低", starts[1]},
+	} {
+		i := strings.Index(lines[c.line], c.cell)
+		if i < 0 || displayWidth(lines[c.line][:i]) != c.start {
+			t.Errorf("%d行目の %q の位置 = %d, want %d\n%s", c.line+1, c.cell, displayWidth(lines[c.line][:max(i, 0)]), c.start, buf.String())
+		}
+	}
+}
+
+func TestTableRendererEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (tableRenderer{}).RenderTasks(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "タスクがありません\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestYAMLRendererKeepsTypes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (yamlRenderer{}).RenderTasks(&buf, testTasks()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []map[string]interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+	if len(got) != 4 {
+		t.Fatalf("len = %d", len(got))
+	}
+	// 数値や真偽値に見える文字列も文字列のまま
+	if v, ok := got[2]["title"].(string); !ok || v != "123" {
+		t.Errorf("title = %#v, want string \"123\"", got[2]["title"])
+	}
+	if v, ok := got[3]["title"].(string); !ok || v != "true" {
+		t.Errorf("title = %#v, want string \"true\"", got[3]["title"])
+	}
+	if v, ok := got[3]["description"].(string); !ok || v != "null" {
+		t.Errorf("description = %#v, want string \"null\"", got[3]["description"])
+	}
+	if v, ok := got[2]["id"].(int); !ok || v != 3 {
+		t.Errorf("id = %#v, want int 3", got[2]["id"])
+	}
+	if v, ok := got[1]["done"].(bool); !ok || !v {
+		t.Errorf("done = %#v, want true", got[1]["done"])
+	}
+	if v := got[1]["description"]; v != "1行目\n2行目" {
+		t.Errorf("description = %#v", v)
+	}
+
+	// 項目の順序はJSONと同じ
+	out := buf.String()
+	if strings.Index(out, "id:") > strings.Index(out, "title:") {
+		t.Errorf("項目の順序がJSONと異なります:\n%s", out)
+	}
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClientSendSignsRequest(t *testing.T) {
+	const secret = "s3cr3t"
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, gotBody = r, mustReadAll(t, r.Body)
+		if err := Verify(secret, r.Header, gotBody, 5*time.Minute, now.Add(time.Second)); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := &Client{HTTP: srv.Client(), Now: func() time.Time { return now }}
+	body := []byte(`{"type":"task.created","task_id":1}`)
+	code, err := c.Send(srv.URL, secret, Message{DeliveryID: 42, Event: "task.created", Body: body})
+	if err != nil {
+		t.Fatalf("Send error = %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", code)
+	}
+
+	if got.Method != http.MethodPost || got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("request = %s %s", got.Method, got.Header.Get("Content-Type"))
+	}
+	if got.Header.Get(HeaderEvent) != "task.created" || got.Header.Get(HeaderDelivery) != "42" {
+		t.Errorf("headers = %v", got.Header)
+	}
+	if got.Header.Get(HeaderTimestamp) != strconv.FormatInt(now.Unix(), 10) {
+		t.Errorf("%s = %q", HeaderTimestamp, got.Header.Get(HeaderTimestamp))
+	}
+	if string(gotBody) != string(body) {
+		t.Errorf("body = %s, want %s", gotBody, body)
+	}
+	if err := Verify("wrong", got.Header, gotBody, 0, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with wrong secret = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestClientSendReportsFailureStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	code, err := NewClient(time.Second).Send(srv.URL, "secret", Message{DeliveryID: 1, Event: "task.updated", Body: []byte("{}")})
+	if err == nil {
+		t.Fatal("Send error = nil, want error for 500")
+	}
+	if code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", code)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "s3cr3t"
+	now := time.Unix(1_800_000_000, 0)
+	body := []byte(`{}`)
+	header := func(ts time.Time, sig string) http.Header {
+		h := http.Header{}
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		h.Set(HeaderTimestamp, timestamp)
+		if sig == "" {
+			sig = Sign(secret, timestamp, body)
+		}
+		h.Set(HeaderSignature, sig)
+		return h
+	}
+
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      []byte
+		tolerance time.Duration
+		wantErr   bool
+	}{
+		{"valid", header(now, ""), body, time.Minute, false},
+		{"within tolerance", header(now.Add(-59*time.Second), ""), body, time.Minute, false},
+		{"too old", header(now.Add(-2*time.Minute), ""), body, time.Minute, true},
+		{"in the future", header(now.Add(2*time.Minute), ""), body, time.Minute, true},
+		{"old but not checked", header(now.Add(-time.Hour), ""), body, 0, false},
+		{"tampered body", header(now, ""), []byte(`{"x":1}`), time.Minute, true},
+		{"bad signature", header(now, "sha256=00"), body, time.Minute, true},
+		{"missing timestamp", http.Header{}, body, time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(secret, tt.header, tt.body, tt.tolerance, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, time.Hour
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.n, base, max); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func mustReadAll(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Error(err)
+	}
+	return b
+}
@@ -8,30 +8,221 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func InitializeDatabase(db *sql.DB) error {
-	// テーブルを削除して再作成
-	dropTableQuery := `DROP TABLE IF EXISTS tasks;`
-	_, err := db.Exec(dropTableQuery)
-	if err != nil {
-		return err
-	}
-
-	// テーブルを新規作成
-	createTableQuery := `
-    CREATE TABLE tasks (
+// migrations スキーマを作成・変更するSQL。起動のたびに未適用のものだけを順に実行し、適用した番号を schema_migrations に記録する。
+// 適用済みのデータベースがあるため、既存の項目は書き換えずに末尾へ追加すること。
+// 番号を記録する前に作成したデータベースにも適用できるよう、各SQLは何度実行しても同じ結果になるように書く
+var migrations = []string{
+	// テーブルを新規作成。versionは楽観的排他制御のため、更新のたびにトリガーで1つ増やす
+	`
+    CREATE TABLE IF NOT EXISTS tasks (
         id SERIAL PRIMARY KEY,
+        external_id VARCHAR(255) UNIQUE,
         title VARCHAR(255) NOT NULL,
         description TEXT,
         done BOOLEAN DEFAULT FALSE,
+        status VARCHAR(20) NOT NULL DEFAULT 'todo',
+        status_changed_at TIMESTAMP,
         priority INT,
         due_date TIMESTAMP,
         estimated_duration INT,
+        project VARCHAR(255) NOT NULL DEFAULT '',
+        contexts TEXT[] NOT NULL DEFAULT '{}',
+        created_at TIMESTAMP NOT NULL,
+        completed_at TIMESTAMP,
+        deleted_at TIMESTAMP,
+        version INT NOT NULL DEFAULT 1
+    );
+    CREATE OR REPLACE FUNCTION tasks_bump_version() RETURNS trigger AS $$
+    BEGIN
+        NEW.version := OLD.version + 1;
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+    DROP TRIGGER IF EXISTS tasks_bump_version ON tasks;
+    CREATE TRIGGER tasks_bump_version
+        BEFORE UPDATE ON tasks
+        FOR EACH ROW EXECUTE FUNCTION tasks_bump_version();`,
+
+	// 時間記録テーブル。ユーザーごとに計測中(stopped_at IS NULL)は1件まで
+	`
+    CREATE TABLE IF NOT EXISTS time_entries (
+        id SERIAL PRIMARY KEY,
+        task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+        user_name VARCHAR(255) NOT NULL,
+        started_at TIMESTAMP NOT NULL,
+        stopped_at TIMESTAMP
+    );
+    CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (user_name) WHERE stopped_at IS NULL;`,
+
+	// 状態遷移テーブル。作成時の遷移はfrom_statusがNULL
+	`
+    CREATE TABLE IF NOT EXISTS task_status_transitions (
+        id SERIAL PRIMARY KEY,
+        task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+        from_status VARCHAR(20),
+        to_status VARCHAR(20) NOT NULL,
+        actor VARCHAR(255) NOT NULL,
+        transitioned_at TIMESTAMP NOT NULL
+    );`,
+
+	// 変更履歴テーブル。完全削除後も履歴を残すため外部キーは張らず、更新・削除はトリガーで禁止する
+	// xact_id は記録したトランザクションのID。コミット順が前後しても取りこぼさずに読めるよう、イベントの読み出し順に使う
+	`
+    CREATE TABLE IF NOT EXISTS task_history (
+        id BIGSERIAL PRIMARY KEY,
+        task_id INT NOT NULL,
+        action VARCHAR(20) NOT NULL,
+        field VARCHAR(50),
+        old_value TEXT,
+        new_value TEXT,
+        actor VARCHAR(255) NOT NULL,
         created_at TIMESTAMP NOT NULL,
+        xact_id XID8 NOT NULL DEFAULT pg_current_xact_id()
+    );
+    CREATE INDEX IF NOT EXISTS task_history_task_id_idx ON task_history (task_id, created_at);
+    CREATE INDEX IF NOT EXISTS task_history_xact_id_idx ON task_history (xact_id, id);
+    CREATE OR REPLACE FUNCTION task_history_append_only() RETURNS trigger AS $$
+    BEGIN
+        RAISE EXCEPTION 'task_history is append-only';
+    END;
+    $$ LANGUAGE plpgsql;
+    DROP TRIGGER IF EXISTS task_history_append_only ON task_history;
+    CREATE TRIGGER task_history_append_only
+        BEFORE UPDATE OR DELETE ON task_history
+        FOR EACH ROW EXECUTE FUNCTION task_history_append_only();`,
+
+	// コメントテーブル。本文はMarkdownのまま保存する
+	`
+    CREATE TABLE IF NOT EXISTS task_comments (
+        id SERIAL PRIMARY KEY,
+        task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+        author VARCHAR(255) NOT NULL,
+        body TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL,
+        updated_at TIMESTAMP
+    );
+    CREATE INDEX IF NOT EXISTS task_comments_task_id_idx ON task_comments (task_id, created_at);`,
+
+	// 添付ファイルテーブル。内容はBlobStoreに保存し、ここにはメタデータのみ持つ
+	`
+    CREATE TABLE IF NOT EXISTS task_attachments (
+        id SERIAL PRIMARY KEY,
+        task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+        filename VARCHAR(255) NOT NULL,
+        content_type VARCHAR(255) NOT NULL,
+        size BIGINT NOT NULL,
+        storage_key VARCHAR(255) NOT NULL UNIQUE,
+        uploaded_by VARCHAR(255) NOT NULL,
+        created_at TIMESTAMP NOT NULL
+    );`,
+
+	// チェックリストテーブル。positionの昇順に表示する
+	`
+    CREATE TABLE IF NOT EXISTS checklist_items (
+        id SERIAL PRIMARY KEY,
+        task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+        position INT NOT NULL,
+        title TEXT NOT NULL,
+        done BOOLEAN NOT NULL DEFAULT FALSE,
         completed_at TIMESTAMP
-    );`
+    );
+    CREATE INDEX IF NOT EXISTS checklist_items_task_id_idx ON checklist_items (task_id, position);`,
 
-	_, err = db.Exec(createTableQuery)
-	return err
+	// Webhookテーブル。eventsが空の場合はすべてのイベントを送る
+	`
+    CREATE TABLE IF NOT EXISTS webhooks (
+        id SERIAL PRIMARY KEY,
+        url TEXT NOT NULL,
+        secret VARCHAR(255) NOT NULL,
+        events TEXT[] NOT NULL DEFAULT '{}',
+        created_by VARCHAR(255) NOT NULL,
+        created_at TIMESTAMP NOT NULL
+    );`,
+
+	// Webhookの送信待ち行列と送信記録。変更履歴と同じトランザクションで追加する
+	`
+    CREATE TABLE IF NOT EXISTS webhook_deliveries (
+        id BIGSERIAL PRIMARY KEY,
+        webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+        history_id BIGINT NOT NULL REFERENCES task_history(id),
+        event_type VARCHAR(50) NOT NULL,
+        status VARCHAR(20) NOT NULL DEFAULT 'pending',
+        attempts INT NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMP NOT NULL,
+        last_status_code INT,
+        last_error TEXT,
+        created_at TIMESTAMP NOT NULL,
+        delivered_at TIMESTAMP
+    );
+    CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+    CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);`,
+
+	// 送信した期限のリマインダー。同じリマインダーを通知先ごとに1度だけ送るため、送信前に行を追加して確保する。
+	// 期限日を変えた場合は別のリマインダーとして扱う
+	`
+    CREATE TABLE IF NOT EXISTS reminder_log (
+        id SERIAL PRIMARY KEY,
+        task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+        kind VARCHAR(20) NOT NULL,
+        lead_minutes INT NOT NULL,
+        due_date TIMESTAMP NOT NULL,
+        channel VARCHAR(50) NOT NULL,
+        sent_at TIMESTAMP NOT NULL,
+        UNIQUE (task_id, kind, lead_minutes, due_date, channel)
+    );`,
+
+	// 送信した毎朝のまとめ。ユーザーごとに1日1度だけ送る
+	`
+    CREATE TABLE IF NOT EXISTS digest_log (
+        id SERIAL PRIMARY KEY,
+        username VARCHAR(255) NOT NULL,
+        digest_date DATE NOT NULL,
+        sent_at TIMESTAMP NOT NULL,
+        UNIQUE (username, digest_date)
+    );`,
+}
+
+// InitializeDatabase 未適用のマイグレーションを実行する。既存のテーブルとデータはそのまま残す
+func InitializeDatabase(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 複数のプロセスが同時に起動しても、マイグレーションは1つずつ実行する
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('task-recommender.schema_migrations'))`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INT PRIMARY KEY,
+        applied_at TIMESTAMP NOT NULL
+    )`); err != nil {
+		return err
+	}
+
+	var applied int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&applied); err != nil {
+		return err
+	}
+	for i := applied; i < len(migrations); i++ {
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			return fmt.Errorf("マイグレーション %d の実行エラー: %w", i+1, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES ($1, NOW())`, i+1); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ResetDatabase すべてのテーブルを削除してから作り直す。既存のデータはすべて消える
+func ResetDatabase(db *sql.DB) error {
+	_, err := db.Exec(`DROP TABLE IF EXISTS schema_migrations, digest_log, reminder_log, webhook_deliveries, webhooks, checklist_items, task_attachments, task_comments, task_history, task_status_transitions, time_entries, tasks`)
+	if err != nil {
+		return err
+	}
+	return InitializeDatabase(db)
 }
 
 func Connect() (*sql.DB, error) {
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrBlobNotFound 指定したキーのデータが存在しない
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrSizeMismatch 読み込んだデータのバイト数が指定されたサイズと一致しない
+var ErrSizeMismatch = errors.New("blob size mismatch")
+
+// BlobStore 添付ファイルなどのバイナリデータを保存するストレージ
+type BlobStore interface {
+	// Put keyにデータを保存する。sizeはデータのバイト数で、rから読めたバイト数と異なる場合はErrSizeMismatchを返す
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// Get keyのデータを読み出す。存在しない場合はErrBlobNotFoundを返す
+	Get(key string) (io.ReadCloser, error)
+	// Delete keyのデータを削除する。存在しない場合もエラーにしない
+	Delete(key string) error
+}
+
+// FromEnv 環境変数の設定からBlobStoreを作成する
+//
+// BLOB_STORE が "s3" の場合はS3互換ストレージ、それ以外はローカルファイルシステムを使う
+func FromEnv() (BlobStore, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "data/attachments"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return NewS3Store(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          region,
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE: %s", os.Getenv("BLOB_STORE"))
+	}
+}